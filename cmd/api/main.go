@@ -9,12 +9,19 @@ import (
 	"back-train/config"
 	"back-train/internal/delivery/http/handler"
 	"back-train/internal/delivery/http/router"
+	"back-train/internal/migration"
 	"back-train/internal/repository"
 	"back-train/internal/usecase"
+	"back-train/pkg/jwtkeys"
+	"back-train/pkg/ldapauth"
+	"back-train/pkg/mailer"
+	"back-train/pkg/passwordhash"
+	"back-train/pkg/passwordpolicy"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -32,32 +39,180 @@ func main() {
 	}
 	defer dbPool.Close()
 
+	// Pastikan schema database sudah sesuai dengan versi migrasi terbaru
+	if cfg.RequireMigrations {
+		migrator, err := migration.NewMigrator(dbPool)
+		if err != nil {
+			log.Fatalf("could not load migrations: %v", err)
+		}
+		pending, err := migrator.Pending(context.Background())
+		if err != nil {
+			log.Fatalf("could not check migration status: %v", err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("database schema is behind: %d pending migration(s), run `go run ./cmd/migrate up` first", len(pending))
+		}
+	}
+
+	// Mailer
+	var mail mailer.Mailer
+	if cfg.MailDriver == "smtp" {
+		mail = mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	} else {
+		mail, err = mailer.NewLogMailer(cfg.MailLogDir, cfg.MailFrom)
+		if err != nil {
+			log.Fatalf("could not initialize log mailer: %v", err)
+		}
+	}
+
+	// Kunci access token
+	jwtKeys, err := loadJWTKeys(cfg)
+	if err != nil {
+		log.Fatalf("could not load JWT keys: %v", err)
+	}
+
+	passwordPolicy, err := buildPasswordPolicy(cfg)
+	if err != nil {
+		log.Fatalf("could not load password policy: %v", err)
+	}
+
+	passwordHasher, err := passwordhash.New(passwordhash.Config{
+		Algorithm: cfg.PasswordHashAlgorithm,
+		Argon2id: passwordhash.Argon2idParams{
+			Memory:      cfg.Argon2MemoryKB,
+			Iterations:  cfg.Argon2Iterations,
+			Parallelism: cfg.Argon2Parallelism,
+			SaltLength:  16,
+			KeyLength:   32,
+		},
+		BcryptCost: cfg.BcryptCost,
+	})
+	if err != nil {
+		log.Fatalf("could not initialize password hasher: %v", err)
+	}
+
 	// Inisialisasi Fiber
-	app := fiber.New()
+	app := fiber.New(fiber.Config{BodyLimit: cfg.UploadMaxMB * 1024 * 1024})
+	// Request ID (header X-Request-ID) ikut dicatat di audit log
+	app.Use(requestid.New())
 	app.Use(logger.New())
 	app.Use(cors.New())
 
 	// Inisialisasi Layers (Dependency Injection)
 	// Repository
 	userRepo := repository.NewUserRepository(dbPool)
+	sessionRepo := repository.NewCachedSessionRepository(repository.NewSessionRepository(dbPool), cfg.SessionCacheTTL)
+	passwordResetRepo := repository.NewPasswordResetRepository(dbPool)
+	mfaRepo := repository.NewMFARepository(dbPool)
+	loginFailureRepo := repository.NewLoginFailureRepository(dbPool)
+	authEventRepo := repository.NewAuthEventRepository(dbPool)
+	apiKeyRepo := repository.NewAPIKeyRepository(dbPool)
+	userIdentityRepo := repository.NewUserIdentityRepository(dbPool)
+	roleRepo := repository.NewRoleRepository(dbPool)
+	alumniClaimRepo := repository.NewAlumniClaimRepository(dbPool)
 	alumniRepo := repository.NewAlumniRepository(dbPool)
 	mahasiswaRepo := repository.NewMahasiswaRepository(dbPool)
 	pekerjaanRepo := repository.NewPekerjaanRepository(dbPool)
+	auditRepo := repository.NewAuditRepository(dbPool)
+	jobRepo := repository.NewJobRepository(dbPool)
+	tracerRepo := repository.NewTracerRepository(dbPool)
+	statsRepo := repository.NewStatsRepository(dbPool)
 
 	// Usecase (Service)
-	authUsecase := usecase.NewAuthUsecase(userRepo, cfg.JWTSecretKey, cfg.JWTExpirationHours)
-	alumniUsecase := usecase.NewAlumniUsecase(alumniRepo)
+	authUsecase := usecase.NewAuthUsecase(userRepo, sessionRepo, passwordResetRepo, mfaRepo, loginFailureRepo, authEventRepo, mail, usecase.AuthOptions{
+		JWTSecret:            cfg.JWTSecretKey,
+		Keys:                 jwtKeys,
+		AccessTokenTTL:       cfg.JWTAccessTTL,
+		RefreshTokenTTL:      cfg.JWTRefreshTTL,
+		PasswordResetTTL:     cfg.PasswordResetTTL,
+		EmailVerificationTTL: cfg.EmailVerificationTTL,
+		ImpersonationTTL:     cfg.ImpersonationTTL,
+		AppBaseURL:           cfg.AppBaseURL,
+		MFAIssuer:            cfg.MFAIssuer,
+		MFAChallengeTTL:      cfg.MFAChallengeTTL,
+		MFARequiredForAdmin:  cfg.MFARequiredForAdmin,
+		Authenticators:       buildAuthenticators(cfg, userRepo, userIdentityRepo, passwordHasher),
+		PasswordPolicy:       passwordPolicy,
+		PasswordHasher:       passwordHasher,
+		Lockout: usecase.LockoutPolicy{
+			MaxAccountFailures: cfg.LoginMaxAccountFailures,
+			MaxIPFailures:      cfg.LoginMaxIPFailures,
+			FailureWindow:      cfg.LoginFailureWindow,
+			BaseLockout:        cfg.LoginLockoutBase,
+			MaxLockout:         cfg.LoginLockoutMax,
+		},
+	})
+	exportOptions := usecase.ExportOptions{
+		PDFMaxRows: cfg.ExportPDFMaxRows,
+		Timeout:    cfg.ExportTimeout,
+	}
+	alumniUsecase := usecase.NewAlumniUsecase(alumniRepo, userRepo, usecase.AlumniImportOptions{
+		MaxRows:   cfg.ImportMaxRows,
+		NIMFormat: cfg.AlumniNIMFormat,
+	}, exportOptions)
 	mahasiswaUsecase := usecase.NewMahasiswaUsecase(mahasiswaRepo)
-	pekerjaanUsecase := usecase.NewPekerjaanUsecase(pekerjaanRepo)
+	pekerjaanUsecase := usecase.NewPekerjaanUsecase(pekerjaanRepo, exportOptions)
+	userUsecase := usecase.NewUserUsecase(userRepo, sessionRepo)
+	roleUsecase := usecase.NewRoleUsecase(roleRepo)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepo, userRepo)
+	auditUsecase := usecase.NewAuditUsecase(auditRepo)
+	historyUsecase := usecase.NewHistoryUsecase(auditRepo, alumniUsecase, mahasiswaUsecase, pekerjaanUsecase)
+	jobOptions := usecase.JobOptions{
+		ArtifactDir:  cfg.JobArtifactDir,
+		MaxAttempts:  cfg.JobMaxAttempts,
+		Workers:      cfg.JobWorkers,
+		PollInterval: cfg.JobPollInterval,
+		Lease:        cfg.JobLease,
+		RetryBase:    cfg.JobRetryBase,
+		RetryMax:     cfg.JobRetryMax,
+		Retention:    cfg.JobRetention,
+	}
+	jobUsecase := usecase.NewJobUsecase(jobRepo, alumniUsecase, pekerjaanUsecase, jobOptions)
+	selfServiceUsecase := usecase.NewSelfServiceUsecase(alumniRepo, alumniClaimRepo, userRepo, alumniUsecase, pekerjaanUsecase, mail, cfg.EmailVerificationTTL, cfg.AppBaseURL)
+	tracerUsecase := usecase.NewTracerUsecase(tracerRepo, alumniRepo, mail, cfg.AppBaseURL)
+	statsUsecase := usecase.NewStatsUsecase(statsRepo, cfg.StatsGraduationMonth)
 
 	// Handler
 	authHandler := handler.NewAuthHandler(authUsecase)
 	alumniHandler := handler.NewAlumniHandler(alumniUsecase)
 	mahasiswaHandler := handler.NewMahasiswaHandler(mahasiswaUsecase)
 	pekerjaanHandler := handler.NewPekerjaanHandler(pekerjaanUsecase)
+	userHandler := handler.NewUserHandler(userUsecase)
+	roleHandler := handler.NewRoleHandler(roleUsecase)
+	selfServiceHandler := handler.NewSelfServiceHandler(selfServiceUsecase)
+	jwksHandler := handler.NewJWKSHandler(jwtKeys)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUsecase)
+	auditHandler := handler.NewAuditHandler(auditUsecase)
+	historyHandler := handler.NewHistoryHandler(historyUsecase)
+	jobHandler := handler.NewJobHandler(jobUsecase)
+	tracerHandler := handler.NewTracerHandler(tracerUsecase)
+	statsHandler := handler.NewStatsHandler(statsUsecase)
+
+	// SSO hanya aktif jika issuer dikonfigurasi
+	var oidcHandler *handler.OIDCHandler
+	if cfg.OIDCIssuerURL != "" {
+		oidcUsecase := usecase.NewOIDCUsecase(userRepo, userIdentityRepo, authUsecase, usecase.OIDCOptions{
+			ProviderName: "oidc",
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+			GroupsClaim:  cfg.OIDCGroupsClaim,
+			RoleMappings: cfg.OIDCRoleMappings,
+			DefaultRole:  cfg.OIDCDefaultRole,
+			StateSecret:  cfg.JWTSecretKey,
+		})
+		oidcHandler = handler.NewOIDCHandler(oidcUsecase, cfg.OIDCPostLoginRedirectURL)
+	}
 
 	// Setup Router
-	router.SetupRoutes(app, authHandler, alumniHandler, mahasiswaHandler, pekerjaanHandler, cfg)
+	router.SetupRoutes(app, authHandler, alumniHandler, mahasiswaHandler, pekerjaanHandler, userHandler, roleHandler, selfServiceHandler, jwksHandler, apiKeyHandler, oidcHandler, auditHandler, historyHandler, jobHandler, tracerHandler, statsHandler, authUsecase, apiKeyUsecase, jwtKeys)
+
+	// Worker job background di proses yang sama; JOB_WORKERS=0 jika memakai cmd/worker saja
+	if cfg.JobWorkers > 0 {
+		go usecase.NewJobWorker(jobRepo, alumniUsecase, pekerjaanUsecase, jobOptions).Run(context.Background())
+	}
 
 	// Start Server
 	serverAddr := fmt.Sprintf(":%s", cfg.ServerPort)
@@ -68,3 +223,77 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// loadJWTKeys menyiapkan kunci access token sesuai JWT_ALGORITHM
+func loadJWTKeys(cfg *config.Config) (*jwtkeys.KeySet, error) {
+	if cfg.JWTAlgorithm == "HS256" {
+		return jwtkeys.NewHMACKeySet(cfg.JWTKeyID, cfg.JWTSecretKey), nil
+	}
+
+	signing, err := jwtkeys.LoadPrivateKey(cfg.JWTPrivateKeyFile, cfg.JWTKeyID, cfg.JWTAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var verification []*jwtkeys.Key
+	for kid, path := range cfg.JWTVerificationKeys {
+		key, err := jwtkeys.LoadPublicKey(path, kid)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+	return jwtkeys.NewKeySet(signing, verification...)
+}
+
+// buildAuthenticators menyusun backend login sesuai urutan AUTH_BACKENDS
+func buildAuthenticators(cfg *config.Config, userRepo repository.UserRepository, identityRepo repository.UserIdentityRepository, hasher *passwordhash.Hasher) []usecase.Authenticator {
+	var authenticators []usecase.Authenticator
+	for _, backend := range cfg.AuthBackends {
+		switch backend {
+		case "local":
+			authenticators = append(authenticators, usecase.NewPasswordAuthenticator(userRepo, hasher))
+		case "ldap":
+			directory := ldapauth.New(ldapauth.Config{
+				URL:                cfg.LDAPURL,
+				StartTLS:           cfg.LDAPStartTLS,
+				InsecureSkipVerify: cfg.LDAPInsecureSkipVerify,
+				Timeout:            cfg.LDAPTimeout,
+				BindDN:             cfg.LDAPBindDN,
+				BindPassword:       cfg.LDAPBindPassword,
+				BaseDN:             cfg.LDAPBaseDN,
+				UserFilter:         cfg.LDAPUserFilter,
+				EmailAttribute:     cfg.LDAPEmailAttribute,
+				GroupAttribute:     cfg.LDAPGroupAttribute,
+				GroupBaseDN:        cfg.LDAPGroupBaseDN,
+				GroupFilter:        cfg.LDAPGroupFilter,
+			})
+			authenticators = append(authenticators, usecase.NewLDAPAuthenticator(directory, userRepo, identityRepo, usecase.LDAPOptions{
+				RoleMappings: cfg.LDAPRoleMappings,
+				DefaultRole:  cfg.LDAPDefaultRole,
+			}))
+		}
+	}
+	return authenticators
+}
+
+// buildPasswordPolicy menyusun kebijakan password dan membuka daftar password bocor jika dikonfigurasi
+func buildPasswordPolicy(cfg *config.Config) (*passwordpolicy.Policy, error) {
+	policy := &passwordpolicy.Policy{
+		MinLength:     cfg.PasswordMinLength,
+		MaxLength:     cfg.PasswordMaxLength,
+		RequireUpper:  cfg.PasswordRequireUpper,
+		RequireLower:  cfg.PasswordRequireLower,
+		RequireDigit:  cfg.PasswordRequireDigit,
+		RequireSymbol: cfg.PasswordRequireSymbol,
+		RejectEmail:   cfg.PasswordRejectEmail,
+	}
+	if cfg.BreachedPasswordsPath != "" {
+		breached, err := passwordpolicy.OpenBreached(cfg.BreachedPasswordsPath)
+		if err != nil {
+			return nil, err
+		}
+		policy.BreachedList = breached
+	}
+	return policy, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"back-train/config"
+	"back-train/internal/migration"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const usage = `Usage: migrate <command> [argument]
+
+Commands:
+  up             apply all pending migrations
+  down [N]       roll back the last N applied migrations (default 1)
+  status         list migrations and whether they are applied
+  to <VERSION>   migrate up or down to exactly VERSION (0 = empty schema)`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	// Load Konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Koneksi Database
+	dbPool, err := pgxpool.Connect(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Unable to connect to database: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	migrator, err := migration.NewMigrator(dbPool)
+	if err != nil {
+		log.Fatalf("could not load migrations: %v", err)
+	}
+
+	switch os.Args[1] {
+	case "up":
+		n, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		log.Printf("applied %d migration(s)", n)
+
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil {
+				log.Fatalf("invalid number of steps: %v", err)
+			}
+		}
+		n, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+		log.Printf("rolled back %d migration(s)", n)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("migrate status: %v", err)
+		}
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d  %-40s %s\n", s.Version, s.Name, appliedAt)
+		}
+
+	case "to":
+		if len(os.Args) < 3 {
+			log.Fatal("missing target version")
+		}
+		version, err := strconv.ParseInt(os.Args[2], 10, 64)
+		if err != nil {
+			log.Fatalf("invalid version: %v", err)
+		}
+		n, err := migrator.To(ctx, version)
+		if err != nil {
+			log.Fatalf("migrate to %d: %v", version, err)
+		}
+		log.Printf("ran %d migration(s), schema is now at version %d", n, version)
+
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,42 @@
+// Command mockidp menjalankan identity provider OpenID Connect sederhana untuk development dan
+// pengujian login SSO secara lokal. Jangan dipakai di production: tidak ada password sama sekali,
+// siapa pun bisa login sebagai email dan grup apa pun lewat form di halaman /authorize.
+//
+//	go run ./cmd/mockidp
+//
+// Lalu set OIDC_ISSUER_URL=http://localhost:9000, OIDC_CLIENT_ID=back-train, OIDC_CLIENT_SECRET=secret.
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"back-train/pkg/mockidp"
+)
+
+func main() {
+	addr := getEnv("MOCK_IDP_ADDR", ":9000")
+	cfg := mockidp.Config{
+		Issuer:        getEnv("MOCK_IDP_ISSUER", "http://localhost:9000"),
+		ClientID:      getEnv("MOCK_IDP_CLIENT_ID", "back-train"),
+		ClientSecret:  getEnv("MOCK_IDP_CLIENT_SECRET", "secret"),
+		DefaultEmail:  getEnv("MOCK_IDP_EMAIL", "alumni@example.com"),
+		DefaultGroups: getEnv("MOCK_IDP_GROUPS", ""),
+	}
+
+	server, err := mockidp.New(cfg)
+	if err != nil {
+		log.Fatalf("could not create mock idp: %v", err)
+	}
+
+	log.Printf("Mock IdP %s listening on %s (client_id=%s)", cfg.Issuer, addr, cfg.ClientID)
+	log.Fatal(http.ListenAndServe(addr, server))
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
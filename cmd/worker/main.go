@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"back-train/config"
+	"back-train/internal/migration"
+	"back-train/internal/repository"
+	"back-train/internal/usecase"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Worker job background tanpa HTTP server. Bisa dijalankan beberapa instance sekaligus dan
+// berdampingan dengan worker di proses API; JOB_ARTIFACT_DIR harus menunjuk ke direktori yang sama.
+func main() {
+	// Load Konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	// Berhenti dengan rapi saat SIGINT/SIGTERM: job yang sedang berjalan dikembalikan ke antrian
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Koneksi Database
+	dbPool, err := pgxpool.Connect(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Unable to connect to database: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	if cfg.RequireMigrations {
+		migrator, err := migration.NewMigrator(dbPool)
+		if err != nil {
+			log.Fatalf("could not load migrations: %v", err)
+		}
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			log.Fatalf("could not check migration status: %v", err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("database schema is behind: %d pending migration(s), run `go run ./cmd/migrate up` first", len(pending))
+		}
+	}
+
+	userRepo := repository.NewUserRepository(dbPool)
+	alumniRepo := repository.NewAlumniRepository(dbPool)
+	pekerjaanRepo := repository.NewPekerjaanRepository(dbPool)
+	jobRepo := repository.NewJobRepository(dbPool)
+
+	exportOptions := usecase.ExportOptions{
+		PDFMaxRows: cfg.ExportPDFMaxRows,
+		Timeout:    cfg.ExportTimeout,
+	}
+	alumniUsecase := usecase.NewAlumniUsecase(alumniRepo, userRepo, usecase.AlumniImportOptions{
+		MaxRows:   cfg.ImportMaxRows,
+		NIMFormat: cfg.AlumniNIMFormat,
+	}, exportOptions)
+	pekerjaanUsecase := usecase.NewPekerjaanUsecase(pekerjaanRepo, exportOptions)
+
+	// Minimal satu worker walaupun JOB_WORKERS=0 (yang menonaktifkan worker di proses API)
+	worker := usecase.NewJobWorker(jobRepo, alumniUsecase, pekerjaanUsecase, usecase.JobOptions{
+		ArtifactDir:  cfg.JobArtifactDir,
+		MaxAttempts:  cfg.JobMaxAttempts,
+		Workers:      max(cfg.JobWorkers, 1),
+		PollInterval: cfg.JobPollInterval,
+		Lease:        cfg.JobLease,
+		RetryBase:    cfg.JobRetryBase,
+		RetryMax:     cfg.JobRetryMax,
+		Retention:    cfg.JobRetention,
+	})
+	worker.Run(ctx)
+}
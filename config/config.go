@@ -3,17 +3,127 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// defaultJWTSecret hanya boleh dipakai saat APP_ENV=development
+const defaultJWTSecret = "ApalahR4has!a!N!"
+
 type Config struct {
-	DatabaseURL        string
-	ServerPort         string
-	JWTSecretKey       string
-	JWTExpirationHours time.Duration
+	AppEnv            string
+	DatabaseURL       string
+	ServerPort        string
+	JWTSecretKey      string
+	JWTAccessTTL      time.Duration
+	JWTRefreshTTL     time.Duration
+	RequireMigrations bool
+	SessionCacheTTL   time.Duration // lama status session di-cache middleware auth
+
+	// Access token: HS256 memakai JWTSecretKey, RS256/EdDSA memakai kunci PEM
+	JWTAlgorithm        string
+	JWTKeyID            string
+	JWTPrivateKeyFile   string
+	JWTVerificationKeys map[string]string // kid -> file public key lama yang masih diterima
+
+	AppBaseURL           string
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+	ImpersonationTTL     time.Duration // masa berlaku token /api/admin/impersonate
+
+	// Two-factor authentication
+	MFAIssuer           string
+	MFAChallengeTTL     time.Duration
+	MFARequiredForAdmin bool
+
+	// OpenID Connect SSO; nonaktif jika OIDCIssuerURL kosong
+	OIDCIssuerURL            string
+	OIDCClientID             string
+	OIDCClientSecret         string
+	OIDCRedirectURL          string
+	OIDCScopes               []string
+	OIDCGroupsClaim          string
+	OIDCRoleMappings         map[string]string // grup IdP -> role lokal
+	OIDCDefaultRole          string
+	OIDCPostLoginRedirectURL string
+
+	// Kebijakan password untuk register, reset, dan ganti password
+	PasswordMinLength     int
+	PasswordMaxLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	PasswordRejectEmail   bool
+	BreachedPasswordsPath string // file atau direktori daftar hash password bocor; kosong = nonaktif
+
+	PasswordHashAlgorithm string // argon2id atau bcrypt untuk hash baru; hash lama di-upgrade saat login
+	BcryptCost            int
+	Argon2MemoryKB        uint32
+	Argon2Iterations      uint32
+	Argon2Parallelism     uint8
+
+	// Backend login yang dicoba berurutan: "local" (hash password di tabel users) dan/atau "ldap"
+	AuthBackends []string
+
+	// LDAP/Active Directory
+	LDAPURL                string
+	LDAPStartTLS           bool
+	LDAPInsecureSkipVerify bool
+	LDAPTimeout            time.Duration
+	LDAPBindDN             string
+	LDAPBindPassword       string
+	LDAPBaseDN             string
+	LDAPUserFilter         string
+	LDAPEmailAttribute     string
+	LDAPGroupAttribute     string
+	LDAPGroupBaseDN        string
+	LDAPGroupFilter        string
+	LDAPRoleMappings       map[string]string // CN grup -> role lokal
+	LDAPDefaultRole        string
+
+	// Login lockout
+	LoginMaxAccountFailures int
+	LoginMaxIPFailures      int
+	LoginFailureWindow      time.Duration
+	LoginLockoutBase        time.Duration
+	LoginLockoutMax         time.Duration
+
+	// Import alumni dari CSV/XLSX
+	UploadMaxMB     int            // batas ukuran body request, termasuk file import
+	ImportMaxRows   int            // jumlah baris data maksimal per file
+	AlumniNIMFormat *regexp.Regexp // format NIM yang diterima saat import
+
+	// Export alumni/pekerjaan ke CSV/XLSX/PDF
+	ExportPDFMaxRows int           // PDF disusun di memori, jadi jumlah barisnya dibatasi; 0 = tanpa batas
+	ExportTimeout    time.Duration // batas waktu export langsung; job export tidak terpengaruh
+
+	// Job background untuk import/export besar
+	JobWorkers      int    // jumlah worker di proses API; 0 jika job hanya dijalankan cmd/worker
+	JobArtifactDir  string // file upload dan hasil job; harus bisa diakses API dan worker
+	JobMaxAttempts  int
+	JobPollInterval time.Duration
+	JobLease        time.Duration // job yang tidak diperpanjang selama ini dianggap ditinggal worker-nya
+	JobRetryBase    time.Duration // jeda retry pertama, berlipat dua sampai JobRetryMax
+	JobRetryMax     time.Duration
+	JobRetention    time.Duration // job selesai beserta file-nya dihapus setelah ini; 0 = tidak pernah
+
+	// Statistik (/api/stats). Alumni hanya menyimpan tahun_lulus, jadi masa tunggu kerja dihitung
+	// dari bulan ini pada tahun lulus
+	StatsGraduationMonth int
+
+	// Mail
+	MailDriver   string // "smtp" atau "log"
+	MailFrom     string
+	MailLogDir   string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
 }
 
 func LoadConfig() (*Config, error) {
@@ -33,19 +143,368 @@ func LoadConfig() (*Config, error) {
 		dbUser, dbPassword, dbHost, dbPort, dbName, dbSSLMode)
 
 	serverPort := getEnv("SERVER_PORT", "4000")
-	jwtSecret := getEnv("JWT_SECRET_KEY", "ApalahR4has!a!N!")
-	jwtExpHoursStr := getEnv("JWT_EXPIRATION_HOURS", "72")
+	// Default production agar server yang lupa di-set APP_ENV tidak berjalan dengan secret bawaan
+	appEnv := getEnv("APP_ENV", "production")
+	jwtSecret := getEnv("JWT_SECRET_KEY", defaultJWTSecret)
+	// Secret ini juga dipakai untuk menandatangani link email dan challenge MFA, jadi tetap wajib diganti
+	if appEnv != "development" && jwtSecret == defaultJWTSecret {
+		return nil, fmt.Errorf("JWT_SECRET_KEY must be changed from the default when APP_ENV=%s", appEnv)
+	}
+
+	jwtAlgorithm := getEnv("JWT_ALGORITHM", "HS256")
+	jwtPrivateKeyFile := getEnv("JWT_PRIVATE_KEY_FILE", "")
+	switch jwtAlgorithm {
+	case "HS256":
+	case "RS256", "EdDSA":
+		if jwtPrivateKeyFile == "" {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for JWT_ALGORITHM=%s", jwtAlgorithm)
+		}
+	default:
+		return nil, fmt.Errorf("invalid JWT_ALGORITHM %q, use HS256, RS256 or EdDSA", jwtAlgorithm)
+	}
+
+	jwtVerificationKeys, err := parsePairs(getEnv("JWT_VERIFICATION_KEYS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_VERIFICATION_KEYS: %w", err)
+	}
+	jwtAccessMinutesStr := getEnv("JWT_ACCESS_TTL_MINUTES", "15")
+	jwtRefreshHoursStr := getEnv("JWT_REFRESH_TTL_HOURS", "720")
+
+	jwtAccessMinutes, err := strconv.Atoi(jwtAccessMinutesStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_ACCESS_TTL_MINUTES: %w", err)
+	}
+
+	jwtRefreshHours, err := strconv.Atoi(jwtRefreshHoursStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_REFRESH_TTL_HOURS: %w", err)
+	}
+
+	requireMigrations, err := strconv.ParseBool(getEnv("DB_REQUIRE_MIGRATIONS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_REQUIRE_MIGRATIONS: %w", err)
+	}
+
+	sessionCacheSeconds, err := strconv.Atoi(getEnv("SESSION_CACHE_TTL_SECONDS", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SESSION_CACHE_TTL_SECONDS: %w", err)
+	}
+
+	passwordResetMinutes, err := strconv.Atoi(getEnv("PASSWORD_RESET_TTL_MINUTES", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_RESET_TTL_MINUTES: %w", err)
+	}
+
+	impersonationMinutes, err := strconv.Atoi(getEnv("IMPERSONATION_TTL_MINUTES", "15"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMPERSONATION_TTL_MINUTES: %w", err)
+	}
+
+	emailVerificationHours, err := strconv.Atoi(getEnv("EMAIL_VERIFICATION_TTL_HOURS", "48"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EMAIL_VERIFICATION_TTL_HOURS: %w", err)
+	}
+
+	mfaChallengeMinutes, err := strconv.Atoi(getEnv("MFA_CHALLENGE_TTL_MINUTES", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MFA_CHALLENGE_TTL_MINUTES: %w", err)
+	}
+
+	mfaRequiredForAdmin, err := strconv.ParseBool(getEnv("MFA_REQUIRED_FOR_ADMIN", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MFA_REQUIRED_FOR_ADMIN: %w", err)
+	}
+
+	oidcIssuerURL := getEnv("OIDC_ISSUER_URL", "")
+	if oidcIssuerURL != "" && getEnv("OIDC_CLIENT_ID", "") == "" {
+		return nil, fmt.Errorf("OIDC_CLIENT_ID is required when OIDC_ISSUER_URL is set")
+	}
+
+	oidcRoleMappings, err := parsePairs(getEnv("OIDC_ROLE_MAPPINGS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC_ROLE_MAPPINGS: %w", err)
+	}
+
+	passwordMinLength, err := strconv.Atoi(getEnv("PASSWORD_MIN_LENGTH", "8"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_MIN_LENGTH: %w", err)
+	}
+
+	passwordMaxLength, err := strconv.Atoi(getEnv("PASSWORD_MAX_LENGTH", "72"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_MAX_LENGTH: %w", err)
+	}
+
+	passwordRequireUpper, err := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_UPPER", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_REQUIRE_UPPER: %w", err)
+	}
+
+	passwordRequireLower, err := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_LOWER", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_REQUIRE_LOWER: %w", err)
+	}
+
+	passwordRequireDigit, err := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_DIGIT", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_REQUIRE_DIGIT: %w", err)
+	}
+
+	passwordRequireSymbol, err := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_SYMBOL", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_REQUIRE_SYMBOL: %w", err)
+	}
+
+	passwordRejectEmail, err := strconv.ParseBool(getEnv("PASSWORD_REJECT_EMAIL", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_REJECT_EMAIL: %w", err)
+	}
+
+	passwordHashAlgorithm := getEnv("PASSWORD_HASH_ALGORITHM", "argon2id")
+	if passwordHashAlgorithm != "argon2id" && passwordHashAlgorithm != "bcrypt" {
+		return nil, fmt.Errorf("invalid PASSWORD_HASH_ALGORITHM %q, use argon2id or bcrypt", passwordHashAlgorithm)
+	}
+
+	bcryptCost, err := strconv.Atoi(getEnv("BCRYPT_COST", "14"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BCRYPT_COST: %w", err)
+	}
+
+	argon2MemoryKB, err := strconv.ParseUint(getEnv("ARGON2_MEMORY_KB", "65536"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ARGON2_MEMORY_KB: %w", err)
+	}
+
+	argon2Iterations, err := strconv.ParseUint(getEnv("ARGON2_ITERATIONS", "3"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ARGON2_ITERATIONS: %w", err)
+	}
+
+	argon2Parallelism, err := strconv.ParseUint(getEnv("ARGON2_PARALLELISM", "2"), 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ARGON2_PARALLELISM: %w", err)
+	}
+
+	authBackends := strings.FieldsFunc(getEnv("AUTH_BACKENDS", "local"), func(r rune) bool { return r == ',' || r == ' ' })
+	for _, backend := range authBackends {
+		if backend != "local" && backend != "ldap" {
+			return nil, fmt.Errorf("invalid AUTH_BACKENDS entry %q, use local or ldap", backend)
+		}
+		if backend == "ldap" && (getEnv("LDAP_URL", "") == "" || getEnv("LDAP_BASE_DN", "") == "") {
+			return nil, fmt.Errorf("LDAP_URL and LDAP_BASE_DN are required when AUTH_BACKENDS includes ldap")
+		}
+	}
+
+	ldapStartTLS, err := strconv.ParseBool(getEnv("LDAP_START_TLS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP_START_TLS: %w", err)
+	}
+
+	ldapInsecureSkipVerify, err := strconv.ParseBool(getEnv("LDAP_INSECURE_SKIP_VERIFY", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP_INSECURE_SKIP_VERIFY: %w", err)
+	}
+
+	ldapTimeoutSeconds, err := strconv.Atoi(getEnv("LDAP_TIMEOUT_SECONDS", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP_TIMEOUT_SECONDS: %w", err)
+	}
+
+	ldapRoleMappings, err := parsePairs(getEnv("LDAP_ROLE_MAPPINGS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP_ROLE_MAPPINGS: %w", err)
+	}
 
-	jwtExpHours, err := strconv.Atoi(jwtExpHoursStr)
+	loginMaxAccountFailures, err := strconv.Atoi(getEnv("LOGIN_MAX_ACCOUNT_FAILURES", "5"))
 	if err != nil {
-		return nil, fmt.Errorf("invalid JWT_EXPIRATION_HOURS: %w", err)
+		return nil, fmt.Errorf("invalid LOGIN_MAX_ACCOUNT_FAILURES: %w", err)
+	}
+
+	loginMaxIPFailures, err := strconv.Atoi(getEnv("LOGIN_MAX_IP_FAILURES", "20"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_MAX_IP_FAILURES: %w", err)
+	}
+
+	loginFailureWindowMinutes, err := strconv.Atoi(getEnv("LOGIN_FAILURE_WINDOW_MINUTES", "15"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_FAILURE_WINDOW_MINUTES: %w", err)
+	}
+
+	loginLockoutBaseSeconds, err := strconv.Atoi(getEnv("LOGIN_LOCKOUT_BASE_SECONDS", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_BASE_SECONDS: %w", err)
+	}
+
+	loginLockoutMaxMinutes, err := strconv.Atoi(getEnv("LOGIN_LOCKOUT_MAX_MINUTES", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_MAX_MINUTES: %w", err)
+	}
+
+	uploadMaxMB, err := strconv.Atoi(getEnv("UPLOAD_MAX_MB", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid UPLOAD_MAX_MB: %w", err)
+	}
+
+	importMaxRows, err := strconv.Atoi(getEnv("IMPORT_MAX_ROWS", "10000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMPORT_MAX_ROWS: %w", err)
+	}
+
+	exportPDFMaxRows, err := strconv.Atoi(getEnv("EXPORT_PDF_MAX_ROWS", "5000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXPORT_PDF_MAX_ROWS: %w", err)
+	}
+
+	exportTimeoutMinutes, err := strconv.Atoi(getEnv("EXPORT_TIMEOUT_MINUTES", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXPORT_TIMEOUT_MINUTES: %w", err)
+	}
+
+	alumniNIMFormat, err := regexp.Compile(getEnv("ALUMNI_NIM_FORMAT", `^[0-9A-Za-z]{5,20}$`))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ALUMNI_NIM_FORMAT: %w", err)
+	}
+
+	jobWorkers, err := strconv.Atoi(getEnv("JOB_WORKERS", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_WORKERS: %w", err)
+	}
+	if jobWorkers < 0 {
+		return nil, fmt.Errorf("JOB_WORKERS must not be negative")
+	}
+
+	jobMaxAttempts, err := strconv.Atoi(getEnv("JOB_MAX_ATTEMPTS", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_MAX_ATTEMPTS: %w", err)
+	}
+	if jobMaxAttempts < 1 {
+		return nil, fmt.Errorf("JOB_MAX_ATTEMPTS must be at least 1")
+	}
+
+	jobPollSeconds, err := strconv.Atoi(getEnv("JOB_POLL_SECONDS", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_POLL_SECONDS: %w", err)
+	}
+
+	jobLeaseSeconds, err := strconv.Atoi(getEnv("JOB_LEASE_SECONDS", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_LEASE_SECONDS: %w", err)
+	}
+	if jobLeaseSeconds < 10 {
+		return nil, fmt.Errorf("JOB_LEASE_SECONDS must be at least 10")
+	}
+
+	jobRetryBaseSeconds, err := strconv.Atoi(getEnv("JOB_RETRY_BASE_SECONDS", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_RETRY_BASE_SECONDS: %w", err)
+	}
+
+	jobRetryMaxMinutes, err := strconv.Atoi(getEnv("JOB_RETRY_MAX_MINUTES", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_RETRY_MAX_MINUTES: %w", err)
+	}
+
+	jobRetentionHours, err := strconv.Atoi(getEnv("JOB_RETENTION_HOURS", "168"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_RETENTION_HOURS: %w", err)
+	}
+
+	statsGraduationMonth, err := strconv.Atoi(getEnv("STATS_GRADUATION_MONTH", "1"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STATS_GRADUATION_MONTH: %w", err)
+	}
+	if statsGraduationMonth < 1 || statsGraduationMonth > 12 {
+		return nil, fmt.Errorf("STATS_GRADUATION_MONTH must be between 1 and 12")
+	}
+
+	mailDriver := getEnv("MAIL_DRIVER", "log")
+	if mailDriver != "smtp" && mailDriver != "log" {
+		return nil, fmt.Errorf("invalid MAIL_DRIVER %q, use smtp or log", mailDriver)
 	}
 
 	return &Config{
-		DatabaseURL:        databaseURL,
-		ServerPort:         serverPort,
-		JWTSecretKey:       jwtSecret,
-		JWTExpirationHours: time.Duration(jwtExpHours) * time.Hour,
+		AppEnv:                   appEnv,
+		DatabaseURL:              databaseURL,
+		ServerPort:               serverPort,
+		JWTSecretKey:             jwtSecret,
+		JWTAccessTTL:             time.Duration(jwtAccessMinutes) * time.Minute,
+		JWTRefreshTTL:            time.Duration(jwtRefreshHours) * time.Hour,
+		RequireMigrations:        requireMigrations,
+		SessionCacheTTL:          time.Duration(sessionCacheSeconds) * time.Second,
+		JWTAlgorithm:             jwtAlgorithm,
+		JWTKeyID:                 getEnv("JWT_KEY_ID", "primary"),
+		JWTPrivateKeyFile:        jwtPrivateKeyFile,
+		JWTVerificationKeys:      jwtVerificationKeys,
+		AppBaseURL:               getEnv("APP_BASE_URL", "http://localhost:3000"),
+		PasswordResetTTL:         time.Duration(passwordResetMinutes) * time.Minute,
+		ImpersonationTTL:         time.Duration(impersonationMinutes) * time.Minute,
+		EmailVerificationTTL:     time.Duration(emailVerificationHours) * time.Hour,
+		MFAIssuer:                getEnv("MFA_ISSUER", "Alumni Tracer"),
+		MFAChallengeTTL:          time.Duration(mfaChallengeMinutes) * time.Minute,
+		MFARequiredForAdmin:      mfaRequiredForAdmin,
+		OIDCIssuerURL:            oidcIssuerURL,
+		OIDCClientID:             getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:         getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:          getEnv("OIDC_REDIRECT_URL", "http://localhost:"+serverPort+"/api/auth/oidc/callback"),
+		OIDCScopes:               strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+		OIDCGroupsClaim:          getEnv("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCRoleMappings:         oidcRoleMappings,
+		OIDCDefaultRole:          getEnv("OIDC_DEFAULT_ROLE", "user"),
+		OIDCPostLoginRedirectURL: getEnv("OIDC_POST_LOGIN_REDIRECT_URL", ""),
+		PasswordMinLength:        passwordMinLength,
+		PasswordMaxLength:        passwordMaxLength,
+		PasswordRequireUpper:     passwordRequireUpper,
+		PasswordRequireLower:     passwordRequireLower,
+		PasswordRequireDigit:     passwordRequireDigit,
+		PasswordRequireSymbol:    passwordRequireSymbol,
+		PasswordRejectEmail:      passwordRejectEmail,
+		BreachedPasswordsPath:    getEnv("BREACHED_PASSWORDS_PATH", ""),
+		PasswordHashAlgorithm:    passwordHashAlgorithm,
+		BcryptCost:               bcryptCost,
+		Argon2MemoryKB:           uint32(argon2MemoryKB),
+		Argon2Iterations:         uint32(argon2Iterations),
+		Argon2Parallelism:        uint8(argon2Parallelism),
+		AuthBackends:             authBackends,
+		LDAPURL:                  getEnv("LDAP_URL", ""),
+		LDAPStartTLS:             ldapStartTLS,
+		LDAPInsecureSkipVerify:   ldapInsecureSkipVerify,
+		LDAPTimeout:              time.Duration(ldapTimeoutSeconds) * time.Second,
+		LDAPBindDN:               getEnv("LDAP_BIND_DN", ""),
+		LDAPBindPassword:         getEnv("LDAP_BIND_PASSWORD", ""),
+		LDAPBaseDN:               getEnv("LDAP_BASE_DN", ""),
+		LDAPUserFilter:           getEnv("LDAP_USER_FILTER", "(mail=%s)"),
+		LDAPEmailAttribute:       getEnv("LDAP_EMAIL_ATTRIBUTE", "mail"),
+		LDAPGroupAttribute:       getEnv("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+		LDAPGroupBaseDN:          getEnv("LDAP_GROUP_BASE_DN", ""),
+		LDAPGroupFilter:          getEnv("LDAP_GROUP_FILTER", ""),
+		LDAPRoleMappings:         ldapRoleMappings,
+		LDAPDefaultRole:          getEnv("LDAP_DEFAULT_ROLE", "user"),
+		LoginMaxAccountFailures:  loginMaxAccountFailures,
+		LoginMaxIPFailures:       loginMaxIPFailures,
+		LoginFailureWindow:       time.Duration(loginFailureWindowMinutes) * time.Minute,
+		LoginLockoutBase:         time.Duration(loginLockoutBaseSeconds) * time.Second,
+		LoginLockoutMax:          time.Duration(loginLockoutMaxMinutes) * time.Minute,
+		UploadMaxMB:              uploadMaxMB,
+		ImportMaxRows:            importMaxRows,
+		AlumniNIMFormat:          alumniNIMFormat,
+		ExportPDFMaxRows:         exportPDFMaxRows,
+		ExportTimeout:            time.Duration(exportTimeoutMinutes) * time.Minute,
+		JobWorkers:               jobWorkers,
+		JobArtifactDir:           getEnv("JOB_ARTIFACT_DIR", "tmp/jobs"),
+		JobMaxAttempts:           jobMaxAttempts,
+		JobPollInterval:          time.Duration(jobPollSeconds) * time.Second,
+		JobLease:                 time.Duration(jobLeaseSeconds) * time.Second,
+		JobRetryBase:             time.Duration(jobRetryBaseSeconds) * time.Second,
+		JobRetryMax:              time.Duration(jobRetryMaxMinutes) * time.Minute,
+		JobRetention:             time.Duration(jobRetentionHours) * time.Hour,
+		StatsGraduationMonth:     statsGraduationMonth,
+		MailDriver:               mailDriver,
+		MailFrom:                 getEnv("MAIL_FROM", "no-reply@localhost"),
+		MailLogDir:               getEnv("MAIL_LOG_DIR", ""),
+		SMTPHost:                 getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:                 getEnv("SMTP_PORT", "587"),
+		SMTPUsername:             getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:             getEnv("SMTP_PASSWORD", ""),
 	}, nil
 }
 
@@ -55,3 +514,20 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// parsePairs membaca daftar "key=value" yang dipisahkan koma
+func parsePairs(value string) (map[string]string, error) {
+	pairs := map[string]string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, val, found := strings.Cut(item, "=")
+		if !found || key == "" || val == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", item)
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return pairs, nil
+}
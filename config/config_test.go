@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// unsetEnv menghapus variabel selama test dan mengembalikannya setelah selesai
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+	if old, ok := os.LookupEnv(key); ok {
+		t.Cleanup(func() { os.Setenv(key, old) })
+	}
+	os.Unsetenv(key)
+}
+
+func TestLoadConfigDefaultSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		appEnv  string // kosong berarti APP_ENV tidak di-set
+		secret  string // kosong berarti JWT_SECRET_KEY tidak di-set
+		wantEnv string
+		wantErr bool
+	}{
+		{"unset env defaults to production and rejects default secret", "", "", "", true},
+		{"production rejects default secret", "production", "", "", true},
+		{"explicit default secret in staging", "staging", defaultJWTSecret, "", true},
+		{"development allows default secret", "development", "", "development", false},
+		{"unset env with custom secret", "", "a-real-secret-value", "production", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetEnv(t, "APP_ENV")
+			unsetEnv(t, "JWT_SECRET_KEY")
+			if tt.appEnv != "" {
+				t.Setenv("APP_ENV", tt.appEnv)
+			}
+			if tt.secret != "" {
+				t.Setenv("JWT_SECRET_KEY", tt.secret)
+			}
+
+			cfg, err := LoadConfig()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "JWT_SECRET_KEY") {
+					t.Fatalf("err = %v, want JWT_SECRET_KEY error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.AppEnv != tt.wantEnv {
+				t.Fatalf("AppEnv = %q, want %q", cfg.AppEnv, tt.wantEnv)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// accountErrorStatus memetakan error ganti password/email ke HTTP status yang sesuai
+func accountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrWrongPassword):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidEmail), errors.Is(err, domain.ErrEmailUnchanged),
+		errors.Is(err, domain.ErrInvalidEmailChange):
+		return fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrEmailTaken):
+		return fiber.StatusConflict
+	case errors.Is(err, domain.ErrUserNotFound):
+		return fiber.StatusNotFound
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func accountErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrAccountLocked) {
+		return lockedResponse(c, err)
+	}
+	if errors.Is(err, domain.ErrWeakPassword) {
+		return passwordPolicyResponse(c, err)
+	}
+	return c.Status(accountErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+}
+
+func (h *AuthHandler) ChangeMyPassword(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	if err := h.authUsecase.ChangePassword(c.Context(), userID, currentSessionID(c), &req, clientInfo(c)); err != nil {
+		return accountErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Password has been changed, other sessions have been signed out"})
+}
+
+func (h *AuthHandler) ChangeMyEmail(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.ChangeEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	if err := h.authUsecase.RequestEmailChange(c.Context(), userID, currentSessionID(c), &req, clientInfo(c)); err != nil {
+		return accountErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "A confirmation link has been sent to the new email address"})
+}
+
+// ConfirmEmailChange tidak membutuhkan login karena link bisa dibuka dari perangkat lain
+func (h *AuthHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	var req domain.ConfirmEmailChangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	if err := h.authUsecase.ConfirmEmailChange(c.Context(), req.Token); err != nil {
+		return accountErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Email has been changed, other sessions have been signed out"})
+}
@@ -4,6 +4,8 @@ package handler
 import (
 	"back-train/internal/domain"
 	"back-train/internal/usecase"
+	"back-train/pkg/spreadsheet"
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -23,7 +25,7 @@ func (h *AlumniHandler) CreateAlumni(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
 	}
 
-	alumni, err := h.alumniUsecase.CreateAlumni(c.Context(), &req)
+	alumni, err := h.alumniUsecase.CreateAlumni(c.Context(), &req, auditMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -31,27 +33,10 @@ func (h *AlumniHandler) CreateAlumni(c *fiber.Ctx) error {
 }
 
 func (h *AlumniHandler) GetAllAlumni(c *fiber.Ctx) error {
-	// Parse query parameters
-	page, _ := strconv.Atoi(c.Query("page", "1"))
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	sort := c.Query("sort", "created_at:desc") // contoh: "nama:asc"
-	search := c.Query("search", "")
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
-	}
-	if limit > 100 { // Batasi limit untuk mencegah query yang berlebihan
-		limit = 100
-	}
-
-	params := domain.PaginationParams{
-		Page:   page,
-		Limit:  limit,
-		Sort:   sort,
-		Search: search,
+	// Parse query parameters, contoh sort: "nama:asc"
+	params, err := listParams(c, "created_at:desc", domain.AlumniFilters)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	result, err := h.alumniUsecase.GetAllAlumni(c.Context(), params)
@@ -85,8 +70,32 @@ func (h *AlumniHandler) UpdateAlumni(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
 	}
 
-	alumni, err := h.alumniUsecase.UpdateAlumni(c.Context(), id, &req)
+	alumni, err := h.alumniUsecase.UpdateAlumni(c.Context(), id, &req, auditMeta(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(alumni)
+}
+
+func (h *AlumniHandler) LinkUser(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	var req domain.LinkAlumniUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	alumni, err := h.alumniUsecase.LinkUser(c.Context(), id, &req, auditMeta(c))
 	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFound):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrAlumniAlreadyLinked):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(alumni)
@@ -98,8 +107,52 @@ func (h *AlumniHandler) DeleteAlumni(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
 	}
 
-	if err := h.alumniUsecase.DeleteAlumni(c.Context(), id); err != nil {
+	if err := h.alumniUsecase.DeleteAlumni(c.Context(), id, auditMeta(c)); err != nil {
+		if errors.Is(err, domain.ErrAlumniNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// ImportAlumni menerima file CSV/XLSX di field multipart "file". Dengan ?dry_run=true hanya laporan
+// validasi yang dikembalikan tanpa menyimpan data.
+func (h *AlumniHandler) ImportAlumni(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot open uploaded file"})
+	}
+	defer file.Close()
+
+	report, err := h.alumniUsecase.ImportAlumni(c.Context(), fileHeader.Filename, file, c.QueryBool("dry_run"), auditMeta(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, spreadsheet.ErrUnsupportedFormat), errors.Is(err, domain.ErrImportUnreadable),
+			errors.Is(err, domain.ErrImportEmpty), errors.Is(err, domain.ErrImportMissingColumn),
+			errors.Is(err, domain.ErrImportTooManyRows):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(report)
+}
+
+// ExportAlumni mengirim semua hasil GET /api/alumni (search, sort dan filter yang sama, tanpa paging)
+// sebagai file csv, xlsx atau pdf; ?columns=a,b memilih dan mengurutkan kolom
+func (h *AlumniHandler) ExportAlumni(c *fiber.Ctx) error {
+	params, err := listParams(c, "created_at:desc", domain.AlumniFilters)
+	if err != nil {
+		return c.Status(exportErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	export, err := h.alumniUsecase.ExportAlumni(c.Context(), params, c.Query("format", "csv"), splitList(c.Query("columns")))
+	if err != nil {
+		return c.Status(exportErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return sendExport(c, export)
+}
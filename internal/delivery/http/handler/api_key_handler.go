@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyHandler struct {
+	apiKeyUsecase usecase.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(aku usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyUsecase: aku}
+}
+
+// apiKeyErrorStatus memetakan error dari usecase ke HTTP status yang sesuai
+func apiKeyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidAPIKeyName), errors.Is(err, domain.ErrInvalidAPIKeyExpiry),
+		errors.Is(err, domain.ErrScopesRequired):
+		return fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrScopeNotGranted):
+		return fiber.StatusForbidden
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func (h *APIKeyHandler) GetMyAPIKeys(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	keys, err := h.apiKeyUsecase.GetMyAPIKeys(c.Context(), userID)
+	if err != nil {
+		return c.Status(apiKeyErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(keys)
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	resp, err := h.apiKeyUsecase.CreateAPIKey(c.Context(), userID, &req)
+	if err != nil {
+		return c.Status(apiKeyErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	if err := h.apiKeyUsecase.RevokeAPIKey(c.Context(), userID, id); err != nil {
+		return c.Status(apiKeyErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	auditUsecase usecase.AuditUsecase
+}
+
+func NewAuditHandler(au usecase.AuditUsecase) *AuditHandler {
+	return &AuditHandler{auditUsecase: au}
+}
+
+// auditMeta mengambil pelaku dan asal request untuk dicatat bersama perubahan data
+func auditMeta(c *fiber.Ctx) domain.AuditMeta {
+	meta := domain.AuditMeta{IPAddress: c.IP()}
+	if userID, err := middleware.GetUserIDFromToken(c); err == nil {
+		meta.ActorUserID = &userID
+	}
+	if actorID, ok := middleware.GetActorIDFromToken(c); ok {
+		meta.ImpersonatorID = &actorID
+	}
+	if requestID, ok := c.Locals("requestid").(string); ok {
+		meta.RequestID = requestID
+	}
+	return meta
+}
+
+// parseAuditTime menerima RFC3339 atau YYYY-MM-DD; tanggal saja pada batas "to" dihitung sampai akhir hari
+func parseAuditTime(value string, endOfDay bool) (*time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+func (h *AuditHandler) GetAuditLogs(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	filter := domain.AuditFilter{
+		EntityType: c.Query("entity_type"),
+		Page:       page,
+		Limit:      limit,
+	}
+	if v := c.Query("entity_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid entity_id"})
+		}
+		filter.EntityID = &id
+	}
+	if v := c.Query("actor_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid actor_id"})
+		}
+		filter.ActorUserID = &id
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := parseAuditTime(v, false)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid from, use RFC3339 or YYYY-MM-DD"})
+		}
+		filter.From = from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := parseAuditTime(v, true)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid to, use RFC3339 or YYYY-MM-DD"})
+		}
+		filter.To = to
+	}
+
+	result, err := h.auditUsecase.GetAuditLogs(c.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAuditFilter) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(result)
+}
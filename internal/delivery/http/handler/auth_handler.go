@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"back-train/internal/delivery/http/middleware"
 	"back-train/internal/domain"
 	"back-train/internal/usecase"
+	"errors"
+	"log"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -23,6 +28,15 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 
 	user, err := h.authUsecase.Register(c.Context(), req.Email, req.Password)
 	if err != nil {
+		if errors.Is(err, domain.ErrWeakPassword) {
+			return passwordPolicyResponse(c, err)
+		}
+		if errors.Is(err, domain.ErrInvalidEmail) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrEmailTaken) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -35,10 +49,158 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
 	}
 
-	token, err := h.authUsecase.Login(c.Context(), req.Email, req.Password)
+	resp, err := h.authUsecase.Login(c.Context(), req.Email, req.Password, clientInfo(c))
 	if err != nil {
+		if errors.Is(err, domain.ErrAccountLocked) {
+			return lockedResponse(c, err)
+		}
+		if errors.Is(err, domain.ErrEmailNotVerified) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrAuthUnavailable) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.JSON(domain.LoginResponse{Token: token})
+	return c.JSON(resp)
+}
+
+// clientInfo mengambil IP dan user agent untuk dicatat di session
+func clientInfo(c *fiber.Ctx) domain.ClientInfo {
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+	return domain.ClientInfo{IPAddress: c.IP(), UserAgent: userAgent}
+}
+
+// lockedResponse membalas 429 dengan header Retry-After sesuai sisa waktu lockout
+func lockedResponse(c *fiber.Ctx, err error) error {
+	var locked *domain.LockedError
+	if errors.As(err, &locked) {
+		retryAfter := int(time.Until(locked.Until).Seconds()) + 1
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	}
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": err.Error()})
+}
+
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req domain.RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	resp, err := h.authUsecase.Refresh(c.Context(), req.RefreshToken, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(resp)
+}
+
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	sessionID, err := middleware.GetSessionIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.authUsecase.Logout(c.Context(), sessionID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req domain.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	if err := h.authUsecase.VerifyEmail(c.Context(), req.Token); err != nil {
+		if errors.Is(err, domain.ErrInvalidVerification) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Email has been verified"})
+}
+
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	var req domain.ResendVerificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	if err := h.authUsecase.ResendVerification(c.Context(), req.Email); err != nil {
+		log.Printf("resend verification failed: %v", err)
+	}
+
+	return c.JSON(fiber.Map{"message": "If the account exists and is not verified yet, a new verification link has been sent"})
+}
+
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req domain.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	if err := h.authUsecase.ForgotPassword(c.Context(), req.Email); err != nil {
+		log.Printf("forgot password failed: %v", err)
+	}
+
+	// Respons selalu sama agar tidak bisa dipakai untuk menebak email yang terdaftar
+	return c.JSON(fiber.Map{"message": "If the email is registered, a password reset link has been sent"})
+}
+
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req domain.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	if err := h.authUsecase.ResetPassword(c.Context(), req.Token, req.Password); err != nil {
+		if errors.Is(err, domain.ErrInvalidResetToken) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrWeakPassword) {
+			return passwordPolicyResponse(c, err)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Password has been reset"})
+}
+
+// passwordPolicyResponse membalas 422 beserta semua aturan password yang dilanggar
+func passwordPolicyResponse(c *fiber.Ctx, err error) error {
+	violations := []domain.PasswordViolation{}
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		violations = policyErr.Violations
+	}
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error(), "violations": violations})
+}
+
+func (h *AuthHandler) UnlockUser(c *fiber.Ctx) error {
+	actorID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	if err := h.authUsecase.UnlockAccount(c.Context(), actorID, id); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "Account has been unlocked"})
 }
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type stubDeleteAlumni struct {
+	usecase.AlumniUsecase
+	err error
+}
+
+func (s stubDeleteAlumni) DeleteAlumni(ctx context.Context, id int, meta domain.AuditMeta) error {
+	return s.err
+}
+
+type stubDeleteMahasiswa struct {
+	usecase.MahasiswaUsecase
+	err error
+}
+
+func (s stubDeleteMahasiswa) DeleteMahasiswa(ctx context.Context, id int, meta domain.AuditMeta) error {
+	return s.err
+}
+
+type stubDeletePekerjaan struct {
+	usecase.PekerjaanUsecase
+	err error
+}
+
+func (s stubDeletePekerjaan) DeletePekerjaan(ctx context.Context, id int, meta domain.AuditMeta) error {
+	return s.err
+}
+
+func TestDeleteStatus(t *testing.T) {
+	handlers := map[string]func(err error) fiber.Handler{
+		"alumni": func(err error) fiber.Handler { return NewAlumniHandler(stubDeleteAlumni{err: err}).DeleteAlumni },
+		"mahasiswa": func(err error) fiber.Handler {
+			return NewMahasiswaHandler(stubDeleteMahasiswa{err: err}).DeleteMahasiswa
+		},
+		"pekerjaan": func(err error) fiber.Handler {
+			return NewPekerjaanHandler(stubDeletePekerjaan{err: err}).DeletePekerjaan
+		},
+	}
+	tests := []struct {
+		entity string
+		err    error
+		want   int
+	}{
+		{"alumni", nil, fiber.StatusNoContent},
+		{"alumni", domain.ErrAlumniNotFound, fiber.StatusNotFound},
+		{"alumni", errors.New("db down"), fiber.StatusInternalServerError},
+		{"mahasiswa", nil, fiber.StatusNoContent},
+		{"mahasiswa", fmt.Errorf("delete: %w", domain.ErrMahasiswaNotFound), fiber.StatusNotFound},
+		{"mahasiswa", errors.New("db down"), fiber.StatusInternalServerError},
+		{"pekerjaan", nil, fiber.StatusNoContent},
+		{"pekerjaan", domain.ErrPekerjaanNotFound, fiber.StatusNotFound},
+		{"pekerjaan", errors.New("db down"), fiber.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %v", tt.entity, tt.err), func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals("user", &jwt.Token{Claims: jwt.MapClaims{"user_id": float64(1)}})
+				return c.Next()
+			})
+			app.Delete("/:id", handlers[tt.entity](tt.err))
+
+			resp, err := app.Test(httptest.NewRequest("DELETE", "/7", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// exportErrorStatus memetakan error saat menyiapkan export ke HTTP status yang sesuai
+func exportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidExportFormat), errors.Is(err, domain.ErrInvalidExportColumn),
+		errors.Is(err, domain.ErrInvalidFilter), errors.Is(err, domain.ErrExportTooManyRows):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// sendExport mengirim file sebagai stream; baris dibaca dari database sambil ditulis ke client.
+// Request context fasthttp tidak boleh dipakai setelah handler selesai, jadi query memakai
+// context sendiri yang dibatasi export.Timeout dan dibatalkan saat penulisan ke client gagal.
+func sendExport(c *fiber.Ctx, export *usecase.ExportFile) error {
+	c.Set(fiber.HeaderContentType, export.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, export.Filename))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		var ctx context.Context
+		var cancel context.CancelFunc
+		if export.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(context.Background(), export.Timeout)
+		} else {
+			ctx, cancel = context.WithCancel(context.Background())
+		}
+		defer cancel()
+
+		if err := export.Write(ctx, &cancelOnErrorWriter{w: w, cancel: cancel}); err != nil {
+			log.Printf("export %s failed: %v", export.Filename, err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("export %s failed: %v", export.Filename, err)
+		}
+	})
+	return nil
+}
+
+// cancelOnErrorWriter membatalkan query begitu client terputus; writer csv menahan error
+// penulisan sampai flush berikutnya sehingga tanpa ini query tetap berjalan sampai selesai
+type cancelOnErrorWriter struct {
+	w      io.Writer
+	cancel context.CancelFunc
+}
+
+func (cw *cancelOnErrorWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	if err != nil {
+		cw.cancel()
+	}
+	return n, err
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HistoryHandler dipakai untuk alumni, mahasiswa dan pekerjaan; jenis datanya ditentukan saat routing
+type HistoryHandler struct {
+	historyUsecase usecase.HistoryUsecase
+}
+
+func NewHistoryHandler(hu usecase.HistoryUsecase) *HistoryHandler {
+	return &HistoryHandler{historyUsecase: hu}
+}
+
+// historyErrorStatus memetakan error riwayat ke HTTP status yang sesuai
+func historyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrVersionNotFound), errors.Is(err, domain.ErrAlumniNotFound),
+		errors.Is(err, domain.ErrMahasiswaNotFound), errors.Is(err, domain.ErrPekerjaanNotFound):
+		return fiber.StatusNotFound
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func (h *HistoryHandler) GetHistory(entityType string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+		}
+
+		versions, err := h.historyUsecase.GetHistory(c.Context(), entityType, id)
+		if err != nil {
+			return c.Status(historyErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(versions)
+	}
+}
+
+func (h *HistoryHandler) DiffVersions(entityType string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+		}
+		from, errFrom := strconv.Atoi(c.Query("from"))
+		to, errTo := strconv.Atoi(c.Query("to"))
+		if errFrom != nil || errTo != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "from and to must be version numbers"})
+		}
+
+		diff, err := h.historyUsecase.DiffVersions(c.Context(), entityType, id, from, to)
+		if err != nil {
+			return c.Status(historyErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(diff)
+	}
+}
+
+func (h *HistoryHandler) RestoreVersion(entityType string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+		}
+		version, err := strconv.Atoi(c.Params("version"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid version"})
+		}
+
+		entity, err := h.historyUsecase.RestoreVersion(c.Context(), entityType, id, version, auditMeta(c))
+		if err != nil {
+			return c.Status(historyErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(entity)
+	}
+}
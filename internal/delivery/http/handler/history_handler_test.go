@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"back-train/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHistoryErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{domain.ErrVersionNotFound, fiber.StatusNotFound},
+		{domain.ErrAlumniNotFound, fiber.StatusNotFound},
+		{fmt.Errorf("load current: %w", domain.ErrMahasiswaNotFound), fiber.StatusNotFound},
+		{domain.ErrPekerjaanNotFound, fiber.StatusNotFound},
+		// Error lain yang kebetulan berakhiran "not found" bukan berarti datanya tidak ada
+		{errors.New("relation \"alumni\" not found"), fiber.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := historyErrorStatus(tt.err); got != tt.want {
+			t.Errorf("historyErrorStatus(%q) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// impersonationErrorStatus memetakan error impersonation ke HTTP status yang sesuai
+func impersonationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrImpersonationDenied), errors.Is(err, domain.ErrCannotImpersonate):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrUserNotFound):
+		return fiber.StatusNotFound
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Impersonate menerbitkan token untuk melihat aplikasi sebagai user lain; semua request dengan token itu dicatat
+func (h *AuthHandler) Impersonate(c *fiber.Ctx) error {
+	targetID, err := strconv.Atoi(c.Params("userID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	actorID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	resp, err := h.authUsecase.Impersonate(c.Context(), actorID, targetID, clientInfo(c))
+	if err != nil {
+		return c.Status(impersonationErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(resp)
+}
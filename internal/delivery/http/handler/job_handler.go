@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"back-train/pkg/spreadsheet"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JobHandler struct {
+	jobUsecase usecase.JobUsecase
+}
+
+func NewJobHandler(ju usecase.JobUsecase) *JobHandler {
+	return &JobHandler{jobUsecase: ju}
+}
+
+// jobErrorStatus memetakan error job ke HTTP status yang sesuai
+func jobErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrJobNotFound), errors.Is(err, domain.ErrJobNoArtifact):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrJobNotCancellable):
+		return fiber.StatusConflict
+	case errors.Is(err, domain.ErrInvalidJobFilter), errors.Is(err, spreadsheet.ErrUnsupportedFormat):
+		return fiber.StatusBadRequest
+	default:
+		return exportErrorStatus(err)
+	}
+}
+
+// canManageJobs bernilai true jika user boleh melihat dan membatalkan job milik user lain
+func canManageJobs(c *fiber.Ctx) bool {
+	for _, p := range middleware.GetPermissionsFromToken(c) {
+		if p == domain.PermJobsManage {
+			return true
+		}
+	}
+	return false
+}
+
+// jobAccepted membalas 202 dengan job yang baru dibuat; statusnya dipantau lewat header Location
+func jobAccepted(c *fiber.Ctx, job *domain.Job) error {
+	c.Location(fmt.Sprintf("/api/jobs/%d", job.ID))
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// EnqueueAlumniImport sama dengan POST /api/alumni/import tetapi diproses di background
+func (h *JobHandler) EnqueueAlumniImport(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot open uploaded file"})
+	}
+	defer file.Close()
+
+	job, err := h.jobUsecase.EnqueueAlumniImport(c.Context(), fileHeader.Filename, file, c.QueryBool("dry_run"), auditMeta(c))
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return jobAccepted(c, job)
+}
+
+// EnqueueAlumniExport menerima query string yang sama dengan GET /api/alumni/export
+func (h *JobHandler) EnqueueAlumniExport(c *fiber.Ctx) error {
+	params, err := listParams(c, "created_at:desc", domain.AlumniFilters)
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	job, err := h.jobUsecase.EnqueueAlumniExport(c.Context(), params, c.Query("format", "csv"), splitList(c.Query("columns")), auditMeta(c))
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return jobAccepted(c, job)
+}
+
+// EnqueuePekerjaanExport menerima query string yang sama dengan GET /api/pekerjaan/export
+func (h *JobHandler) EnqueuePekerjaanExport(c *fiber.Ctx) error {
+	params, err := listParams(c, "created_at:desc", domain.PekerjaanFilters)
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	job, err := h.jobUsecase.EnqueuePekerjaanExport(c.Context(), params, c.Query("format", "csv"), splitList(c.Query("columns")), auditMeta(c))
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return jobAccepted(c, job)
+}
+
+// GetJobs menampilkan job milik user sendiri; dengan jobs:manage semua job, bisa difilter created_by
+func (h *JobHandler) GetJobs(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	params, err := listParams(c, "", domain.JobFilters)
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	filter := domain.JobFilter{
+		Type:   params.Filters["type"],
+		Status: params.Filters["status"],
+		Page:   params.Page,
+		Limit:  params.Limit,
+	}
+	if !canManageJobs(c) {
+		filter.CreatedBy = &userID
+	} else if v, ok := params.Filters["created_by"]; ok {
+		id, _ := strconv.Atoi(v) // sudah divalidasi listParams
+		filter.CreatedBy = &id
+	}
+
+	result, err := h.jobUsecase.GetJobs(c.Context(), filter)
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(result)
+}
+
+func (h *JobHandler) GetJob(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	job, err := h.jobUsecase.GetJob(c.Context(), id, userID, canManageJobs(c))
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(job)
+}
+
+// CancelJob membatalkan job yang masih antri; job yang sedang berjalan dihentikan worker dalam
+// beberapa detik sehingga statusnya perlu dipantau lewat GET /api/jobs/:id
+func (h *JobHandler) CancelJob(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	job, err := h.jobUsecase.CancelJob(c.Context(), id, userID, canManageJobs(c))
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	if job.Status == domain.JobStatusCancelled {
+		return c.JSON(job)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+func (h *JobHandler) DownloadJobArtifact(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	job, err := h.jobUsecase.GetJobArtifact(c.Context(), id, userID, canManageJobs(c))
+	if err != nil {
+		return c.Status(jobErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := c.Download(*job.ArtifactPath, *job.ArtifactName); err != nil {
+		return err
+	}
+	if job.ArtifactContentType != nil {
+		c.Set(fiber.HeaderContentType, *job.ArtifactContentType)
+	}
+	return nil
+}
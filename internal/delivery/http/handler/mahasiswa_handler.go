@@ -3,6 +3,7 @@ package handler
 import (
 	"back-train/internal/domain"
 	"back-train/internal/usecase"
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -22,7 +23,7 @@ func (h *MahasiswaHandler) CreateMahasiswa(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
 	}
 
-	mahasiswa, err := h.mahasiswaUsecase.CreateMahasiswa(c.Context(), &req)
+	mahasiswa, err := h.mahasiswaUsecase.CreateMahasiswa(c.Context(), &req, auditMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -61,7 +62,7 @@ func (h *MahasiswaHandler) UpdateMahasiswa(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
 	}
 
-	mahasiswa, err := h.mahasiswaUsecase.UpdateMahasiswa(c.Context(), id, &req)
+	mahasiswa, err := h.mahasiswaUsecase.UpdateMahasiswa(c.Context(), id, &req, auditMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -74,7 +75,10 @@ func (h *MahasiswaHandler) DeleteMahasiswa(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
 	}
 
-	if err := h.mahasiswaUsecase.DeleteMahasiswa(c.Context(), id); err != nil {
+	if err := h.mahasiswaUsecase.DeleteMahasiswa(c.Context(), id, auditMeta(c)); err != nil {
+		if errors.Is(err, domain.ErrMahasiswaNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.SendStatus(fiber.StatusNoContent)
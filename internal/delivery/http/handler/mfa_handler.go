@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// mfaErrorStatus memetakan error 2FA dari usecase ke HTTP status yang sesuai
+func mfaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidMFAChallenge), errors.Is(err, domain.ErrInvalidMFACode):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, domain.ErrMFASetupRequired):
+		return fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrMFANotEnabled), errors.Is(err, domain.ErrMFAAlreadyEnabled):
+		return fiber.StatusConflict
+	case errors.Is(err, domain.ErrMFARequired):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrUserNotFound):
+		return fiber.StatusNotFound
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func (h *AuthHandler) VerifyMFA(c *fiber.Ctx) error {
+	var req domain.MFAVerifyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	resp, err := h.authUsecase.VerifyMFA(c.Context(), &req, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountLocked) {
+			return lockedResponse(c, err)
+		}
+		return c.Status(mfaErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(resp)
+}
+
+func (h *AuthHandler) BeginMFAEnrollment(c *fiber.Ctx) error {
+	var req domain.MFAChallengeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	resp, err := h.authUsecase.BeginMFAEnrollment(c.Context(), req.MFAToken)
+	if err != nil {
+		return c.Status(mfaErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(resp)
+}
+
+func (h *AuthHandler) ConfirmMFAEnrollment(c *fiber.Ctx) error {
+	var req domain.MFAEnrollmentConfirmRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	resp, err := h.authUsecase.ConfirmMFAEnrollment(c.Context(), &req, clientInfo(c))
+	if err != nil {
+		return c.Status(mfaErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(resp)
+}
+
+func (h *AuthHandler) GetMyMFAStatus(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	status, err := h.authUsecase.GetMFAStatus(c.Context(), userID)
+	if err != nil {
+		return c.Status(mfaErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(status)
+}
+
+func (h *AuthHandler) SetupMyMFA(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	resp, err := h.authUsecase.SetupMFA(c.Context(), userID)
+	if err != nil {
+		return c.Status(mfaErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(resp)
+}
+
+func (h *AuthHandler) EnableMyMFA(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.MFACodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	resp, err := h.authUsecase.EnableMFA(c.Context(), userID, req.Code)
+	if err != nil {
+		return c.Status(mfaErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(resp)
+}
+
+func (h *AuthHandler) DisableMyMFA(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.MFACodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	if err := h.authUsecase.DisableMFA(c.Context(), userID, req.Code); err != nil {
+		return c.Status(mfaErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *AuthHandler) RegenerateMyRecoveryCodes(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.MFACodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	resp, err := h.authUsecase.RegenerateRecoveryCodes(c.Context(), userID, req.Code)
+	if err != nil {
+		return c.Status(mfaErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(resp)
+}
+
+func (h *AuthHandler) ResetUserMFA(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	if err := h.authUsecase.ResetMFA(c.Context(), id); err != nil {
+		return c.Status(mfaErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
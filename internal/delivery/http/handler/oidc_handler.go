@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const oidcStateCookie = "oidc_state"
+
+type OIDCHandler struct {
+	oidcUsecase usecase.OIDCUsecase
+	// postLoginRedirectURL opsional; jika diisi, hasil login dikirim ke frontend lewat fragment URL
+	postLoginRedirectURL string
+}
+
+func NewOIDCHandler(ou usecase.OIDCUsecase, postLoginRedirectURL string) *OIDCHandler {
+	return &OIDCHandler{oidcUsecase: ou, postLoginRedirectURL: postLoginRedirectURL}
+}
+
+func (h *OIDCHandler) Login(c *fiber.Ctx) error {
+	req, err := h.oidcUsecase.BeginLogin(c.Context())
+	if err != nil {
+		log.Printf("oidc login failed: %v", err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "identity provider is unavailable"})
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    req.StateCookie,
+		Path:     "/api/auth/oidc",
+		Expires:  req.ExpiresAt,
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode, // harus terkirim saat IdP redirect kembali ke callback
+	})
+	return c.Redirect(req.AuthURL, fiber.StatusFound)
+}
+
+func (h *OIDCHandler) Callback(c *fiber.Ctx) error {
+	// Cookie state hanya berlaku untuk satu kali callback
+	stateCookie := c.Cookies(oidcStateCookie)
+	c.ClearCookie(oidcStateCookie)
+
+	if idpError := c.Query("error"); idpError != "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "identity provider returned " + idpError})
+	}
+
+	resp, err := h.oidcUsecase.Callback(c.Context(), c.Query("code"), c.Query("state"), stateCookie, clientInfo(c))
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrInvalidSSOState):
+			status = fiber.StatusBadRequest
+		case errors.Is(err, domain.ErrSSOLoginFailed), errors.Is(err, domain.ErrSSOEmailNotVerified):
+			status = fiber.StatusUnauthorized
+		case errors.Is(err, domain.ErrAccountDisabled):
+			status = fiber.StatusForbidden
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if h.postLoginRedirectURL == "" {
+		return c.JSON(resp)
+	}
+
+	// Token ditaruh di fragment agar tidak terkirim ke server frontend maupun tercatat di log
+	fragment := url.Values{}
+	if resp.MFARequired {
+		fragment.Set("mfa_token", resp.MFAToken)
+		fragment.Set("mfa_enrollment_required", strconv.FormatBool(resp.MFAEnrollmentRequired))
+	} else {
+		fragment.Set("token", resp.Token)
+		fragment.Set("refresh_token", resp.RefreshToken)
+		fragment.Set("expires_in", strconv.FormatInt(resp.ExpiresIn, 10))
+	}
+	return c.Redirect(h.postLoginRedirectURL+"#"+fragment.Encode(), fiber.StatusFound)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"back-train/internal/domain"
 	"back-train/internal/usecase"
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -22,7 +23,7 @@ func (h *PekerjaanHandler) CreatePekerjaan(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
 	}
 
-	pekerjaan, err := h.pekerjaanUsecase.CreatePekerjaan(c.Context(), &req)
+	pekerjaan, err := h.pekerjaanUsecase.CreatePekerjaan(c.Context(), &req, auditMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -30,27 +31,10 @@ func (h *PekerjaanHandler) CreatePekerjaan(c *fiber.Ctx) error {
 }
 
 func (h *PekerjaanHandler) GetAllPekerjaan(c *fiber.Ctx) error {
-	// Parse query parameters
-	page, _ := strconv.Atoi(c.Query("page", "1"))
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	sort := c.Query("sort", "created_at:desc") // contoh: "nama_perusahaan:asc"
-	search := c.Query("search", "")
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
-	}
-	if limit > 100 { // Batasi limit untuk mencegah query yang berlebihan
-		limit = 100
-	}
-
-	params := domain.PaginationParams{
-		Page:   page,
-		Limit:  limit,
-		Sort:   sort,
-		Search: search,
+	// Parse query parameters, contoh sort: "nama_perusahaan:asc"
+	params, err := listParams(c, "created_at:desc", domain.PekerjaanFilters)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	result, err := h.pekerjaanUsecase.GetAllPekerjaan(c.Context(), params)
@@ -84,7 +68,7 @@ func (h *PekerjaanHandler) UpdatePekerjaan(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
 	}
 
-	pekerjaan, err := h.pekerjaanUsecase.UpdatePekerjaan(c.Context(), id, &req)
+	pekerjaan, err := h.pekerjaanUsecase.UpdatePekerjaan(c.Context(), id, &req, auditMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -97,8 +81,26 @@ func (h *PekerjaanHandler) DeletePekerjaan(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
 	}
 
-	if err := h.pekerjaanUsecase.DeletePekerjaan(c.Context(), id); err != nil {
+	if err := h.pekerjaanUsecase.DeletePekerjaan(c.Context(), id, auditMeta(c)); err != nil {
+		if errors.Is(err, domain.ErrPekerjaanNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// ExportPekerjaan mengirim semua hasil GET /api/pekerjaan (search, sort dan filter yang sama, tanpa paging)
+// sebagai file csv, xlsx atau pdf; ?columns=a,b memilih dan mengurutkan kolom
+func (h *PekerjaanHandler) ExportPekerjaan(c *fiber.Ctx) error {
+	params, err := listParams(c, "created_at:desc", domain.PekerjaanFilters)
+	if err != nil {
+		return c.Status(exportErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	export, err := h.pekerjaanUsecase.ExportPekerjaan(c.Context(), params, c.Query("format", "csv"), splitList(c.Query("columns")))
+	if err != nil {
+		return c.Status(exportErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return sendExport(c, export)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"back-train/internal/domain"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// listParams membaca page, limit, sort, search dan filter yang diizinkan dari query string.
+// Filter angka yang bukan angka ditolak agar tidak menjadi error database.
+func listParams(c *fiber.Ctx, defaultSort string, filters map[string]bool) (domain.PaginationParams, error) {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 { // Batasi limit untuk mencegah query yang berlebihan
+		limit = 100
+	}
+
+	params := domain.PaginationParams{
+		Page:    page,
+		Limit:   limit,
+		Sort:    c.Query("sort", defaultSort),
+		Search:  c.Query("search", ""),
+		Filters: map[string]string{},
+	}
+	for name, numeric := range filters {
+		value := strings.TrimSpace(c.Query(name))
+		if value == "" {
+			continue
+		}
+		if numeric {
+			if _, err := strconv.Atoi(value); err != nil {
+				return params, fmt.Errorf("%w: %s must be a number", domain.ErrInvalidFilter, name)
+			}
+		}
+		params.Filters[name] = value
+	}
+	return params, nil
+}
+
+// splitList memecah nilai query "a,b,c" dan membuang bagian kosong
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
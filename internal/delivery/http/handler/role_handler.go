@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RoleHandler struct {
+	roleUsecase usecase.RoleUsecase
+}
+
+func NewRoleHandler(ru usecase.RoleUsecase) *RoleHandler {
+	return &RoleHandler{roleUsecase: ru}
+}
+
+// roleErrorStatus memetakan error dari usecase ke HTTP status yang sesuai
+func roleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrRoleNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidRoleName), errors.Is(err, domain.ErrPermissionNotFound):
+		return fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrRoleExists):
+		return fiber.StatusConflict
+	case errors.Is(err, domain.ErrProtectedRole):
+		return fiber.StatusForbidden
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func (h *RoleHandler) GetAllRoles(c *fiber.Ctx) error {
+	roles, err := h.roleUsecase.GetAllRoles(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(roles)
+}
+
+func (h *RoleHandler) GetRoleByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	role, err := h.roleUsecase.GetRoleByID(c.Context(), id)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(role)
+}
+
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	var req domain.CreateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	role, err := h.roleUsecase.CreateRole(c.Context(), &req)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(role)
+}
+
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	var req domain.UpdateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	role, err := h.roleUsecase.UpdateRole(c.Context(), id, &req)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(role)
+}
+
+func (h *RoleHandler) UpdateRolePermissions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	var req domain.UpdateRolePermissionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	role, err := h.roleUsecase.UpdateRolePermissions(c.Context(), id, &req)
+	if err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(role)
+}
+
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	if err := h.roleUsecase.DeleteRole(c.Context(), id); err != nil {
+		return c.Status(roleErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *RoleHandler) GetAllPermissions(c *fiber.Ctx) error {
+	permissions, err := h.roleUsecase.GetAllPermissions(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(permissions)
+}
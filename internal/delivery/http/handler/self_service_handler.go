@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SelfServiceHandler struct {
+	selfServiceUsecase usecase.SelfServiceUsecase
+}
+
+func NewSelfServiceHandler(su usecase.SelfServiceUsecase) *SelfServiceHandler {
+	return &SelfServiceHandler{selfServiceUsecase: su}
+}
+
+// selfServiceErrorStatus memetakan error dari usecase ke HTTP status yang sesuai
+func selfServiceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrAlumniNotLinked):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrAlumniAlreadyLinked):
+		return fiber.StatusConflict
+	case errors.Is(err, domain.ErrInvalidClaimToken):
+		return fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrNotOwner):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrAlumniNotFound), errors.Is(err, domain.ErrPekerjaanNotFound):
+		return fiber.StatusNotFound
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func (h *SelfServiceHandler) ClaimAlumni(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.ClaimAlumniRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	alumni, err := h.selfServiceUsecase.ClaimAlumni(c.Context(), userID, req.NIM, auditMeta(c))
+	if err != nil {
+		return c.Status(selfServiceErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	if alumni == nil {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "A confirmation link has been sent to the email registered on the alumni record"})
+	}
+	return c.JSON(alumni)
+}
+
+func (h *SelfServiceHandler) ConfirmAlumniClaim(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.ConfirmAlumniClaimRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	alumni, err := h.selfServiceUsecase.ConfirmAlumniClaim(c.Context(), userID, req.Token, auditMeta(c))
+	if err != nil {
+		return c.Status(selfServiceErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(alumni)
+}
+
+func (h *SelfServiceHandler) GetMyAlumni(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	alumni, err := h.selfServiceUsecase.GetMyAlumni(c.Context(), userID)
+	if err != nil {
+		return c.Status(selfServiceErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(alumni)
+}
+
+func (h *SelfServiceHandler) UpdateMyAlumni(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.UpdateMyAlumniRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	alumni, err := h.selfServiceUsecase.UpdateMyAlumni(c.Context(), userID, &req, auditMeta(c))
+	if err != nil {
+		return c.Status(selfServiceErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(alumni)
+}
+
+func (h *SelfServiceHandler) GetMyPekerjaan(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	pekerjaan, err := h.selfServiceUsecase.GetMyPekerjaan(c.Context(), userID)
+	if err != nil {
+		return c.Status(selfServiceErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(pekerjaan)
+}
+
+func (h *SelfServiceHandler) CreateMyPekerjaan(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req domain.CreatePekerjaanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	pekerjaan, err := h.selfServiceUsecase.CreateMyPekerjaan(c.Context(), userID, &req, auditMeta(c))
+	if err != nil {
+		return c.Status(selfServiceErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(pekerjaan)
+}
+
+func (h *SelfServiceHandler) EndMyPekerjaan(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	var req domain.EndPekerjaanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	pekerjaan, err := h.selfServiceUsecase.EndMyPekerjaan(c.Context(), userID, id, &req, auditMeta(c))
+	if err != nil {
+		return c.Status(selfServiceErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(pekerjaan)
+}
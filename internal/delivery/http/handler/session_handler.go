@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sessionErrorStatus memetakan error pengelolaan session ke HTTP status yang sesuai
+func sessionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrSessionNotFound), errors.Is(err, domain.ErrUserNotFound):
+		return fiber.StatusNotFound
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// currentSessionID kosong jika request memakai API key
+func currentSessionID(c *fiber.Ctx) string {
+	sessionID, _ := middleware.GetSessionIDFromToken(c)
+	return sessionID
+}
+
+func (h *AuthHandler) GetMySessions(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	sessions, err := h.authUsecase.ListSessions(c.Context(), userID, currentSessionID(c))
+	if err != nil {
+		return c.Status(sessionErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(sessions)
+}
+
+func (h *AuthHandler) RevokeMySession(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.authUsecase.RevokeSession(c.Context(), userID, userID, c.Params("id")); err != nil {
+		return c.Status(sessionErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RevokeMySessions keluar dari semua perangkat lain; ?include_current=true ikut mencabut session ini
+func (h *AuthHandler) RevokeMySessions(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	keep := currentSessionID(c)
+	if c.QueryBool("include_current") {
+		keep = ""
+	}
+	if err := h.authUsecase.RevokeAllSessions(c.Context(), userID, userID, keep); err != nil {
+		return c.Status(sessionErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *AuthHandler) GetUserSessions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	sessions, err := h.authUsecase.ListSessions(c.Context(), id, currentSessionID(c))
+	if err != nil {
+		return c.Status(sessionErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(sessions)
+}
+
+func (h *AuthHandler) RevokeUserSession(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	actorID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.authUsecase.RevokeSession(c.Context(), actorID, id, c.Params("sessionID")); err != nil {
+		return c.Status(sessionErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *AuthHandler) RevokeUserSessions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	actorID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.authUsecase.RevokeAllSessions(c.Context(), actorID, id, ""); err != nil {
+		return c.Status(sessionErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StatsHandler struct {
+	statsUsecase usecase.StatsUsecase
+}
+
+func NewStatsHandler(su usecase.StatsUsecase) *StatsHandler {
+	return &StatsHandler{statsUsecase: su}
+}
+
+// statsFilter membaca filter dari query string: jurusan, angkatan, tahun_lulus_from, tahun_lulus_to dan job
+func statsFilter(c *fiber.Ctx) (domain.StatsFilter, error) {
+	filter := domain.StatsFilter{Jurusan: c.Query("jurusan"), Job: c.Query("job")}
+	numbers := []struct {
+		name string
+		dst  **int
+	}{
+		{"angkatan", &filter.Angkatan},
+		{"tahun_lulus_from", &filter.TahunLulusFrom},
+		{"tahun_lulus_to", &filter.TahunLulusTo},
+	}
+	for _, f := range numbers {
+		v := c.Query(f.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("invalid " + f.name)
+		}
+		*f.dst = &n
+	}
+	return filter, nil
+}
+
+func statsErrorStatus(err error) int {
+	if errors.Is(err, domain.ErrInvalidStatsFilter) {
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
+
+// GetEmployment mengembalikan tingkat keterserapan kerja dan masa tunggu, total dan per tahun_lulus
+func (h *StatsHandler) GetEmployment(c *fiber.Ctx) error {
+	filter, err := statsFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	stats, err := h.statsUsecase.GetEmployment(c.Context(), filter)
+	if err != nil {
+		return c.Status(statsErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(stats)
+}
+
+func (h *StatsHandler) GetIndustryDistribution(c *fiber.Ctx) error {
+	filter, err := statsFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	dist, err := h.statsUsecase.GetIndustryDistribution(c.Context(), filter)
+	if err != nil {
+		return c.Status(statsErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(dist)
+}
+
+func (h *StatsHandler) GetSalaryDistribution(c *fiber.Ctx) error {
+	filter, err := statsFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	dist, err := h.statsUsecase.GetSalaryDistribution(c.Context(), filter)
+	if err != nil {
+		return c.Status(statsErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(dist)
+}
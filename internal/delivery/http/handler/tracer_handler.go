@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TracerHandler struct {
+	tracerUsecase usecase.TracerUsecase
+}
+
+func NewTracerHandler(tu usecase.TracerUsecase) *TracerHandler {
+	return &TracerHandler{tracerUsecase: tu}
+}
+
+// tracerErrorStatus memetakan error tracer study ke HTTP status yang sesuai
+func tracerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrTracerNotFound), errors.Is(err, domain.ErrVersionNotFound),
+		errors.Is(err, domain.ErrInvitationNotFound), errors.Is(err, domain.ErrInvalidInvitation),
+		errors.Is(err, domain.ErrAlumniNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidQuestions), errors.Is(err, domain.ErrInvalidFilter):
+		return fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrAlreadySubmitted), errors.Is(err, domain.ErrTracerNoVersion),
+		errors.Is(err, domain.ErrTracerPeriodLocked):
+		return fiber.StatusConflict
+	case errors.Is(err, domain.ErrTracerClosed):
+		return fiber.StatusGone
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func tracerErrorResponse(c *fiber.Ctx, err error) error {
+	var answerErr *domain.AnswerValidationError
+	if errors.As(err, &answerErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error(), "errors": answerErr.Errors})
+	}
+	return c.Status(tracerErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+}
+
+// tracerPage membaca page dan limit dari query string dengan batas yang sama seperti listing lain
+func tracerPage(c *fiber.Ctx) (int, int) {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
+
+func (h *TracerHandler) CreateQuestionnaire(c *fiber.Ctx) error {
+	var req domain.QuestionnaireRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	q, err := h.tracerUsecase.CreateQuestionnaire(c.Context(), &req)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(q)
+}
+
+func (h *TracerHandler) GetQuestionnaires(c *fiber.Ctx) error {
+	list, err := h.tracerUsecase.GetQuestionnaires(c.Context())
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(list)
+}
+
+func (h *TracerHandler) GetQuestionnaire(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	q, err := h.tracerUsecase.GetQuestionnaire(c.Context(), id)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(q)
+}
+
+func (h *TracerHandler) UpdateQuestionnaire(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	var req domain.QuestionnaireRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	q, err := h.tracerUsecase.UpdateQuestionnaire(c.Context(), id, &req)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(q)
+}
+
+func (h *TracerHandler) CreateVersion(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromToken(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	var req domain.CreateQuestionnaireVersionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	version, err := h.tracerUsecase.CreateVersion(c.Context(), id, &req, &userID)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(version)
+}
+
+func (h *TracerHandler) GetVersions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	versions, err := h.tracerUsecase.GetVersions(c.Context(), id)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(versions)
+}
+
+func (h *TracerHandler) GetVersion(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid version"})
+	}
+
+	v, err := h.tracerUsecase.GetVersion(c.Context(), id, version)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(v)
+}
+
+func (h *TracerHandler) InviteAlumni(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	var req domain.InviteAlumniRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	result, err := h.tracerUsecase.Invite(c.Context(), id, &req)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(result)
+}
+
+func (h *TracerHandler) ResendInvitation(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	invitationID, err := strconv.Atoi(c.Params("invitationID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid invitation ID"})
+	}
+
+	inv, err := h.tracerUsecase.ResendInvitation(c.Context(), id, invitationID)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(inv)
+}
+
+// GetInvitations bisa difilter ?status=pending (belum mengisi) atau ?status=submitted
+func (h *TracerHandler) GetInvitations(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	page, limit := tracerPage(c)
+
+	result, err := h.tracerUsecase.GetInvitations(c.Context(), id, c.Query("status"), page, limit)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(result)
+}
+
+// GetResponses mengembalikan jawaban mentah beserta versi pertanyaan yang dipakai saat mengisi
+func (h *TracerHandler) GetResponses(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+	page, limit := tracerPage(c)
+
+	result, err := h.tracerUsecase.GetResponses(c.Context(), id, page, limit)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(result)
+}
+
+func (h *TracerHandler) GetStats(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	stats, err := h.tracerUsecase.GetStats(c.Context(), id)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(stats)
+}
+
+// GetForm dibuka alumni dari link email; token dikirim lewat query string, tanpa login
+func (h *TracerHandler) GetForm(c *fiber.Ctx) error {
+	form, err := h.tracerUsecase.GetForm(c.Context(), c.Query("token"))
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.JSON(form)
+}
+
+func (h *TracerHandler) Submit(c *fiber.Ctx) error {
+	var req domain.SubmitTracerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	submission, err := h.tracerUsecase.Submit(c.Context(), &req)
+	if err != nil {
+		return tracerErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(submission)
+}
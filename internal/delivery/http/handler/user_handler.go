@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserHandler struct {
+	userUsecase usecase.UserUsecase
+}
+
+func NewUserHandler(uu usecase.UserUsecase) *UserHandler {
+	return &UserHandler{userUsecase: uu}
+}
+
+// userErrorStatus memetakan error dari usecase ke HTTP status yang sesuai
+func userErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrRoleNotFound), errors.Is(err, domain.ErrRolesRequired):
+		return fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrLastAdmin):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
+	// Parse query parameters
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	sort := c.Query("sort", "created_at:desc") // contoh: "email:asc"
+	search := c.Query("search", "")
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 { // Batasi limit untuk mencegah query yang berlebihan
+		limit = 100
+	}
+
+	params := domain.PaginationParams{
+		Page:   page,
+		Limit:  limit,
+		Sort:   sort,
+		Search: search,
+	}
+
+	result, err := h.userUsecase.GetAllUsers(c.Context(), params)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(result)
+}
+
+func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	user, err := h.userUsecase.GetUserByID(c.Context(), id)
+	if err != nil {
+		return c.Status(userErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(user)
+}
+
+func (h *UserHandler) UpdateUserRoles(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	var req domain.UpdateUserRolesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	user, err := h.userUsecase.UpdateUserRoles(c.Context(), id, &req)
+	if err != nil {
+		return c.Status(userErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(user)
+}
+
+func (h *UserHandler) UpdateUserStatus(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	var req domain.UpdateUserStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+	}
+
+	user, err := h.userUsecase.UpdateUserStatus(c.Context(), id, &req)
+	if err != nil {
+		return c.Status(userErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(user)
+}
+
+func (h *UserHandler) MarkEmailVerified(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	user, err := h.userUsecase.MarkEmailVerified(c.Context(), id)
+	if err != nil {
+		return c.Status(userErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(user)
+}
+
+func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid ID"})
+	}
+
+	if err := h.userUsecase.DeleteUsers(c.Context(), id); err != nil {
+		return c.Status(userErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
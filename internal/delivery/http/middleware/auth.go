@@ -1,51 +1,184 @@
 package middleware
 
 import (
+	"back-train/internal/domain"
+	"back-train/pkg/jwtkeys"
+	"context"
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	jwtware "github.com/gofiber/jwt/v3"
 	"github.com/golang-jwt/jwt/v4"
 )
 
-// AuthMiddleware protects routes
-func AuthMiddleware(secret string) fiber.Handler {
-	return jwtware.New(jwtware.Config{
-		SigningKey: []byte(secret),
+// SessionValidator reports whether the session an access token belongs to is still active.
+// Dipanggil di setiap request, jadi implementasinya sebaiknya memakai cache (lihat NewCachedSessionRepository).
+type SessionValidator func(ctx context.Context, sessionID string) (bool, error)
+
+// APIKeyHeader adalah header alternatif untuk autentikasi memakai API key pribadi
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator memvalidasi API key dan mengembalikan pemiliknya dengan permission sesuai scope key
+type APIKeyAuthenticator func(ctx context.Context, rawKey string) (*domain.User, *domain.APIKey, error)
+
+// ImpersonationRecorder mencatat setiap request yang dibuat admin atas nama user lain
+type ImpersonationRecorder func(ctx context.Context, actorID, userID int, method, path string, status int, client domain.ClientInfo)
+
+// AuthMiddleware protects routes. Request bisa memakai bearer JWT (kunci verifikasi dipilih dari
+// header kid) atau header X-API-Key; keduanya menghasilkan c.Locals("user") dengan claim yang sama.
+func AuthMiddleware(keys *jwtkeys.KeySet, validateSession SessionValidator, authenticateAPIKey APIKeyAuthenticator, recordImpersonation ImpersonationRecorder) fiber.Handler {
+	jwtHandler := jwtware.New(jwtware.Config{
+		KeyFunc: keys.Keyfunc,
+		SuccessHandler: func(c *fiber.Ctx) error {
+			// Token yang session-nya sudah di-logout/di-revoke ditolak walaupun belum expired
+			sessionID, err := GetSessionIDFromToken(c)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+			}
+			active, err := validateSession(c.Context(), sessionID)
+			if err != nil || !active {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Session has been revoked"})
+			}
+
+			actorID, impersonating := GetActorIDFromToken(c)
+			if !impersonating {
+				return c.Next()
+			}
+			userID, _ := GetUserIDFromToken(c)
+			err = c.Next()
+			recordImpersonation(c.Context(), actorID, userID, c.Method(), c.OriginalURL(), responseStatus(c, err), domain.ClientInfo{
+				IPAddress: c.IP(),
+				UserAgent: c.Get(fiber.HeaderUserAgent),
+			})
+			return err
+		},
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Unauthorized",
 			})
 		},
 	})
+
+	return func(c *fiber.Ctx) error {
+		rawKey := c.Get(APIKeyHeader)
+		if rawKey == "" {
+			return jwtHandler(c)
+		}
+
+		user, key, err := authenticateAPIKey(c.Context(), rawKey)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid API key"})
+		}
+		c.Locals("user", apiKeyToken(user, key))
+		return c.Next()
+	}
+}
+
+// apiKeyToken membungkus pemilik API key sebagai token dengan claim yang sama seperti access token,
+// sehingga helper GetXxxFromToken dan RequirePermission tidak perlu membedakan keduanya.
+func apiKeyToken(user *domain.User, key *domain.APIKey) *jwt.Token {
+	claims := jwt.MapClaims{
+		// Angka disimpan sebagai float64 seperti hasil decode JSON pada JWT
+		"user_id":     float64(user.ID),
+		"email":       user.Email,
+		"roles":       toInterfaceSlice(user.Roles),
+		"permissions": toInterfaceSlice(user.Permissions),
+		"api_key_id":  float64(key.ID),
+	}
+	return &jwt.Token{Claims: claims, Valid: true}
+}
+
+func toInterfaceSlice(list []string) []interface{} {
+	result := make([]interface{}, len(list))
+	for i, item := range list {
+		result[i] = item
+	}
+	return result
 }
 
-// RoleMiddleware checks if user has the required role
-func RoleMiddleware(requiredRole string) fiber.Handler {
+// responseStatus menentukan status akhir respons, termasuk jika handler mengembalikan error
+// yang baru diubah menjadi respons oleh error handler Fiber setelah middleware selesai
+func responseStatus(c *fiber.Ctx, err error) int {
+	var fiberErr *fiber.Error
+	switch {
+	case err == nil:
+		return c.Response().StatusCode()
+	case errors.As(err, &fiberErr):
+		return fiberErr.Code
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// DenyImpersonation menolak aksi yang mengubah kredensial akun saat admin sedang meniru user
+func DenyImpersonation() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		user := c.Locals("user").(*jwt.Token)
-		claims := user.Claims.(jwt.MapClaims)
-		rolesClaim, ok := claims["roles"].([]interface{})
-		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Invalid roles claim"})
+		if _, impersonating := GetActorIDFromToken(c); impersonating {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": domain.ErrImpersonating.Error()})
 		}
+		return c.Next()
+	}
+}
 
-		userRoles := make([]string, len(rolesClaim))
-		for i, role := range rolesClaim {
-			userRoles[i] = role.(string)
+// DenyAPIKey menolak aksi atas kredensial dan session akun jika request diautentikasi dengan API key,
+// agar kebocoran satu key tidak bisa dipakai untuk mengambil alih akun
+func DenyAPIKey() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if IsAPIKeyRequest(c) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": domain.ErrAPIKeyNotAllowed.Error()})
 		}
+		return c.Next()
+	}
+}
 
-		for _, role := range userRoles {
-			if role == requiredRole {
-				return c.Next()
+// IsAPIKeyRequest melaporkan apakah request diautentikasi dengan API key, bukan login session
+func IsAPIKeyRequest(c *fiber.Ctx) bool {
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	_, ok := claims["api_key_id"]
+	return ok
+}
+
+// RequirePermission checks if the user holds every one of the given permissions.
+// Permission efektif dihitung saat login/refresh dan dibawa di claim "permissions".
+func RequirePermission(required ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		granted := map[string]bool{}
+		for _, p := range GetPermissionsFromToken(c) {
+			granted[p] = true
+		}
+
+		for _, p := range required {
+			if !granted[p] {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Forbidden: missing permission " + p,
+				})
 			}
 		}
+		return c.Next()
+	}
+}
+
+// GetPermissionsFromToken mengambil daftar permission dari claim "permissions"
+func GetPermissionsFromToken(c *fiber.Ctx) []string {
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	raw, ok := claims["permissions"].([]interface{})
+	if !ok {
+		return nil
+	}
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Forbidden: you don't have the required role",
-		})
+	permissions := make([]string, 0, len(raw))
+	for _, p := range raw {
+		if name, ok := p.(string); ok {
+			permissions = append(permissions, name)
+		}
 	}
+	return permissions
 }
 
-// Helper untuk mendapatkan ID user dari token (opsional, bisa digunakan di handler)
+// Helper untuk mendapatkan ID user dari token (opsional, bisa digunakan di handler).
+// Saat impersonation ini adalah user yang ditiru; admin yang sebenarnya ada di GetActorIDFromToken.
 func GetUserIDFromToken(c *fiber.Ctx) (int, error) {
 	user := c.Locals("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
@@ -55,3 +188,33 @@ func GetUserIDFromToken(c *fiber.Ctx) (int, error) {
 	}
 	return int(id), nil
 }
+
+// GetActorIDFromToken mengambil ID admin dari claim act; ok bernilai false jika token bukan hasil impersonation
+func GetActorIDFromToken(c *fiber.Ctx) (int, bool) {
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	act, ok := claims["act"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	id, ok := act["user_id"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int(id), true
+}
+
+// GetSessionIDFromToken mengambil session ID (claim jti) dari token
+func GetSessionIDFromToken(c *fiber.Ctx) (string, error) {
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	sid, ok := claims["jti"].(string)
+	if !ok {
+		// Token yang diterbitkan sebelum claim jti dipakai masih membawa sid sampai expired
+		sid, ok = claims["sid"].(string)
+	}
+	if !ok || sid == "" {
+		return "", fiber.NewError(fiber.StatusUnauthorized, "Invalid session in token")
+	}
+	return sid, nil
+}
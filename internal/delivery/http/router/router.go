@@ -1,9 +1,11 @@
 package router
 
 import (
-	"back-train/config"
 	"back-train/internal/delivery/http/handler"
 	"back-train/internal/delivery/http/middleware"
+	"back-train/internal/domain"
+	"back-train/internal/usecase"
+	"back-train/pkg/jwtkeys"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -14,40 +16,176 @@ func SetupRoutes(
 	alumniHandler *handler.AlumniHandler,
 	mahasiswaHandler *handler.MahasiswaHandler,
 	pekerjaanHandler *handler.PekerjaanHandler,
-	cfg *config.Config,
+	userHandler *handler.UserHandler,
+	roleHandler *handler.RoleHandler,
+	selfServiceHandler *handler.SelfServiceHandler,
+	jwksHandler *handler.JWKSHandler,
+	apiKeyHandler *handler.APIKeyHandler,
+	oidcHandler *handler.OIDCHandler, // nil jika SSO tidak dikonfigurasi
+	auditHandler *handler.AuditHandler,
+	historyHandler *handler.HistoryHandler,
+	jobHandler *handler.JobHandler,
+	tracerHandler *handler.TracerHandler,
+	statsHandler *handler.StatsHandler,
+	authUsecase usecase.AuthUsecase,
+	apiKeyUsecase usecase.APIKeyUsecase,
+	keys *jwtkeys.KeySet,
 ) {
+	// Public key untuk verifikasi access token oleh service lain
+	app.Get("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	api := app.Group("/api")
 
 	// Auth routes
 	auth := api.Group("/auth")
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/login", authHandler.Login)
+	auth.Post("/refresh", authHandler.Refresh)
+	auth.Post("/verify-email", authHandler.VerifyEmail)
+	auth.Post("/confirm-email-change", authHandler.ConfirmEmailChange)
+	auth.Post("/resend-verification", authHandler.ResendVerification)
+	auth.Post("/forgot-password", authHandler.ForgotPassword)
+	auth.Post("/reset-password", authHandler.ResetPassword)
+	if oidcHandler != nil {
+		auth.Get("/oidc/login", oidcHandler.Login)
+		auth.Get("/oidc/callback", oidcHandler.Callback)
+	}
+	auth.Post("/mfa/verify", authHandler.VerifyMFA)
+	auth.Post("/mfa/enroll", authHandler.BeginMFAEnrollment)
+	auth.Post("/mfa/enroll/confirm", authHandler.ConfirmMFAEnrollment)
 
 	// Middleware
-	authMiddleware := middleware.AuthMiddleware(cfg.JWTSecretKey)
-	adminMiddleware := middleware.RoleMiddleware("admin")
+	authMiddleware := middleware.AuthMiddleware(keys, authUsecase.ValidateSession, apiKeyUsecase.AuthenticateAPIKey, authUsecase.RecordImpersonatedRequest)
+	can := middleware.RequirePermission
+	noImpersonation := middleware.DenyImpersonation()
+	noAPIKey := middleware.DenyAPIKey()
+
+	auth.Post("/logout", authMiddleware, authHandler.Logout)
 
 	// Alumni routes
 	alumni := api.Group("/alumni", authMiddleware)
-	alumni.Get("/", alumniHandler.GetAllAlumni)
-	alumni.Get("/:id", alumniHandler.GetAlumniByID)
-	alumni.Post("/", adminMiddleware, alumniHandler.CreateAlumni)
-	alumni.Put("/:id", adminMiddleware, alumniHandler.UpdateAlumni)
-	alumni.Delete("/:id", adminMiddleware, alumniHandler.DeleteAlumni)
+	alumni.Get("/", can(domain.PermAlumniRead), alumniHandler.GetAllAlumni)
+	alumni.Get("/export", can(domain.PermAlumniRead), alumniHandler.ExportAlumni)
+	alumni.Get("/:id", can(domain.PermAlumniRead), alumniHandler.GetAlumniByID)
+	alumni.Post("/", can(domain.PermAlumniWrite), alumniHandler.CreateAlumni)
+	alumni.Post("/import", can(domain.PermAlumniWrite), alumniHandler.ImportAlumni)
+	alumni.Put("/:id", can(domain.PermAlumniWrite), alumniHandler.UpdateAlumni)
+	alumni.Delete("/:id", can(domain.PermAlumniWrite), alumniHandler.DeleteAlumni)
+	alumni.Put("/:id/user", can(domain.PermAlumniWrite), alumniHandler.LinkUser)
+	alumni.Get("/:id/history", can(domain.PermAuditRead), historyHandler.GetHistory(domain.AuditEntityAlumni))
+	alumni.Get("/:id/history/diff", can(domain.PermAuditRead), historyHandler.DiffVersions(domain.AuditEntityAlumni))
+	alumni.Post("/:id/history/:version/restore", can(domain.PermAuditRead), can(domain.PermAlumniWrite), historyHandler.RestoreVersion(domain.AuditEntityAlumni))
 
 	// Mahasiswa routes
 	mahasiswa := api.Group("/mahasiswa", authMiddleware)
-	mahasiswa.Get("/", mahasiswaHandler.GetAllMahasiswa)
-	mahasiswa.Get("/:id", mahasiswaHandler.GetMahasiswaByID)
-	mahasiswa.Post("/", adminMiddleware, mahasiswaHandler.CreateMahasiswa)
-	mahasiswa.Put("/:id", adminMiddleware, mahasiswaHandler.UpdateMahasiswa)
-	mahasiswa.Delete("/:id", adminMiddleware, mahasiswaHandler.DeleteMahasiswa)
+	mahasiswa.Get("/", can(domain.PermMahasiswaRead), mahasiswaHandler.GetAllMahasiswa)
+	mahasiswa.Get("/:id", can(domain.PermMahasiswaRead), mahasiswaHandler.GetMahasiswaByID)
+	mahasiswa.Post("/", can(domain.PermMahasiswaWrite), mahasiswaHandler.CreateMahasiswa)
+	mahasiswa.Put("/:id", can(domain.PermMahasiswaWrite), mahasiswaHandler.UpdateMahasiswa)
+	mahasiswa.Delete("/:id", can(domain.PermMahasiswaWrite), mahasiswaHandler.DeleteMahasiswa)
+	mahasiswa.Get("/:id/history", can(domain.PermAuditRead), historyHandler.GetHistory(domain.AuditEntityMahasiswa))
+	mahasiswa.Get("/:id/history/diff", can(domain.PermAuditRead), historyHandler.DiffVersions(domain.AuditEntityMahasiswa))
+	mahasiswa.Post("/:id/history/:version/restore", can(domain.PermAuditRead), can(domain.PermMahasiswaWrite), historyHandler.RestoreVersion(domain.AuditEntityMahasiswa))
 
 	// Pekerjaan routes
 	pekerjaan := api.Group("/pekerjaan", authMiddleware)
-	pekerjaan.Get("/", pekerjaanHandler.GetAllPekerjaan)
-	pekerjaan.Get("/:id", pekerjaanHandler.GetPekerjaanByID)
-	pekerjaan.Post("/", adminMiddleware, pekerjaanHandler.CreatePekerjaan)
-	pekerjaan.Put("/:id", adminMiddleware, pekerjaanHandler.UpdatePekerjaan)
-	pekerjaan.Delete("/:id", adminMiddleware, pekerjaanHandler.DeletePekerjaan)
+	pekerjaan.Get("/", can(domain.PermPekerjaanRead), pekerjaanHandler.GetAllPekerjaan)
+	pekerjaan.Get("/export", can(domain.PermPekerjaanRead), pekerjaanHandler.ExportPekerjaan)
+	pekerjaan.Get("/:id", can(domain.PermPekerjaanRead), pekerjaanHandler.GetPekerjaanByID)
+	pekerjaan.Post("/", can(domain.PermPekerjaanWrite), pekerjaanHandler.CreatePekerjaan)
+	pekerjaan.Put("/:id", can(domain.PermPekerjaanWrite), pekerjaanHandler.UpdatePekerjaan)
+	pekerjaan.Delete("/:id", can(domain.PermPekerjaanWrite), pekerjaanHandler.DeletePekerjaan)
+	pekerjaan.Get("/:id/history", can(domain.PermAuditRead), historyHandler.GetHistory(domain.AuditEntityPekerjaan))
+	pekerjaan.Get("/:id/history/diff", can(domain.PermAuditRead), historyHandler.DiffVersions(domain.AuditEntityPekerjaan))
+	pekerjaan.Post("/:id/history/:version/restore", can(domain.PermAuditRead), can(domain.PermPekerjaanWrite), historyHandler.RestoreVersion(domain.AuditEntityPekerjaan))
+
+	// Self-service routes: kepemilikan data dicek di usecase berdasarkan user di token
+	me := api.Group("/me", authMiddleware)
+	me.Get("/alumni", selfServiceHandler.GetMyAlumni)
+	me.Put("/alumni", selfServiceHandler.UpdateMyAlumni)
+	me.Post("/alumni/claim", selfServiceHandler.ClaimAlumni)
+	me.Post("/alumni/claim/confirm", selfServiceHandler.ConfirmAlumniClaim)
+	me.Get("/pekerjaan", selfServiceHandler.GetMyPekerjaan)
+	me.Post("/pekerjaan", selfServiceHandler.CreateMyPekerjaan)
+	me.Put("/pekerjaan/:id/end", selfServiceHandler.EndMyPekerjaan)
+	me.Get("/api-keys", apiKeyHandler.GetMyAPIKeys)
+	// Kredensial dan keamanan akun tidak bisa diubah oleh admin yang sedang meniru user,
+	// dan hanya bisa diubah dari session login, bukan dengan API key
+	me.Post("/api-keys", noAPIKey, noImpersonation, apiKeyHandler.CreateAPIKey)
+	me.Delete("/api-keys/:id", noAPIKey, noImpersonation, apiKeyHandler.RevokeAPIKey)
+	me.Get("/mfa", authHandler.GetMyMFAStatus)
+	me.Post("/mfa/setup", noAPIKey, noImpersonation, authHandler.SetupMyMFA)
+	me.Post("/mfa/enable", noAPIKey, noImpersonation, authHandler.EnableMyMFA)
+	me.Post("/mfa/disable", noAPIKey, noImpersonation, authHandler.DisableMyMFA)
+	me.Post("/mfa/recovery-codes", noAPIKey, noImpersonation, authHandler.RegenerateMyRecoveryCodes)
+	me.Put("/password", noAPIKey, noImpersonation, authHandler.ChangeMyPassword)
+	me.Put("/email", noAPIKey, noImpersonation, authHandler.ChangeMyEmail)
+	me.Get("/sessions", authHandler.GetMySessions)
+	me.Delete("/sessions", noAPIKey, noImpersonation, authHandler.RevokeMySessions)
+	me.Delete("/sessions/:id", noAPIKey, noImpersonation, authHandler.RevokeMySession)
+
+	// User management routes
+	users := api.Group("/users", authMiddleware, can(domain.PermUsersManage))
+	users.Get("/", userHandler.GetAllUsers)
+	users.Get("/:id", userHandler.GetUserByID)
+	users.Put("/:id/roles", userHandler.UpdateUserRoles)
+	users.Put("/:id/status", userHandler.UpdateUserStatus)
+	users.Put("/:id/verify-email", userHandler.MarkEmailVerified)
+	users.Delete("/:id", userHandler.DeleteUser)
+	users.Delete("/:id/mfa", authHandler.ResetUserMFA)
+	users.Post("/:id/unlock", authHandler.UnlockUser)
+	users.Get("/:id/sessions", authHandler.GetUserSessions)
+	users.Delete("/:id/sessions", authHandler.RevokeUserSessions)
+	users.Delete("/:id/sessions/:sessionID", authHandler.RevokeUserSession)
+
+	// Admin tools
+	admin := api.Group("/admin", authMiddleware, can(domain.PermUsersManage))
+	admin.Post("/impersonate/:userID", noAPIKey, noImpersonation, authHandler.Impersonate)
+
+	// Role & permission management routes
+	roles := api.Group("/roles", authMiddleware, can(domain.PermRolesManage))
+	roles.Get("/", roleHandler.GetAllRoles)
+	roles.Get("/:id", roleHandler.GetRoleByID)
+	roles.Post("/", roleHandler.CreateRole)
+	roles.Put("/:id", roleHandler.UpdateRole)
+	roles.Put("/:id/permissions", roleHandler.UpdateRolePermissions)
+	roles.Delete("/:id", roleHandler.DeleteRole)
+	api.Get("/permissions", authMiddleware, can(domain.PermRolesManage), roleHandler.GetAllPermissions)
+
+	// Audit log perubahan data alumni, mahasiswa dan pekerjaan
+	api.Get("/audit", authMiddleware, can(domain.PermAuditRead), auditHandler.GetAuditLogs)
+
+	// Job background untuk import/export besar; job hanya bisa dilihat pembuatnya kecuali dengan jobs:manage
+	jobs := api.Group("/jobs", authMiddleware)
+	jobs.Get("/", jobHandler.GetJobs)
+	jobs.Post("/alumni-import", can(domain.PermAlumniWrite), jobHandler.EnqueueAlumniImport)
+	jobs.Post("/alumni-export", can(domain.PermAlumniRead), jobHandler.EnqueueAlumniExport)
+	jobs.Post("/pekerjaan-export", can(domain.PermPekerjaanRead), jobHandler.EnqueuePekerjaanExport)
+	jobs.Get("/:id", jobHandler.GetJob)
+	jobs.Get("/:id/download", jobHandler.DownloadJobArtifact)
+	jobs.Post("/:id/cancel", jobHandler.CancelJob)
+
+	// Tracer study: alumni mengisi lewat token dari email undangan, tanpa login
+	api.Get("/tracer/form", tracerHandler.GetForm)
+	api.Post("/tracer/submit", tracerHandler.Submit)
+
+	questionnaires := api.Group("/questionnaires", authMiddleware, can(domain.PermTracerManage))
+	questionnaires.Get("/", tracerHandler.GetQuestionnaires)
+	questionnaires.Post("/", tracerHandler.CreateQuestionnaire)
+	questionnaires.Get("/:id", tracerHandler.GetQuestionnaire)
+	questionnaires.Put("/:id", tracerHandler.UpdateQuestionnaire)
+	questionnaires.Get("/:id/versions", tracerHandler.GetVersions)
+	questionnaires.Post("/:id/versions", tracerHandler.CreateVersion)
+	questionnaires.Get("/:id/versions/:version", tracerHandler.GetVersion)
+	questionnaires.Get("/:id/invitations", tracerHandler.GetInvitations)
+	questionnaires.Post("/:id/invitations", tracerHandler.InviteAlumni)
+	questionnaires.Post("/:id/invitations/:invitationID/resend", tracerHandler.ResendInvitation)
+	questionnaires.Get("/:id/responses", tracerHandler.GetResponses)
+	questionnaires.Get("/:id/stats", tracerHandler.GetStats)
+
+	// Statistik lulusan untuk laporan akreditasi; hanya angka agregat dari data alumni dan pekerjaan
+	stats := api.Group("/stats", authMiddleware, can(domain.PermAlumniRead), can(domain.PermPekerjaanRead))
+	stats.Get("/employment", statsHandler.GetEmployment)
+	stats.Get("/industries", statsHandler.GetIndustryDistribution)
+	stats.Get("/salaries", statsHandler.GetSalaryDistribution)
 }
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis entity dan aksi yang dicatat di audit log
+const (
+	AuditEntityAlumni    = "alumni"
+	AuditEntityMahasiswa = "mahasiswa"
+	AuditEntityPekerjaan = "pekerjaan"
+
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	AuditActionLink   = "link" // akun user dihubungkan/dilepas dari data alumni
+)
+
+// AuditLog adalah satu perubahan data. Before dan After hanya berisi field yang berubah.
+type AuditLog struct {
+	ID             int64           `json:"id"`
+	ActorUserID    *int            `json:"actor_user_id"`
+	ImpersonatorID *int            `json:"impersonator_id,omitempty"`
+	Action         string          `json:"action"`
+	EntityType     string          `json:"entity_type"`
+	EntityID       int             `json:"entity_id"`
+	Before         json.RawMessage `json:"before"`
+	After          json.RawMessage `json:"after"`
+	RequestID      *string         `json:"request_id"`
+	IPAddress      *string         `json:"ip_address"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// AuditMeta adalah informasi siapa dan dari request mana sebuah perubahan dilakukan
+type AuditMeta struct {
+	ActorUserID    *int // nil untuk perubahan oleh sistem
+	ImpersonatorID *int
+	RequestID      string
+	IPAddress      string
+}
+
+// AuditFilter untuk GET /api/audit; field kosong/nil berarti tidak difilter
+type AuditFilter struct {
+	EntityType  string
+	EntityID    *int
+	ActorUserID *int
+	From        *time.Time
+	To          *time.Time
+	Page        int
+	Limit       int
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // Auth DTOs
 type RegisterRequest struct {
 	Email    string `json:"email"`
@@ -11,10 +13,147 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// ClientInfo adalah informasi perangkat yang disimpan di session saat login dan refresh
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// LoginResponse berisi token, atau hanya challenge MFA jika akun wajib memasukkan kode TOTP dulu
 type LoginResponse struct {
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"` // masa berlaku access token dalam detik
+
+	MFARequired           bool   `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"` // role mewajibkan 2FA tetapi belum di-setup
+	MFAToken              string `json:"mfa_token,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangeEmailRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewEmail        string `json:"new_email"`
+}
+
+type ConfirmEmailChangeRequest struct {
 	Token string `json:"token"`
 }
 
+// ImpersonationResponse berisi access token berumur pendek tanpa refresh token
+type ImpersonationResponse struct {
+	Token     string `json:"token"`
+	ExpiresIn int64  `json:"expires_in"`
+	User      *User  `json:"user"` // user yang ditiru
+}
+
+// OIDCAuthRequest berisi URL login identity provider dan state yang disimpan di cookie
+type OIDCAuthRequest struct {
+	AuthURL     string
+	StateCookie string
+	ExpiresAt   time.Time
+}
+
+// MFA DTOs
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"` // dipakai jika authenticator tidak tersedia
+}
+
+type MFAChallengeRequest struct {
+	MFAToken string `json:"mfa_token"`
+}
+
+type MFAEnrollmentConfirmRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+type MFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"` // tampilkan sebagai QR code
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"` // hanya ditampilkan sekali
+}
+
+type MFAEnrollmentResponse struct {
+	LoginResponse
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFAStatusResponse struct {
+	Enabled                bool `json:"enabled"`
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// API key DTOs
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days"` // default 90, maksimal 365
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"` // hanya dikembalikan sekali
+}
+
+// User management DTOs
+type UpdateUserRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+type UpdateUserStatusRequest struct {
+	IsActive bool `json:"is_active"`
+}
+
+// Role management DTOs
+type CreateRoleRequest struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateRoleRequest struct {
+	Name string `json:"name"`
+}
+
+type UpdateRolePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
 // Alumni DTOs
 type CreateAlumniRequest struct {
 	NIM        string  `json:"nim"`
@@ -37,6 +176,31 @@ type UpdateAlumniRequest struct {
 	Alamat     *string `json:"alamat"`
 }
 
+// LinkAlumniUserRequest dipakai admin untuk menghubungkan (atau melepas dengan null) akun ke data alumni
+type LinkAlumniUserRequest struct {
+	UserID *int `json:"user_id"`
+}
+
+// Self-service DTOs
+type ClaimAlumniRequest struct {
+	NIM string `json:"nim"`
+}
+
+type ConfirmAlumniClaimRequest struct {
+	Token string `json:"token"`
+}
+
+// UpdateMyAlumniRequest hanya berisi data kontak; data akademik tetap dikelola admin
+type UpdateMyAlumniRequest struct {
+	Email     string  `json:"email"`
+	NoTelepon *string `json:"no_telepon"`
+	Alamat    *string `json:"alamat"`
+}
+
+type EndPekerjaanRequest struct {
+	TanggalSelesaiKerja string `json:"tanggal_selesai_kerja"` // format YYYY-MM-DD
+}
+
 // Mahasiswa DTOs
 type CreateMahasiswaRequest struct {
 	NIM      string `json:"nim"`
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Error yang perlu dibedakan oleh layer di atas repository
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidEmail        = errors.New("invalid email address")
+	ErrEmailTaken          = errors.New("email address is already in use")
+	ErrEmailUnchanged      = errors.New("new email address is the same as the current one")
+	ErrWrongPassword       = errors.New("current password is incorrect")
+	ErrWeakPassword        = errors.New("password does not meet the password policy")
+	ErrAuthUnavailable     = errors.New("authentication service is temporarily unavailable")
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrRolesRequired       = errors.New("at least one role is required")
+	ErrInvalidRoleName     = errors.New("role name must be between 1 and 50 characters")
+	ErrRoleExists          = errors.New("role already exists")
+	ErrProtectedRole       = errors.New("built-in role cannot be changed this way")
+	ErrPermissionNotFound  = errors.New("permission not found")
+	ErrAlumniNotFound      = errors.New("alumni not found")
+	ErrMahasiswaNotFound   = errors.New("mahasiswa not found")
+	ErrPekerjaanNotFound   = errors.New("pekerjaan not found")
+	ErrAlumniNotLinked     = errors.New("account is not linked to any alumni record")
+	ErrAlumniAlreadyLinked = errors.New("alumni record or account is already linked")
+	ErrInvalidClaimToken   = errors.New("invalid or expired alumni claim token")
+	ErrNotOwner            = errors.New("resource does not belong to the current user")
+	ErrLastAdmin           = errors.New("cannot remove or disable the last active admin")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session revoked")
+	ErrInvalidResetToken   = errors.New("invalid or expired password reset token")
+	ErrInvalidVerification = errors.New("invalid or expired email verification link")
+	ErrInvalidEmailChange  = errors.New("invalid or expired email change link")
+	ErrEmailNotVerified    = errors.New("email address has not been verified")
+	ErrAccountDisabled     = errors.New("account is disabled")
+	ErrInvalidMFAChallenge = errors.New("invalid or expired MFA challenge")
+	ErrInvalidMFACode      = errors.New("invalid authentication code")
+	ErrMFANotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMFASetupRequired    = errors.New("start two-factor setup first")
+	ErrMFARequired         = errors.New("two-factor authentication is required for your role")
+	ErrAccountLocked       = errors.New("too many failed login attempts, try again later")
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrInvalidAPIKey       = errors.New("invalid, expired or revoked api key")
+	ErrInvalidAPIKeyName   = errors.New("api key name must be between 1 and 100 characters")
+	ErrInvalidAPIKeyExpiry = errors.New("expires_in_days must be between 1 and 365")
+	ErrScopesRequired      = errors.New("at least one scope is required")
+	ErrScopeNotGranted     = errors.New("scope is not granted to your account")
+	ErrAPIKeyNotAllowed    = errors.New("this action requires a login session, not an api key")
+	ErrImpersonationDenied = errors.New("only admins with a login session can impersonate users")
+	ErrCannotImpersonate   = errors.New("this user cannot be impersonated")
+	ErrImpersonating       = errors.New("this action is not allowed while impersonating another user")
+	ErrInvalidSSOState     = errors.New("invalid or expired sso login state")
+	ErrSSOLoginFailed      = errors.New("sso login failed")
+	ErrSSOEmailNotVerified = errors.New("identity provider did not return a verified email address")
+	ErrInvalidAuditFilter  = errors.New("invalid audit filter: from must be before to")
+	ErrVersionNotFound     = errors.New("version not found")
+	ErrInvalidFilter       = errors.New("invalid filter value")
+	ErrInvalidExportFormat = errors.New("invalid export format, use csv, xlsx or pdf")
+	ErrInvalidExportColumn = errors.New("unknown export column")
+	ErrExportTooManyRows   = errors.New("export has too many rows for pdf, use csv or xlsx")
+	ErrImportUnreadable    = errors.New("cannot read import file")
+	ErrImportEmpty         = errors.New("import file has no data rows")
+	ErrImportTooManyRows   = errors.New("import file has too many rows")
+	ErrImportMissingColumn = errors.New("import file is missing required columns")
+	ErrJobNotFound         = errors.New("job not found")
+	ErrJobNotCancellable   = errors.New("job has already finished")
+	ErrJobNoArtifact       = errors.New("job has no downloadable result")
+	ErrJobLost             = errors.New("job is no longer held by this worker")
+	ErrInvalidJobFilter    = errors.New("invalid job filter")
+	ErrTracerNotFound      = errors.New("questionnaire not found")
+	ErrInvalidQuestions    = errors.New("invalid questionnaire")
+	ErrTracerNoVersion     = errors.New("questionnaire has no questions yet")
+	ErrTracerPeriodLocked  = errors.New("questionnaire period cannot be changed after it has submissions")
+	ErrTracerClosed        = errors.New("questionnaire is not open")
+	ErrInvalidInvitation   = errors.New("invalid or expired invitation")
+	ErrInvitationNotFound  = errors.New("invitation not found")
+	ErrAlreadySubmitted    = errors.New("questionnaire has already been filled in for this period")
+	ErrInvalidAnswers      = errors.New("invalid answers")
+	ErrInvalidStatsFilter  = errors.New("invalid stats filter")
+)
+
+// LockedError dikembalikan saat akun atau IP sedang dikunci; errors.Is(err, ErrAccountLocked) bernilai true
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return ErrAccountLocked.Error()
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
+
+// PasswordViolation adalah satu aturan kebijakan password yang dilanggar
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError berisi semua aturan yang dilanggar; errors.Is(err, ErrWeakPassword) bernilai true
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return ErrWeakPassword.Error()
+}
+
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
+
+// AnswerValidationError berisi semua kesalahan jawaban kuesioner; errors.Is(err, ErrInvalidAnswers) bernilai true
+type AnswerValidationError struct {
+	Errors []string
+}
+
+func (e *AnswerValidationError) Error() string {
+	return ErrInvalidAnswers.Error()
+}
+
+func (e *AnswerValidationError) Is(target error) bool {
+	return target == ErrInvalidAnswers
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// HistoryActionBaseline menandai versi awal sebelum perubahan pertama yang tercatat di audit log,
+// yaitu untuk data yang sudah ada sebelum audit log diaktifkan
+const HistoryActionBaseline = "baseline"
+
+// EntityVersion adalah isi lengkap sebuah data setelah satu perubahan. Versi 1 adalah yang paling lama.
+type EntityVersion struct {
+	Version        int                    `json:"version"`
+	AuditLogID     *int64                 `json:"audit_log_id"` // nil untuk versi baseline
+	Action         string                 `json:"action"`
+	ActorUserID    *int                   `json:"actor_user_id"`
+	ImpersonatorID *int                   `json:"impersonator_id,omitempty"`
+	ChangedFields  []string               `json:"changed_fields"`
+	Data           map[string]interface{} `json:"data"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// FieldChange adalah nilai satu field pada dua versi yang dibandingkan
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// VersionDiff adalah hasil GET /:id/history/diff
+type VersionDiff struct {
+	EntityType  string        `json:"entity_type"`
+	EntityID    int           `json:"entity_id"`
+	FromVersion int           `json:"from_version"`
+	ToVersion   int           `json:"to_version"`
+	Changes     []FieldChange `json:"changes"`
+}
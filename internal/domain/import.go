@@ -0,0 +1,20 @@
+package domain
+
+// ImportRowError berisi semua kesalahan pada satu baris file import.
+// Row adalah nomor baris di file, baris 1 adalah header.
+type ImportRowError struct {
+	Row    int      `json:"row"`
+	NIM    string   `json:"nim"`
+	Errors []string `json:"errors"`
+}
+
+// ImportReport adalah hasil import alumni. Pada dry run tidak ada data yang disimpan
+// dan Imported selalu 0; baris yang tidak valid tidak pernah disimpan.
+type ImportReport struct {
+	DryRun      bool             `json:"dry_run"`
+	TotalRows   int              `json:"total_rows"`
+	ValidRows   int              `json:"valid_rows"`
+	InvalidRows int              `json:"invalid_rows"`
+	Imported    int              `json:"imported"`
+	Errors      []ImportRowError `json:"errors"`
+}
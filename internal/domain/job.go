@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis dan status job background
+const (
+	JobTypeAlumniImport    = "alumni_import"
+	JobTypeAlumniExport    = "alumni_export"
+	JobTypePekerjaanExport = "pekerjaan_export"
+
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+// Job adalah pekerjaan panjang yang dijalankan worker di luar request HTTP.
+// Result berisi hasil berbentuk JSON (misalnya laporan import), file hasil diunduh lewat artifact.
+type Job struct {
+	ID              int64           `json:"id"`
+	Type            string          `json:"type"`
+	Status          string          `json:"status"`
+	Payload         json.RawMessage `json:"payload"`
+	ProgressDone    int             `json:"progress_done"`
+	ProgressTotal   int             `json:"progress_total"` // 0 jika belum diketahui
+	Attempts        int             `json:"attempts"`
+	MaxAttempts     int             `json:"max_attempts"`
+	LastError       *string         `json:"last_error"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	ArtifactName    *string         `json:"artifact_name,omitempty"`
+	CancelRequested bool            `json:"cancel_requested"`
+	CreatedBy       *int            `json:"created_by"`
+	RunAt           time.Time       `json:"run_at"`
+	StartedAt       *time.Time      `json:"started_at"`
+	FinishedAt      *time.Time      `json:"finished_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+
+	// Hanya dipakai server dan worker
+	InputPath           *string `json:"-"`
+	ArtifactPath        *string `json:"-"`
+	ArtifactContentType *string `json:"-"`
+	ImpersonatorID      *int    `json:"-"`
+	RequestID           *string `json:"-"`
+	IPAddress           *string `json:"-"`
+}
+
+// AuditMeta mencatat perubahan data oleh job atas nama user dan request yang membuatnya
+func (j *Job) AuditMeta() AuditMeta {
+	meta := AuditMeta{ActorUserID: j.CreatedBy, ImpersonatorID: j.ImpersonatorID}
+	if j.RequestID != nil {
+		meta.RequestID = *j.RequestID
+	}
+	if j.IPAddress != nil {
+		meta.IPAddress = *j.IPAddress
+	}
+	return meta
+}
+
+// Finished bernilai true jika job tidak akan dijalankan lagi
+func (j *Job) Finished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
+
+// JobFilter untuk GET /api/jobs; CreatedBy nil berarti job semua user
+type JobFilter struct {
+	CreatedBy *int
+	Type      string
+	Status    string
+	Page      int
+	Limit     int
+}
@@ -4,18 +4,89 @@ import "time"
 
 // User represents a user in the system
 type User struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` // Jangan expose password hash
-	Roles        []string  `json:"roles"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              int        `json:"id"`
+	Email           string     `json:"email"`
+	PasswordHash    string     `json:"-"` // Jangan expose password hash
+	Roles           []string   `json:"roles"`
+	Permissions     []string   `json:"permissions"`
+	IsActive        bool       `json:"is_active"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	MFAEnabled      bool       `json:"mfa_enabled"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// HasRole bernilai true jika user memiliki role dengan nama tersebut
+func (u *User) HasRole(name string) bool {
+	for _, r := range u.Roles {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Session represents one login; every refresh token rotated from it belongs to the same session
+type Session struct {
+	ID             string     `json:"id"`
+	UserID         int        `json:"user_id"`
+	UserAgent      string     `json:"user_agent"`
+	IPAddress      string     `json:"ip_address"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastSeenAt     time.Time  `json:"last_seen_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	RevokedReason  *string    `json:"revoked_reason"`
+	ImpersonatorID *int       `json:"impersonator_id,omitempty"` // admin yang membuat session ini lewat impersonation
+	Current        bool       `json:"current"`                   // true untuk session milik token yang sedang dipakai
+}
+
+// UserMFA menyimpan secret TOTP milik user; EnabledAt nil berarti setup belum dikonfirmasi
+type UserMFA struct {
+	UserID       int
+	Secret       string
+	EnabledAt    *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+// AuthEvent mencatat kejadian keamanan pada akun, misalnya lockout dan unlock
+type AuthEvent struct {
+	ID        int64     `json:"id"`
+	EventType string    `json:"event_type"`
+	UserID    *int      `json:"user_id"`
+	ActorID   *int      `json:"actor_id"` // admin yang melakukan aksi, nil jika oleh sistem
+	Email     *string   `json:"email"`
+	IPAddress *string   `json:"ip_address"`
+	Detail    *string   `json:"detail"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// APIKey adalah key pribadi untuk akses mesin-ke-mesin; key aslinya hanya ditampilkan sekali saat dibuat
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"` // permission yang boleh dipakai key ini
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
 
 // Role represents a user role
 type Role struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// Permission represents a single action that can be granted to a role
+type Permission struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 // Alumni represents alumni data
@@ -29,6 +100,7 @@ type Alumni struct {
 	Email      string    `json:"email"`
 	NoTelepon  *string   `json:"no_telepon"`
 	Alamat     *string   `json:"alamat"`
+	UserID     *int      `json:"user_id"` // akun yang terhubung (self-service), nil jika belum ada
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -2,12 +2,20 @@ package domain
 
 // PaginationParams holds the parameters for pagination, sorting, and searching.
 type PaginationParams struct {
-	Page   int
-	Limit  int
-	Sort   string
-	Search string
+	Page    int
+	Limit   int
+	Sort    string
+	Search  string
+	Filters map[string]string // filter kolom dari query string; hanya kolom di AlumniFilters/PekerjaanFilters
 }
 
+// Filter yang didukung listing dan export; nilai true berarti nilainya harus angka
+var (
+	AlumniFilters    = map[string]bool{"jurusan": false, "angkatan": true, "tahun_lulus": true}
+	PekerjaanFilters = map[string]bool{"alumni_id": true, "bidang_industri": false, "status_pekerjaan": false}
+	JobFilters       = map[string]bool{"type": false, "status": false, "created_by": true}
+)
+
 // PaginationResult is a generic struct for paginated responses.
 type PaginationResult[T any] struct {
 	Data     []T   `json:"data"`
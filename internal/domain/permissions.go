@@ -0,0 +1,31 @@
+package domain
+
+// Nama permission yang dicek oleh middleware.RequirePermission.
+// Daftar lengkapnya tersimpan di tabel permissions.
+const (
+	PermAlumniRead     = "alumni:read"
+	PermAlumniWrite    = "alumni:write"
+	PermMahasiswaRead  = "mahasiswa:read"
+	PermMahasiswaWrite = "mahasiswa:write"
+	PermPekerjaanRead  = "pekerjaan:read"
+	PermPekerjaanWrite = "pekerjaan:write"
+	PermUsersManage    = "users:manage"
+	PermRolesManage    = "roles:manage"
+	PermAuditRead      = "audit:read"
+	PermJobsManage     = "jobs:manage" // melihat dan membatalkan job milik user lain
+	PermTracerManage   = "tracer:manage"
+)
+
+// ImpersonationPermissions adalah satu-satunya permission yang boleh ikut di token impersonation.
+// Permission lain, termasuk permission baru yang belum didaftarkan di sini, selalu dibuang.
+var ImpersonationPermissions = []string{
+	PermAlumniRead, PermAlumniWrite,
+	PermMahasiswaRead, PermMahasiswaWrite,
+	PermPekerjaanRead, PermPekerjaanWrite,
+}
+
+// Role bawaan yang tidak boleh dihapus atau diganti namanya
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
@@ -0,0 +1,43 @@
+package domain
+
+// StatsFilter membatasi alumni yang dihitung di /api/stats; field kosong berarti tidak difilter
+type StatsFilter struct {
+	Jurusan        string
+	Angkatan       *int
+	TahunLulusFrom *int
+	TahunLulusTo   *int
+	Job            string // pekerjaan yang dihitung di distribusi: "latest" (default) atau "first"
+}
+
+// EmploymentStats menghitung alumni yang pernah bekerja dan masa tunggu dari lulus sampai pekerjaan pertama
+type EmploymentStats struct {
+	TotalAlumni           int      `json:"total_alumni"`
+	Employed              int      `json:"employed"`           // punya minimal satu data pekerjaan
+	CurrentlyEmployed     int      `json:"currently_employed"` // punya pekerjaan yang belum berakhir
+	EmploymentRate        float64  `json:"employment_rate"`    // employed / total_alumni (0-1)
+	AvgWaitingMonths      *float64 `json:"avg_waiting_months"` // nil jika belum ada yang bekerja
+	MedianWaitingMonths   *float64 `json:"median_waiting_months"`
+	EmployedWithin6Months int      `json:"employed_within_6_months"`
+}
+
+type CohortEmploymentStats struct {
+	TahunLulus int `json:"tahun_lulus"`
+	EmploymentStats
+}
+
+type EmploymentSummary struct {
+	EmploymentStats
+	ByTahunLulus []CohortEmploymentStats `json:"by_tahun_lulus"`
+}
+
+// StatsBucket adalah jumlah alumni untuk satu nilai; Group nil berarti datanya tidak diisi
+type StatsBucket struct {
+	Group      *string `json:"group"`
+	Count      int     `json:"count"`
+	Percentage float64 `json:"percentage"` // 0-1
+}
+
+type StatsDistribution struct {
+	Total   int           `json:"total"`
+	Buckets []StatsBucket `json:"buckets"`
+}
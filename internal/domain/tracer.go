@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis pertanyaan kuesioner tracer study
+const (
+	QuestionSingleChoice   = "single_choice"
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionScale          = "scale"
+	QuestionText           = "text"
+)
+
+// Questionnaire adalah kuesioner tracer study untuk satu periode
+type Questionnaire struct {
+	ID             int        `json:"id"`
+	Title          string     `json:"title"`
+	Description    *string    `json:"description"`
+	Period         string     `json:"period"`
+	OpensAt        *time.Time `json:"opens_at"`        // nil = langsung dibuka
+	ClosesAt       *time.Time `json:"closes_at"`       // nil = tidak ditutup
+	CurrentVersion *int       `json:"current_version"` // versi yang dipakai untuk pengisian baru, nil jika belum ada pertanyaan
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Question adalah satu pertanyaan. Code menjadi kunci jawaban dan sebaiknya tetap sama antar versi.
+type Question struct {
+	Code     string             `json:"code"`
+	Type     string             `json:"type"`
+	Prompt   string             `json:"prompt"`
+	Required bool               `json:"required"`
+	Options  []string           `json:"options,omitempty"`   // single_choice dan multiple_choice
+	ScaleMin int                `json:"scale_min,omitempty"` // scale
+	ScaleMax int                `json:"scale_max,omitempty"`
+	ShowIf   *QuestionCondition `json:"show_if,omitempty"`
+}
+
+// QuestionCondition menampilkan pertanyaan hanya jika jawaban pertanyaan sebelumnya salah satu dari Equals.
+// Untuk multiple_choice cukup salah satu pilihan yang cocok; untuk scale nilainya ditulis sebagai teks.
+type QuestionCondition struct {
+	Question string   `json:"question"`
+	Equals   []string `json:"equals"`
+}
+
+// QuestionnaireVersion adalah set pertanyaan yang tidak bisa diubah setelah dibuat
+type QuestionnaireVersion struct {
+	ID              int        `json:"id"`
+	QuestionnaireID int        `json:"questionnaire_id"`
+	Version         int        `json:"version"`
+	Questions       []Question `json:"questions"`
+	CreatedBy       *int       `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type QuestionnaireInvitation struct {
+	ID              int        `json:"id"`
+	QuestionnaireID int        `json:"questionnaire_id"`
+	AlumniID        int        `json:"alumni_id"`
+	AlumniNIM       string     `json:"alumni_nim"`
+	AlumniNama      string     `json:"alumni_nama"`
+	Email           string     `json:"email"`
+	SentAt          *time.Time `json:"sent_at"` // nil jika email undangan gagal dikirim
+	SubmittedAt     *time.Time `json:"submitted_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// QuestionnaireSubmission adalah jawaban mentah satu alumni
+type QuestionnaireSubmission struct {
+	ID              int             `json:"id"`
+	QuestionnaireID int             `json:"questionnaire_id"`
+	Version         int             `json:"version"`
+	AlumniID        int             `json:"alumni_id"`
+	AlumniNIM       string          `json:"alumni_nim"`
+	AlumniNama      string          `json:"alumni_nama"`
+	Jurusan         string          `json:"jurusan"`
+	TahunLulus      int             `json:"tahun_lulus"`
+	Period          string          `json:"period"`
+	Answers         json.RawMessage `json:"answers"`
+	SubmittedAt     time.Time       `json:"submitted_at"`
+}
+
+// ResponseRate adalah jumlah undangan dan pengisian untuk satu kelompok alumni
+type ResponseRate struct {
+	Group     string  `json:"group"`
+	Invited   int     `json:"invited"`
+	Submitted int     `json:"submitted"`
+	Rate      float64 `json:"rate"` // 0-1
+}
+
+type QuestionnaireStats struct {
+	Invited      int            `json:"invited"`
+	Submitted    int            `json:"submitted"`
+	Rate         float64        `json:"rate"`
+	ByJurusan    []ResponseRate `json:"by_jurusan"`
+	ByTahunLulus []ResponseRate `json:"by_tahun_lulus"`
+}
+
+// Questionnaire DTOs
+type QuestionnaireRequest struct {
+	Title       string     `json:"title"`
+	Description *string    `json:"description"`
+	Period      string     `json:"period"`
+	OpensAt     *time.Time `json:"opens_at"`
+	ClosesAt    *time.Time `json:"closes_at"`
+}
+
+type CreateQuestionnaireVersionRequest struct {
+	Questions []Question `json:"questions"`
+}
+
+// InviteAlumniRequest memilih alumni lewat AlumniIDs, atau lewat filter jika AlumniIDs kosong.
+// Tanpa AlumniIDs dan filter semua alumni diundang.
+type InviteAlumniRequest struct {
+	AlumniIDs  []int  `json:"alumni_ids"`
+	Jurusan    string `json:"jurusan"`
+	Angkatan   int    `json:"angkatan"`
+	TahunLulus int    `json:"tahun_lulus"`
+}
+
+// InviteAlumniResult: alumni yang sudah pernah diundang tidak diundang ulang
+type InviteAlumniResult struct {
+	Invited        int `json:"invited"`
+	AlreadyInvited int `json:"already_invited"`
+	EmailFailed    int `json:"email_failed"`
+}
+
+// TracerForm adalah kuesioner yang ditampilkan ke alumni pemilik token undangan
+type TracerForm struct {
+	QuestionnaireID int        `json:"questionnaire_id"`
+	Title           string     `json:"title"`
+	Description     *string    `json:"description"`
+	Period          string     `json:"period"`
+	ClosesAt        *time.Time `json:"closes_at"`
+	Version         int        `json:"version"`
+	Questions       []Question `json:"questions"`
+	AlumniNama      string     `json:"alumni_nama"`
+}
+
+// SubmitTracerRequest: Version adalah versi yang ditampilkan ke alumni; 0 berarti versi terbaru
+type SubmitTracerRequest struct {
+	Token   string                     `json:"token"`
+	Version int                        `json:"version"`
+	Answers map[string]json.RawMessage `json:"answers"`
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS pekerjaan;
+DROP TABLE IF EXISTS mahasiswa;
+DROP TABLE IF EXISTS alumni;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS roles;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,63 @@
+CREATE TABLE IF NOT EXISTS users (
+    id            SERIAL PRIMARY KEY,
+    email         VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id   SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+
+INSERT INTO roles (name) VALUES ('admin'), ('user') ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS alumni (
+    id          SERIAL PRIMARY KEY,
+    nim         VARCHAR(20) NOT NULL UNIQUE,
+    nama        VARCHAR(100) NOT NULL,
+    jurusan     VARCHAR(100) NOT NULL,
+    angkatan    INTEGER NOT NULL,
+    tahun_lulus INTEGER NOT NULL,
+    email       VARCHAR(255) NOT NULL UNIQUE,
+    no_telepon  VARCHAR(20),
+    alamat      TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS mahasiswa (
+    id         SERIAL PRIMARY KEY,
+    nim        VARCHAR(20) NOT NULL UNIQUE,
+    nama       VARCHAR(100) NOT NULL,
+    jurusan    VARCHAR(100) NOT NULL,
+    angkatan   INTEGER NOT NULL,
+    email      VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS pekerjaan (
+    id                    SERIAL PRIMARY KEY,
+    alumni_id             INTEGER NOT NULL REFERENCES alumni (id) ON DELETE CASCADE,
+    nama_perusahaan       VARCHAR(100) NOT NULL,
+    posisi_jabatan        VARCHAR(100) NOT NULL,
+    bidang_industri       VARCHAR(50) NOT NULL,
+    lokasi_kerja          VARCHAR(100) NOT NULL,
+    gaji_range            VARCHAR(50),
+    tanggal_mulai_kerja   DATE NOT NULL,
+    tanggal_selesai_kerja DATE,
+    status_pekerjaan      VARCHAR(50) NOT NULL,
+    deskripsi_pekerjaan   TEXT,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pekerjaan_alumni_id ON pekerjaan (alumni_id);
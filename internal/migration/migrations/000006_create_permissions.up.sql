@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id INTEGER NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('alumni:read', 'Melihat data alumni'),
+    ('alumni:write', 'Menambah, mengubah dan menghapus data alumni'),
+    ('mahasiswa:read', 'Melihat data mahasiswa'),
+    ('mahasiswa:write', 'Menambah, mengubah dan menghapus data mahasiswa'),
+    ('pekerjaan:read', 'Melihat data pekerjaan alumni'),
+    ('pekerjaan:write', 'Menambah, mengubah dan menghapus data pekerjaan alumni'),
+    ('users:manage', 'Mengelola akun pengguna'),
+    ('roles:manage', 'Mengelola role dan permission')
+ON CONFLICT (name) DO NOTHING;
+
+-- admin mendapat semua permission, user hanya permission baca
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'user' AND p.name IN ('alumni:read', 'mahasiswa:read', 'pekerjaan:read')
+ON CONFLICT DO NOTHING;
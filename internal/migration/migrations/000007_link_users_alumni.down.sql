@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS alumni_claims;
+ALTER TABLE alumni DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,15 @@
+-- Satu akun hanya boleh terhubung ke satu data alumni dan sebaliknya
+ALTER TABLE alumni ADD COLUMN IF NOT EXISTS user_id INTEGER UNIQUE REFERENCES users (id) ON DELETE SET NULL;
+
+-- Klaim data alumni oleh akun: link konfirmasi dikirim ke email yang tercatat di data alumni
+CREATE TABLE IF NOT EXISTS alumni_claims (
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    alumni_id  INTEGER NOT NULL REFERENCES alumni (id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alumni_claims_user_id ON alumni_claims (user_id);
@@ -0,0 +1,23 @@
+-- Penghitung gagal login per akun (email) dan per alamat IP
+CREATE TABLE IF NOT EXISTS login_failures (
+    scope          VARCHAR(10) NOT NULL, -- 'account' atau 'ip'
+    key            VARCHAR(255) NOT NULL,
+    failure_count  INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until   TIMESTAMPTZ,
+    PRIMARY KEY (scope, key)
+);
+
+-- Catatan kejadian keamanan seperti lockout dan unlock
+CREATE TABLE IF NOT EXISTS auth_events (
+    id         BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    user_id    INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    actor_id   INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    email      VARCHAR(255),
+    ip_address VARCHAR(45),
+    detail     TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_events_user_id ON auth_events (user_id);
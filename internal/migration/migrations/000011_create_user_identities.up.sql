@@ -0,0 +1,13 @@
+-- Akun di identity provider eksternal (SSO) yang terhubung ke user lokal
+CREATE TABLE IF NOT EXISTS user_identities (
+    id            SERIAL PRIMARY KEY,
+    user_id       INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider      VARCHAR(50) NOT NULL,
+    subject       VARCHAR(255) NOT NULL, -- claim "sub" dari ID token
+    email         VARCHAR(255) NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
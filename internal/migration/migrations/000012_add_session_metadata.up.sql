@@ -0,0 +1,9 @@
+-- Informasi perangkat untuk daftar session aktif di /api/me/sessions
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS user_agent VARCHAR(512) NOT NULL DEFAULT '';
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45) NOT NULL DEFAULT '';
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
+
+-- Diperbarui setiap refresh token dirotasi
+UPDATE user_sessions SET last_seen_at = created_at WHERE last_seen_at IS NULL;
+ALTER TABLE user_sessions ALTER COLUMN last_seen_at SET DEFAULT NOW();
+ALTER TABLE user_sessions ALTER COLUMN last_seen_at SET NOT NULL;
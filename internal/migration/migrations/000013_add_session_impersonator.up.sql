@@ -0,0 +1,2 @@
+-- Session yang dibuat admin lewat /api/admin/impersonate; NULL untuk login biasa
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS impersonator_id INT REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,42 @@
+-- Riwayat perubahan data (alumni, mahasiswa, pekerjaan). Ditulis dalam transaksi yang sama dengan perubahannya.
+-- actor_user_id dan impersonator_id sengaja tanpa foreign key agar log tetap utuh saat user dihapus.
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id              BIGSERIAL PRIMARY KEY,
+    actor_user_id   INTEGER,
+    impersonator_id INTEGER, -- admin yang sedang meniru actor, jika ada
+    action          VARCHAR(20) NOT NULL, -- create, update, delete, link
+    entity_type     VARCHAR(50) NOT NULL,
+    entity_id       INTEGER NOT NULL,
+    before_data     JSONB, -- nilai lama field yang berubah; NULL untuk create
+    after_data      JSONB, -- nilai baru field yang berubah; NULL untuk delete
+    request_id      VARCHAR(64),
+    ip_address      VARCHAR(45),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs (actor_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+-- Append-only: baris yang sudah ditulis tidak bisa diubah atau dihapus
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_no_update ON audit_logs;
+CREATE TRIGGER audit_logs_no_update BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+
+DROP TRIGGER IF EXISTS audit_logs_no_truncate ON audit_logs;
+CREATE TRIGGER audit_logs_no_truncate BEFORE TRUNCATE ON audit_logs
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
+
+INSERT INTO permissions (name, description) VALUES
+    ('audit:read', 'Melihat audit log perubahan data')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin' AND p.name = 'audit:read'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DELETE FROM permissions WHERE name = 'jobs:manage';
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,43 @@
+-- Antrian job background (import/export besar). Worker mengambil job dengan FOR UPDATE SKIP LOCKED
+-- dan memperpanjang locked_until selama job berjalan; job running yang lease-nya habis diambil ulang.
+CREATE TABLE IF NOT EXISTS jobs (
+    id                    BIGSERIAL PRIMARY KEY,
+    type                  VARCHAR(50) NOT NULL,
+    status                VARCHAR(20) NOT NULL DEFAULT 'queued', -- queued, running, succeeded, failed, cancelled
+    payload               JSONB NOT NULL DEFAULT '{}',
+    input_path            TEXT, -- file upload yang diproses job, dihapus setelah job selesai
+    progress_done         INTEGER NOT NULL DEFAULT 0,
+    progress_total        INTEGER NOT NULL DEFAULT 0, -- 0 jika belum diketahui
+    attempts              INTEGER NOT NULL DEFAULT 0,
+    max_attempts          INTEGER NOT NULL DEFAULT 3,
+    last_error            TEXT,
+    result                JSONB,
+    artifact_path         TEXT,
+    artifact_name         VARCHAR(255),
+    artifact_content_type VARCHAR(100),
+    cancel_requested      BOOLEAN NOT NULL DEFAULT FALSE,
+    run_at                TIMESTAMPTZ NOT NULL DEFAULT NOW(), -- waktu paling cepat job boleh diambil (backoff retry)
+    locked_by             VARCHAR(100),
+    locked_until          TIMESTAMPTZ,
+    created_by            INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    impersonator_id       INTEGER,
+    request_id            VARCHAR(64),
+    ip_address            VARCHAR(45),
+    started_at            TIMESTAMPTZ,
+    finished_at           TIMESTAMPTZ,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_queued ON jobs (run_at, id) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs (locked_until) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_jobs_created_by ON jobs (created_by, created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_finished_at ON jobs (finished_at) WHERE finished_at IS NOT NULL;
+
+INSERT INTO permissions (name, description) VALUES
+    ('jobs:manage', 'Melihat dan membatalkan job background milik semua user')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin' AND p.name = 'jobs:manage'
+ON CONFLICT DO NOTHING;
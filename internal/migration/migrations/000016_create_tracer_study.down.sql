@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE name = 'tracer:manage';
+DROP TABLE IF EXISTS questionnaire_submissions;
+DROP TABLE IF EXISTS questionnaire_invitations;
+DROP TABLE IF EXISTS questionnaire_versions;
+DROP TABLE IF EXISTS questionnaires;
@@ -0,0 +1,56 @@
+-- Kuesioner tracer study. Pertanyaan disimpan per versi dan versi yang sudah dibuat tidak pernah diubah,
+-- sehingga setiap jawaban selalu bisa dibaca dengan set pertanyaan yang dipakai saat mengisi.
+CREATE TABLE IF NOT EXISTS questionnaires (
+    id          SERIAL PRIMARY KEY,
+    title       VARCHAR(200) NOT NULL,
+    description TEXT,
+    period      VARCHAR(20) NOT NULL, -- misalnya "2026"; satu alumni hanya bisa mengisi sekali per periode
+    opens_at    TIMESTAMPTZ,
+    closes_at   TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS questionnaire_versions (
+    id               SERIAL PRIMARY KEY,
+    questionnaire_id INTEGER NOT NULL REFERENCES questionnaires(id) ON DELETE CASCADE,
+    version          INTEGER NOT NULL,
+    questions        JSONB NOT NULL,
+    created_by       INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (questionnaire_id, version)
+);
+
+-- Undangan per alumni; token hanya dikirim lewat email, yang disimpan hash-nya
+CREATE TABLE IF NOT EXISTS questionnaire_invitations (
+    id               SERIAL PRIMARY KEY,
+    questionnaire_id INTEGER NOT NULL REFERENCES questionnaires(id) ON DELETE CASCADE,
+    alumni_id        INTEGER NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    token_hash       VARCHAR(64) NOT NULL UNIQUE,
+    sent_at          TIMESTAMPTZ,
+    submitted_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (questionnaire_id, alumni_id)
+);
+
+CREATE TABLE IF NOT EXISTS questionnaire_submissions (
+    id               SERIAL PRIMARY KEY,
+    questionnaire_id INTEGER NOT NULL REFERENCES questionnaires(id) ON DELETE CASCADE,
+    version_id       INTEGER NOT NULL REFERENCES questionnaire_versions(id),
+    invitation_id    INTEGER REFERENCES questionnaire_invitations(id) ON DELETE SET NULL,
+    alumni_id        INTEGER NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    period           VARCHAR(20) NOT NULL, -- disalin dari questionnaires agar unik per alumni per periode
+    answers          JSONB NOT NULL,
+    submitted_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (alumni_id, period)
+);
+
+CREATE INDEX IF NOT EXISTS idx_questionnaire_submissions_questionnaire ON questionnaire_submissions (questionnaire_id, submitted_at);
+
+INSERT INTO permissions (name, description) VALUES
+    ('tracer:manage', 'Mengelola kuesioner tracer study, undangan, dan melihat jawaban')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin' AND p.name = 'tracer:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+-- Email dibandingkan tanpa membedakan huruf besar/kecil; gagal jika sudah ada email kembar
+-- yang hanya berbeda huruf besar/kecil, akun tersebut harus digabung atau diubah dulu
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (lower(email));
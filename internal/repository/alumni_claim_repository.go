@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type alumniClaimRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAlumniClaimRepository(db *pgxpool.Pool) AlumniClaimRepository {
+	return &alumniClaimRepository{db: db}
+}
+
+func (r *alumniClaimRepository) Create(ctx context.Context, userID, alumniID int, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO alumni_claims (user_id, alumni_id, token_hash, expires_at) VALUES ($1, $2, $3, $4)`
+	_, err := r.db.Exec(ctx, query, userID, alumniID, tokenHash, expiresAt)
+	return err
+}
+
+func (r *alumniClaimRepository) Consume(ctx context.Context, userID int, tokenHash string, meta domain.AuditMeta) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var claimID, alumniID int
+	query := `
+		SELECT id, alumni_id FROM alumni_claims
+		WHERE token_hash = $1 AND user_id = $2 AND used_at IS NULL AND expires_at > NOW()
+		FOR UPDATE`
+	err = tx.QueryRow(ctx, query, tokenHash, userID).Scan(&claimID, &alumniID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, domain.ErrInvalidClaimToken
+		}
+		return 0, err
+	}
+
+	if err := linkUnclaimedAlumni(ctx, tx, userID, alumniID, meta); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE alumni_claims SET used_at = NOW() WHERE id = $1`, claimID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return alumniID, nil
+}
+
+func (r *alumniClaimRepository) LinkIfUnclaimed(ctx context.Context, userID, alumniID int, meta domain.AuditMeta) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := linkUnclaimedAlumni(ctx, tx, userID, alumniID, meta); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// linkUnclaimedAlumni menghubungkan akun ke data alumni yang belum dimiliki akun lain; dicek ulang
+// di dalam transaksi agar dua klaim bersamaan tidak saling menimpa
+func linkUnclaimedAlumni(ctx context.Context, tx pgx.Tx, userID, alumniID int, meta domain.AuditMeta) error {
+	before, err := lockAlumni(ctx, tx, alumniID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.ErrAlumniNotFound
+		}
+		return err
+	}
+
+	linkSQL := `
+		UPDATE alumni SET user_id = $1, updated_at = NOW()
+		WHERE id = $2 AND user_id IS NULL
+		AND NOT EXISTS (SELECT 1 FROM alumni WHERE user_id = $1)`
+	cmdTag, err := tx.Exec(ctx, linkSQL, userID, alumniID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() != 1 {
+		return domain.ErrAlumniAlreadyLinked
+	}
+
+	after := *before
+	after.UserID = &userID
+	return writeAudit(ctx, tx, meta, domain.AuditActionLink, domain.AuditEntityAlumni, alumniID, before, &after)
+}
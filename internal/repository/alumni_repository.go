@@ -4,7 +4,6 @@ package repository
 import (
 	"back-train/internal/domain"
 	"context"
-	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -21,42 +20,120 @@ func NewAlumniRepository(db *pgxpool.Pool) AlumniRepository {
 	return &alumniRepository{db: db}
 }
 
-func (r *alumniRepository) Create(ctx context.Context, alumni *domain.Alumni) (*domain.Alumni, error) {
-	query := `INSERT INTO alumni (nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat)
+const alumniInsertSQL = `INSERT INTO alumni (nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat)
               VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
               RETURNING id, created_at, updated_at`
-	err := r.db.QueryRow(ctx, query, alumni.NIM, alumni.Nama, alumni.Jurusan, alumni.Angkatan, alumni.TahunLulus, alumni.Email, alumni.NoTelepon, alumni.Alamat).Scan(&alumni.ID, &alumni.CreatedAt, &alumni.UpdatedAt)
+
+// alumniBatchSize adalah jumlah baris per pgx.Batch pada CreateMany
+const alumniBatchSize = 500
+
+func (r *alumniRepository) Create(ctx context.Context, alumni *domain.Alumni, meta domain.AuditMeta) (*domain.Alumni, error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, alumniInsertSQL, alumni.NIM, alumni.Nama, alumni.Jurusan, alumni.Angkatan, alumni.TahunLulus, alumni.Email, alumni.NoTelepon, alumni.Alamat).Scan(&alumni.ID, &alumni.CreatedAt, &alumni.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionCreate, domain.AuditEntityAlumni, alumni.ID, nil, alumni); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return alumni, nil
 }
 
-func (r *alumniRepository) FindAll(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Alumni], error) {
+// CreateMany menyimpan semua alumni dalam satu transaksi. Insert dan audit log dikirim per batch
+// agar ribuan baris tidak membutuhkan ribuan round trip.
+func (r *alumniRepository) CreateMany(ctx context.Context, list []*domain.Alumni, meta domain.AuditMeta) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for start := 0; start < len(list); start += alumniBatchSize {
+		chunk := list[start:min(start+alumniBatchSize, len(list))]
+
+		batch := &pgx.Batch{}
+		for _, a := range chunk {
+			batch.Queue(alumniInsertSQL, a.NIM, a.Nama, a.Jurusan, a.Angkatan, a.TahunLulus, a.Email, a.NoTelepon, a.Alamat)
+		}
+		results := tx.SendBatch(ctx, batch)
+		for _, a := range chunk {
+			if err := results.QueryRow().Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt); err != nil {
+				results.Close()
+				return err
+			}
+		}
+		if err := results.Close(); err != nil {
+			return err
+		}
+
+		audits := &pgx.Batch{}
+		for _, a := range chunk {
+			if err := queueAudit(audits, meta, domain.AuditActionCreate, domain.AuditEntityAlumni, a.ID, nil, a); err != nil {
+				return err
+			}
+		}
+		if err := tx.SendBatch(ctx, audits).Close(); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindExisting mengembalikan NIM dan email (huruf kecil) dari daftar yang sudah dipakai alumni lain
+func (r *alumniRepository) FindExisting(ctx context.Context, nims, emails []string) (map[string]bool, map[string]bool, error) {
+	query := `SELECT nim, LOWER(email) FROM alumni WHERE nim = ANY($1) OR LOWER(email) = ANY($2)`
+	rows, err := r.db.Query(ctx, query, nims, emails)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	existingNIMs := map[string]bool{}
+	existingEmails := map[string]bool{}
+	for rows.Next() {
+		var nim, email string
+		if err := rows.Scan(&nim, &email); err != nil {
+			return nil, nil, err
+		}
+		existingNIMs[nim] = true
+		existingEmails[email] = true
+	}
+	if rows.Err() != nil {
+		return nil, nil, rows.Err()
+	}
+	return existingNIMs, existingEmails, nil
+}
+
+var alumniFilterColumns = listFilterColumns{
+	"jurusan":     "jurusan",
+	"angkatan":    "angkatan",
+	"tahun_lulus": "tahun_lulus",
+}
+
+// alumniListQuery membuat klausa WHERE dan ORDER BY yang sama untuk listing dan export
+func alumniListQuery(params domain.PaginationParams) (string, []interface{}, string) {
 	var args []interface{}
 	var whereClauses []string
-	argID := 1
-
-	baseQuery := `SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, created_at, updated_at FROM alumni`
-	countQuery := `SELECT COUNT(id) FROM alumni`
 
 	if params.Search != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("(nama ILIKE $%d OR nim ILIKE $%d OR jurusan ILIKE $%d OR email ILIKE $%d)", argID, argID, argID, argID))
 		args = append(args, "%"+params.Search+"%")
-		argID++
+		whereClauses = append(whereClauses, fmt.Sprintf("(nama ILIKE $%d OR nim ILIKE $%d OR jurusan ILIKE $%d OR email ILIKE $%d)", len(args), len(args), len(args), len(args)))
 	}
+	whereClauses, args = alumniFilterColumns.whereFilters(params.Filters, whereClauses, args, domain.AlumniFilters)
 
+	whereSQL := ""
 	if len(whereClauses) > 0 {
-		whereSQL := " WHERE " + strings.Join(whereClauses, " AND ")
-		baseQuery += whereSQL
-		countQuery += whereSQL
-	}
-
-	// Get total count
-	var total int64
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, err
+		whereSQL = " WHERE " + strings.Join(whereClauses, " AND ")
 	}
 
 	// Sorting
@@ -81,15 +158,33 @@ func (r *alumniRepository) FindAll(ctx context.Context, params domain.Pagination
 			sortOrder = "ASC"
 		}
 	}
-	baseQuery += fmt.Sprintf(" ORDER BY %s %s", sortColumn, sortOrder)
+	// id sebagai penentu urutan agar baris dengan nilai sort sama tidak berpindah halaman
+	return whereSQL, args, fmt.Sprintf(" ORDER BY %s %s, id %s", sortColumn, sortOrder, sortOrder)
+}
+
+const alumniSelectSQL = `SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, user_id, created_at, updated_at FROM alumni`
+
+func scanAlumni(row pgx.Row, a *domain.Alumni) error {
+	return row.Scan(&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus, &a.Email, &a.NoTelepon, &a.Alamat, &a.UserID, &a.CreatedAt, &a.UpdatedAt)
+}
+
+func (r *alumniRepository) FindAll(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Alumni], error) {
+	whereSQL, args, orderSQL := alumniListQuery(params)
+
+	// Get total count
+	var total int64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(id) FROM alumni`+whereSQL, args...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
 
 	// Pagination
 	offset := (params.Page - 1) * params.Limit
-	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argID, argID+1)
+	query := alumniSelectSQL + whereSQL + orderSQL + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 	args = append(args, params.Limit, offset)
 
 	// Execute main query
-	rows, err := r.db.Query(ctx, baseQuery, args...)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +193,7 @@ func (r *alumniRepository) FindAll(ctx context.Context, params domain.Pagination
 	alumniList := []domain.Alumni{}
 	for rows.Next() {
 		var a domain.Alumni
-		if err := rows.Scan(&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus, &a.Email, &a.NoTelepon, &a.Alamat, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		if err := scanAlumni(rows, &a); err != nil {
 			return nil, err
 		}
 		alumniList = append(alumniList, a)
@@ -124,37 +219,171 @@ func (r *alumniRepository) FindAll(ctx context.Context, params domain.Pagination
 	return result, nil
 }
 
+// StreamAll memanggil fn untuk setiap alumni yang cocok dengan search/filter/sort tanpa paging,
+// satu baris per waktu sehingga hasil sebesar apa pun tidak dimuat sekaligus ke memori
+func (r *alumniRepository) StreamAll(ctx context.Context, params domain.PaginationParams, fn func(*domain.Alumni) error) error {
+	whereSQL, args, orderSQL := alumniListQuery(params)
+	rows, err := r.db.Query(ctx, alumniSelectSQL+whereSQL+orderSQL, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var a domain.Alumni
+	for rows.Next() {
+		if err := scanAlumni(rows, &a); err != nil {
+			return err
+		}
+		if err := fn(&a); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (r *alumniRepository) FindByID(ctx context.Context, id int) (*domain.Alumni, error) {
 	var a domain.Alumni
-	query := `SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, created_at, updated_at FROM alumni WHERE id = $1`
-	err := r.db.QueryRow(ctx, query, id).Scan(&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus, &a.Email, &a.NoTelepon, &a.Alamat, &a.CreatedAt, &a.UpdatedAt)
+	query := `SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, user_id, created_at, updated_at FROM alumni WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus, &a.Email, &a.NoTelepon, &a.Alamat, &a.UserID, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrAlumniNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *alumniRepository) FindByNIM(ctx context.Context, nim string) (*domain.Alumni, error) {
+	var a domain.Alumni
+	query := `SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, user_id, created_at, updated_at FROM alumni WHERE nim = $1`
+	err := r.db.QueryRow(ctx, query, nim).Scan(&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus, &a.Email, &a.NoTelepon, &a.Alamat, &a.UserID, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrAlumniNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *alumniRepository) FindByUserID(ctx context.Context, userID int) (*domain.Alumni, error) {
+	var a domain.Alumni
+	query := `SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, user_id, created_at, updated_at FROM alumni WHERE user_id = $1`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus, &a.Email, &a.NoTelepon, &a.Alamat, &a.UserID, &a.CreatedAt, &a.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.New("alumni not found")
+			return nil, domain.ErrAlumniNotLinked
 		}
 		return nil, err
 	}
 	return &a, nil
 }
 
-func (r *alumniRepository) Update(ctx context.Context, alumni *domain.Alumni) (*domain.Alumni, error) {
+// lockAlumni membaca dan mengunci baris alumni sebagai kondisi "sebelum" untuk audit log;
+// pgx.ErrNoRows dikembalikan apa adanya agar pemanggil bisa memilih pesan error-nya
+func lockAlumni(ctx context.Context, tx pgx.Tx, id int) (*domain.Alumni, error) {
+	var a domain.Alumni
+	query := `SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, user_id, created_at, updated_at FROM alumni WHERE id = $1 FOR UPDATE`
+	err := tx.QueryRow(ctx, query, id).Scan(&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus, &a.Email, &a.NoTelepon, &a.Alamat, &a.UserID, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *alumniRepository) SetUser(ctx context.Context, alumniID int, userID *int, meta domain.AuditMeta) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockAlumni(ctx, tx, alumniID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.ErrAlumniNotFound
+		}
+		return err
+	}
+
+	if userID != nil {
+		// Satu akun hanya boleh terhubung ke satu data alumni
+		var linkedID int
+		err := tx.QueryRow(ctx, `SELECT id FROM alumni WHERE user_id = $1`, *userID).Scan(&linkedID)
+		if err == nil && linkedID != alumniID {
+			return domain.ErrAlumniAlreadyLinked
+		}
+		if err != nil && err != pgx.ErrNoRows {
+			return err
+		}
+	}
+
+	after := *before
+	err = tx.QueryRow(ctx, `UPDATE alumni SET user_id = $1, updated_at = NOW() WHERE id = $2 RETURNING updated_at`, userID, alumniID).Scan(&after.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	after.UserID = userID
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionLink, domain.AuditEntityAlumni, alumniID, before, &after); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *alumniRepository) Update(ctx context.Context, alumni *domain.Alumni, meta domain.AuditMeta) (*domain.Alumni, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockAlumni(ctx, tx, alumni.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrAlumniNotFound
+		}
+		return nil, err
+	}
+
 	query := `UPDATE alumni SET nama=$1, jurusan=$2, angkatan=$3, tahun_lulus=$4, email=$5, no_telepon=$6, alamat=$7, updated_at=NOW()
-              WHERE id=$8 RETURNING updated_at`
-	err := r.db.QueryRow(ctx, query, alumni.Nama, alumni.Jurusan, alumni.Angkatan, alumni.TahunLulus, alumni.Email, alumni.NoTelepon, alumni.Alamat, alumni.ID).Scan(&alumni.UpdatedAt)
+              WHERE id=$8 RETURNING user_id, updated_at`
+	err = tx.QueryRow(ctx, query, alumni.Nama, alumni.Jurusan, alumni.Angkatan, alumni.TahunLulus, alumni.Email, alumni.NoTelepon, alumni.Alamat, alumni.ID).Scan(&alumni.UserID, &alumni.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionUpdate, domain.AuditEntityAlumni, alumni.ID, before, alumni); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return alumni, nil
 }
 
-func (r *alumniRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM alumni WHERE id = $1`
-	cmdTag, err := r.db.Exec(ctx, query, id)
+func (r *alumniRepository) Delete(ctx context.Context, id int, meta domain.AuditMeta) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockAlumni(ctx, tx, id)
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.ErrAlumniNotFound
+		}
 		return err
 	}
-	if cmdTag.RowsAffected() != 1 {
-		return errors.New("no row found to delete")
+
+	if _, err := tx.Exec(ctx, `DELETE FROM alumni WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionDelete, domain.AuditEntityAlumni, id, before, nil); err != nil {
+		return err
 	}
-	return nil
+	return tx.Commit(ctx)
 }
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type auditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// auditIgnoredFields tidak dianggap sebagai perubahan karena diisi otomatis oleh database
+var auditIgnoredFields = map[string]bool{"created_at": true, "updated_at": true}
+
+// writeAudit mencatat perubahan di dalam transaksi tx sehingga ikut batal jika perubahannya gagal.
+// before nil berarti create, after nil berarti delete. Update tanpa perubahan field tidak dicatat.
+func writeAudit(ctx context.Context, tx pgx.Tx, meta domain.AuditMeta, action, entityType string, entityID int, before, after interface{}) error {
+	args, err := auditArgs(meta, action, entityType, entityID, before, after)
+	if err != nil || args == nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, auditInsertSQL, args...)
+	return err
+}
+
+// queueAudit sama dengan writeAudit tetapi dimasukkan ke batch untuk perubahan massal
+func queueAudit(batch *pgx.Batch, meta domain.AuditMeta, action, entityType string, entityID int, before, after interface{}) error {
+	args, err := auditArgs(meta, action, entityType, entityID, before, after)
+	if err != nil || args == nil {
+		return err
+	}
+	batch.Queue(auditInsertSQL, args...)
+	return nil
+}
+
+const auditInsertSQL = `
+	INSERT INTO audit_logs (actor_user_id, impersonator_id, action, entity_type, entity_id, before_data, after_data, request_id, ip_address)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''))`
+
+// auditArgs bernilai nil jika tidak ada yang perlu dicatat
+func auditArgs(meta domain.AuditMeta, action, entityType string, entityID int, before, after interface{}) ([]interface{}, error) {
+	beforeData, afterData, err := auditDiff(before, after)
+	if err != nil {
+		return nil, err
+	}
+	if beforeData == nil && afterData == nil {
+		return nil, nil
+	}
+	// Dikonversi ke []byte agar nil tersimpan sebagai NULL, bukan JSON null
+	return []interface{}{meta.ActorUserID, meta.ImpersonatorID, action, entityType, entityID,
+		[]byte(beforeData), []byte(afterData), meta.RequestID, meta.IPAddress}, nil
+}
+
+// auditDiff mengembalikan nilai lama dan baru dari field JSON yang berbeda
+func auditDiff(before, after interface{}) (json.RawMessage, json.RawMessage, error) {
+	oldFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	newFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changedOld := map[string]interface{}{}
+	changedNew := map[string]interface{}{}
+	for name, value := range newFields {
+		if old, ok := oldFields[name]; !ok || !reflect.DeepEqual(old, value) {
+			changedNew[name] = value
+			if ok {
+				changedOld[name] = old
+			}
+		}
+	}
+	for name, old := range oldFields {
+		if _, ok := newFields[name]; !ok {
+			changedOld[name] = old
+		}
+	}
+
+	return auditJSON(changedOld, before != nil), auditJSON(changedNew, after != nil), nil
+}
+
+func auditFields(entity interface{}) (map[string]interface{}, error) {
+	if entity == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for name := range auditIgnoredFields {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+// auditJSON bernilai NULL jika entity tidak ada (create/delete) atau tidak ada field yang berubah
+func auditJSON(fields map[string]interface{}, present bool) json.RawMessage {
+	if !present || len(fields) == 0 {
+		return nil
+	}
+	raw, _ := json.Marshal(fields)
+	return raw
+}
+
+const auditLogColumns = `id, actor_user_id, impersonator_id, action, entity_type, entity_id, before_data, after_data, request_id, ip_address, created_at`
+
+func scanAuditLogs(rows pgx.Rows) ([]domain.AuditLog, error) {
+	logs := []domain.AuditLog{}
+	for rows.Next() {
+		var l domain.AuditLog
+		if err := rows.Scan(&l.ID, &l.ActorUserID, &l.ImpersonatorID, &l.Action, &l.EntityType, &l.EntityID, &l.Before, &l.After, &l.RequestID, &l.IPAddress, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return logs, nil
+}
+
+func (r *auditRepository) FindAll(ctx context.Context, filter domain.AuditFilter) (*domain.PaginationResult[domain.AuditLog], error) {
+	var args []interface{}
+	var whereClauses []string
+	add := func(clause string, value interface{}) {
+		args = append(args, value)
+		whereClauses = append(whereClauses, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.EntityType != "" {
+		add("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		add("entity_id = $%d", *filter.EntityID)
+	}
+	if filter.ActorUserID != nil {
+		add("actor_user_id = $%d", *filter.ActorUserID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	whereSQL := ""
+	if len(whereClauses) > 0 {
+		whereSQL = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(id) FROM audit_logs`+whereSQL, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query := fmt.Sprintf(`
+		SELECT `+auditLogColumns+`
+		FROM audit_logs%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, whereSQL, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, filter.Limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs, err := scanAuditLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	lastPage := int(math.Ceil(float64(total) / float64(filter.Limit)))
+	if lastPage < 1 && total > 0 {
+		lastPage = 1
+	}
+
+	return &domain.PaginationResult[domain.AuditLog]{
+		Data:     logs,
+		Total:    total,
+		Page:     filter.Page,
+		Limit:    filter.Limit,
+		LastPage: lastPage,
+	}, nil
+}
+
+func (r *auditRepository) FindByEntity(ctx context.Context, entityType string, entityID int) ([]domain.AuditLog, error) {
+	query := `SELECT ` + auditLogColumns + ` FROM audit_logs WHERE entity_type = $1 AND entity_id = $2 ORDER BY id ASC`
+	rows, err := r.db.Query(ctx, query, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAuditLogs(rows)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type authEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuthEventRepository(db *pgxpool.Pool) AuthEventRepository {
+	return &authEventRepository{db: db}
+}
+
+func (r *authEventRepository) Record(ctx context.Context, event *domain.AuthEvent) error {
+	query := `
+		INSERT INTO auth_events (event_type, user_id, actor_id, email, ip_address, detail)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+	return r.db.QueryRow(ctx, query, event.EventType, event.UserID, event.ActorID, event.Email, event.IPAddress, event.Detail).
+		Scan(&event.ID, &event.CreatedAt)
+}
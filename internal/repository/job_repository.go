@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type jobRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewJobRepository(db *pgxpool.Pool) JobRepository {
+	return &jobRepository{db: db}
+}
+
+const jobColumns = `id, type, status, payload, input_path, progress_done, progress_total, attempts, max_attempts, last_error,
+	result, artifact_path, artifact_name, artifact_content_type, cancel_requested, run_at, created_by, impersonator_id,
+	request_id, ip_address, started_at, finished_at, created_at, updated_at`
+
+func scanJob(row pgx.Row) (*domain.Job, error) {
+	var j domain.Job
+	err := row.Scan(&j.ID, &j.Type, &j.Status, &j.Payload, &j.InputPath, &j.ProgressDone, &j.ProgressTotal, &j.Attempts, &j.MaxAttempts, &j.LastError,
+		&j.Result, &j.ArtifactPath, &j.ArtifactName, &j.ArtifactContentType, &j.CancelRequested, &j.RunAt, &j.CreatedBy, &j.ImpersonatorID,
+		&j.RequestID, &j.IPAddress, &j.StartedAt, &j.FinishedAt, &j.CreatedAt, &j.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrJobNotFound
+		}
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (r *jobRepository) Create(ctx context.Context, job *domain.Job) (*domain.Job, error) {
+	query := `INSERT INTO jobs (type, payload, input_path, max_attempts, created_by, impersonator_id, request_id, ip_address)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  RETURNING ` + jobColumns
+	return scanJob(r.db.QueryRow(ctx, query, job.Type, []byte(job.Payload), job.InputPath, job.MaxAttempts,
+		job.CreatedBy, job.ImpersonatorID, job.RequestID, job.IPAddress))
+}
+
+func (r *jobRepository) FindByID(ctx context.Context, id int64) (*domain.Job, error) {
+	return scanJob(r.db.QueryRow(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+}
+
+func (r *jobRepository) FindAll(ctx context.Context, filter domain.JobFilter) (*domain.PaginationResult[domain.Job], error) {
+	var args []interface{}
+	var whereClauses []string
+	add := func(clause string, value interface{}) {
+		args = append(args, value)
+		whereClauses = append(whereClauses, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.CreatedBy != nil {
+		add("created_by = $%d", *filter.CreatedBy)
+	}
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+
+	whereSQL := ""
+	if len(whereClauses) > 0 {
+		whereSQL = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(id) FROM jobs`+whereSQL, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query := fmt.Sprintf(`SELECT `+jobColumns+` FROM jobs%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		whereSQL, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, filter.Limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []domain.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	lastPage := int(math.Ceil(float64(total) / float64(filter.Limit)))
+	if lastPage < 1 && total > 0 {
+		lastPage = 1
+	}
+
+	return &domain.PaginationResult[domain.Job]{
+		Data:     jobs,
+		Total:    total,
+		Page:     filter.Page,
+		Limit:    filter.Limit,
+		LastPage: lastPage,
+	}, nil
+}
+
+// Claim mengambil satu job yang siap dijalankan, termasuk job running yang lease-nya habis karena
+// worker sebelumnya mati. SKIP LOCKED membuat beberapa worker bisa mengambil job bersamaan tanpa
+// saling menunggu. Mengembalikan nil jika antrian kosong.
+func (r *jobRepository) Claim(ctx context.Context, workerID string, lease time.Duration) (*domain.Job, error) {
+	query := `
+		UPDATE jobs SET status = 'running', attempts = attempts + 1, locked_by = $1,
+			locked_until = NOW() + make_interval(secs => $2), started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = 'queued' AND run_at <= NOW()) OR (status = 'running' AND locked_until < NOW())
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+	job, err := scanJob(r.db.QueryRow(ctx, query, workerID, lease.Seconds()))
+	if err == domain.ErrJobNotFound {
+		return nil, nil
+	}
+	return job, err
+}
+
+// Heartbeat menyimpan progress dan memperpanjang lease. ErrJobLost berarti job sudah diambil
+// worker lain (lease sempat habis) sehingga hasil worker ini tidak boleh disimpan.
+func (r *jobRepository) Heartbeat(ctx context.Context, id int64, workerID string, done, total int, lease time.Duration) (bool, error) {
+	query := `
+		UPDATE jobs SET progress_done = $3, progress_total = $4, locked_until = NOW() + make_interval(secs => $5), updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'
+		RETURNING cancel_requested`
+	var cancelRequested bool
+	err := r.db.QueryRow(ctx, query, id, workerID, done, total, lease.Seconds()).Scan(&cancelRequested)
+	if err == pgx.ErrNoRows {
+		return false, domain.ErrJobLost
+	}
+	return cancelRequested, err
+}
+
+// execOwned menjalankan perubahan status yang hanya boleh dilakukan worker pemegang job
+func (r *jobRepository) execOwned(ctx context.Context, query string, args ...interface{}) error {
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrJobLost
+	}
+	return nil
+}
+
+func (r *jobRepository) Complete(ctx context.Context, job *domain.Job, workerID string) error {
+	query := `
+		UPDATE jobs SET status = 'succeeded', result = $3, artifact_path = $4, artifact_name = $5, artifact_content_type = $6,
+			progress_done = GREATEST(progress_done, progress_total), last_error = NULL, locked_by = NULL, locked_until = NULL,
+			finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+	return r.execOwned(ctx, query, job.ID, workerID, []byte(job.Result), job.ArtifactPath, job.ArtifactName, job.ArtifactContentType)
+}
+
+// Retry mengembalikan job ke antrian dan baru bisa diambil lagi setelah runAt
+func (r *jobRepository) Retry(ctx context.Context, id int64, workerID, lastError string, runAt time.Time) error {
+	query := `
+		UPDATE jobs SET status = 'queued', last_error = $3, run_at = $4, locked_by = NULL, locked_until = NULL, updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+	return r.execOwned(ctx, query, id, workerID, lastError, runAt)
+}
+
+// Release mengembalikan job ke antrian tanpa menghitung percobaan, dipakai saat worker berhenti
+func (r *jobRepository) Release(ctx context.Context, id int64, workerID string) error {
+	query := `
+		UPDATE jobs SET status = 'queued', attempts = GREATEST(attempts - 1, 0), locked_by = NULL, locked_until = NULL, updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+	return r.execOwned(ctx, query, id, workerID)
+}
+
+// Finish mengakhiri job dengan status failed atau cancelled
+func (r *jobRepository) Finish(ctx context.Context, id int64, workerID, status string, lastError *string) error {
+	query := `
+		UPDATE jobs SET status = $3, last_error = COALESCE($4, last_error), locked_by = NULL, locked_until = NULL,
+			finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+	return r.execOwned(ctx, query, id, workerID, status, lastError)
+}
+
+// RequestCancel langsung membatalkan job yang masih antri; job yang sedang berjalan hanya ditandai
+// dan dihentikan oleh worker pada heartbeat berikutnya
+func (r *jobRepository) RequestCancel(ctx context.Context, id int64) (*domain.Job, error) {
+	query := `
+		UPDATE jobs SET cancel_requested = TRUE,
+			status = CASE WHEN status = 'queued' THEN 'cancelled' ELSE status END,
+			finished_at = CASE WHEN status = 'queued' THEN NOW() ELSE finished_at END,
+			updated_at = NOW()
+		WHERE id = $1 AND status IN ('queued', 'running')
+		RETURNING ` + jobColumns
+	job, err := scanJob(r.db.QueryRow(ctx, query, id))
+	if err == domain.ErrJobNotFound {
+		return nil, domain.ErrJobNotCancellable
+	}
+	return job, err
+}
+
+// DeleteFinishedBefore menghapus job yang sudah selesai sebelum waktu tertentu dan mengembalikan
+// file input/artifact miliknya agar bisa dihapus dari disk
+func (r *jobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) ([]string, error) {
+	query := `DELETE FROM jobs WHERE finished_at < $1 RETURNING input_path, artifact_path`
+	rows, err := r.db.Query(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var inputPath, artifactPath *string
+		if err := rows.Scan(&inputPath, &artifactPath); err != nil {
+			return nil, err
+		}
+		for _, p := range []*string{inputPath, artifactPath} {
+			if p != nil {
+				paths = append(paths, *p)
+			}
+		}
+	}
+	return paths, rows.Err()
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+)
+
+// listFilterColumns memetakan nama filter di PaginationParams.Filters ke kolom SQL
+type listFilterColumns map[string]string
+
+// whereFilters menambahkan kondisi untuk setiap filter yang dikenal. Filter teks dicocokkan tanpa
+// membedakan huruf besar; nilai filter angka sudah divalidasi handler dan di-cast oleh PostgreSQL.
+func (columns listFilterColumns) whereFilters(filters map[string]string, whereClauses []string, args []interface{}, numeric map[string]bool) ([]string, []interface{}) {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // urutan parameter stabil agar query plan bisa dipakai ulang
+	for _, key := range keys {
+		column, ok := columns[key]
+		if !ok {
+			continue
+		}
+		args = append(args, filters[key])
+		if numeric[key] {
+			whereClauses = append(whereClauses, fmt.Sprintf("%s = $%d::int", column, len(args)))
+		} else {
+			whereClauses = append(whereClauses, fmt.Sprintf("LOWER(%s) = LOWER($%d)", column, len(args)))
+		}
+	}
+	return whereClauses, args
+}
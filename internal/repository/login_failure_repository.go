@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type loginFailureRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginFailureRepository(db *pgxpool.Pool) LoginFailureRepository {
+	return &loginFailureRepository{db: db}
+}
+
+func (r *loginFailureRepository) LockedUntil(ctx context.Context, scope, key string) (*time.Time, error) {
+	var lockedUntil *time.Time
+	query := `SELECT locked_until FROM login_failures WHERE scope = $1 AND key = $2 AND locked_until > NOW()`
+	err := r.db.QueryRow(ctx, query, scope, key).Scan(&lockedUntil)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return lockedUntil, nil
+}
+
+func (r *loginFailureRepository) RegisterFailure(ctx context.Context, scope, key string, window time.Duration) (int, error) {
+	var count int
+	query := `
+		INSERT INTO login_failures (scope, key, failure_count, last_failed_at) VALUES ($1, $2, 1, NOW())
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failure_count = CASE
+				WHEN login_failures.last_failed_at < NOW() - make_interval(secs => $3) THEN 1
+				ELSE login_failures.failure_count + 1
+			END,
+			last_failed_at = NOW()
+		RETURNING failure_count`
+	err := r.db.QueryRow(ctx, query, scope, key, window.Seconds()).Scan(&count)
+	return count, err
+}
+
+func (r *loginFailureRepository) Lock(ctx context.Context, scope, key string, until time.Time) error {
+	query := `UPDATE login_failures SET locked_until = $3 WHERE scope = $1 AND key = $2`
+	_, err := r.db.Exec(ctx, query, scope, key, until)
+	return err
+}
+
+func (r *loginFailureRepository) Reset(ctx context.Context, scope, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM login_failures WHERE scope = $1 AND key = $2`, scope, key)
+	return err
+}
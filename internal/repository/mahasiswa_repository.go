@@ -3,7 +3,6 @@ package repository
 import (
 	"back-train/internal/domain"
 	"context"
-	"errors"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -17,14 +16,27 @@ func NewMahasiswaRepository(db *pgxpool.Pool) MahasiswaRepository {
 	return &mahasiswaRepository{db: db}
 }
 
-func (r *mahasiswaRepository) Create(ctx context.Context, m *domain.Mahasiswa) (*domain.Mahasiswa, error) {
+func (r *mahasiswaRepository) Create(ctx context.Context, m *domain.Mahasiswa, meta domain.AuditMeta) (*domain.Mahasiswa, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `INSERT INTO mahasiswa (nim, nama, jurusan, angkatan, email)
               VALUES ($1, $2, $3, $4, $5)
               RETURNING id, created_at, updated_at`
-	err := r.db.QueryRow(ctx, query, m.NIM, m.Nama, m.Jurusan, m.Angkatan, m.Email).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+	err = tx.QueryRow(ctx, query, m.NIM, m.Nama, m.Jurusan, m.Angkatan, m.Email).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionCreate, domain.AuditEntityMahasiswa, m.ID, nil, m); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return m, nil
 }
 
@@ -53,31 +65,76 @@ func (r *mahasiswaRepository) FindByID(ctx context.Context, id int) (*domain.Mah
 	err := r.db.QueryRow(ctx, query, id).Scan(&m.ID, &m.NIM, &m.Nama, &m.Jurusan, &m.Angkatan, &m.Email, &m.CreatedAt, &m.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.New("mahasiswa not found")
+			return nil, domain.ErrMahasiswaNotFound
 		}
 		return nil, err
 	}
 	return &m, nil
 }
 
-func (r *mahasiswaRepository) Update(ctx context.Context, m *domain.Mahasiswa) (*domain.Mahasiswa, error) {
+// lockMahasiswa membaca dan mengunci baris mahasiswa sebagai kondisi "sebelum" untuk audit log
+func lockMahasiswa(ctx context.Context, tx pgx.Tx, id int) (*domain.Mahasiswa, error) {
+	var m domain.Mahasiswa
+	query := `SELECT id, nim, nama, jurusan, angkatan, email, created_at, updated_at FROM mahasiswa WHERE id = $1 FOR UPDATE`
+	err := tx.QueryRow(ctx, query, id).Scan(&m.ID, &m.NIM, &m.Nama, &m.Jurusan, &m.Angkatan, &m.Email, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *mahasiswaRepository) Update(ctx context.Context, m *domain.Mahasiswa, meta domain.AuditMeta) (*domain.Mahasiswa, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockMahasiswa(ctx, tx, m.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrMahasiswaNotFound
+		}
+		return nil, err
+	}
+
 	query := `UPDATE mahasiswa SET nama=$1, jurusan=$2, angkatan=$3, email=$4, updated_at=NOW()
               WHERE id=$5 RETURNING updated_at`
-	err := r.db.QueryRow(ctx, query, m.Nama, m.Jurusan, m.Angkatan, m.Email, m.ID).Scan(&m.UpdatedAt)
+	err = tx.QueryRow(ctx, query, m.Nama, m.Jurusan, m.Angkatan, m.Email, m.ID).Scan(&m.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionUpdate, domain.AuditEntityMahasiswa, m.ID, before, m); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return m, nil
 }
 
-func (r *mahasiswaRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM mahasiswa WHERE id = $1`
-	cmdTag, err := r.db.Exec(ctx, query, id)
+func (r *mahasiswaRepository) Delete(ctx context.Context, id int, meta domain.AuditMeta) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	if cmdTag.RowsAffected() != 1 {
-		return errors.New("no row found to delete")
+	defer tx.Rollback(ctx)
+
+	before, err := lockMahasiswa(ctx, tx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.ErrMahasiswaNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM mahasiswa WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionDelete, domain.AuditEntityMahasiswa, id, before, nil); err != nil {
+		return err
 	}
-	return nil
+	return tx.Commit(ctx)
 }
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type passwordResetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPasswordResetRepository(db *pgxpool.Pool) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+	_, err := r.db.Exec(ctx, query, userID, tokenHash, expiresAt)
+	return err
+}
+
+func (r *passwordResetRepository) FindUserID(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	query := `SELECT user_id FROM password_reset_tokens WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()`
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(&userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, domain.ErrInvalidResetToken
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (r *passwordResetRepository) Consume(ctx context.Context, tokenHash string, newPasswordHash string) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var tokenID, userID int
+	query := `
+		SELECT id, user_id FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		FOR UPDATE`
+	err = tx.QueryRow(ctx, query, tokenHash).Scan(&tokenID, &userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, domain.ErrInvalidResetToken
+		}
+		return 0, err
+	}
+
+	updateSQL := `UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`
+	if _, err := tx.Exec(ctx, updateSQL, newPasswordHash, userID); err != nil {
+		return 0, err
+	}
+
+	// Token ini dan semua token reset lain milik user yang masih berlaku tidak bisa dipakai lagi
+	usedSQL := `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+	if _, err := tx.Exec(ctx, usedSQL, userID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
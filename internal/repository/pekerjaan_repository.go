@@ -3,7 +3,6 @@ package repository
 import (
 	"back-train/internal/domain"
 	"context"
-	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -20,46 +19,53 @@ func NewPekerjaanRepository(db *pgxpool.Pool) PekerjaanRepository {
 	return &pekerjaanRepository{db: db}
 }
 
-func (r *pekerjaanRepository) Create(ctx context.Context, p *domain.Pekerjaan) (*domain.Pekerjaan, error) {
+func (r *pekerjaanRepository) Create(ctx context.Context, p *domain.Pekerjaan, meta domain.AuditMeta) (*domain.Pekerjaan, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `INSERT INTO pekerjaan (alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja, gaji_range, tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, deskripsi_pekerjaan)
               VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
               RETURNING id, created_at, updated_at`
-	err := r.db.QueryRow(ctx, query, p.AlumniID, p.NamaPerusahaan, p.PosisiJabatan, p.BidangIndustri, p.LokasiKerja, p.GajiRange, p.TanggalMulaiKerja, p.TanggalSelesaiKerja, p.StatusPekerjaan, p.DeskripsiPekerjaan).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+	err = tx.QueryRow(ctx, query, p.AlumniID, p.NamaPerusahaan, p.PosisiJabatan, p.BidangIndustri, p.LokasiKerja, p.GajiRange, p.TanggalMulaiKerja, p.TanggalSelesaiKerja, p.StatusPekerjaan, p.DeskripsiPekerjaan).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionCreate, domain.AuditEntityPekerjaan, p.ID, nil, p); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return p, nil
 }
 
-func (r *pekerjaanRepository) FindAll(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Pekerjaan], error) {
+var pekerjaanFilterColumns = listFilterColumns{
+	"alumni_id":        "p.alumni_id",
+	"bidang_industri":  "p.bidang_industri",
+	"status_pekerjaan": "p.status_pekerjaan",
+}
+
+// pekerjaanListQuery membuat klausa FROM/WHERE dan ORDER BY yang sama untuk listing dan export
+func pekerjaanListQuery(params domain.PaginationParams) (string, []interface{}, string) {
 	var args []interface{}
 	var whereClauses []string
-	argID := 1
-
-	baseQuery := `SELECT p.id, p.alumni_id, p.nama_perusahaan, p.posisi_jabatan, p.bidang_industri, p.lokasi_kerja, p.gaji_range, p.tanggal_mulai_kerja, p.tanggal_selesai_kerja, p.status_pekerjaan, p.deskripsi_pekerjaan, p.created_at, p.updated_at FROM pekerjaan p`
-	countQuery := `SELECT COUNT(p.id) FROM pekerjaan p`
+	fromSQL := " FROM pekerjaan p"
 
 	if params.Search != "" {
 		// Join with alumni to search by alumni name as well
-		baseQuery += " JOIN alumni a ON p.alumni_id = a.id"
-		countQuery += " JOIN alumni a ON p.alumni_id = a.id"
+		fromSQL += " JOIN alumni a ON p.alumni_id = a.id"
 
-		whereClauses = append(whereClauses, fmt.Sprintf("(p.nama_perusahaan ILIKE $%d OR p.posisi_jabatan ILIKE $%d OR p.bidang_industri ILIKE $%d OR a.nama ILIKE $%d)", argID, argID, argID, argID))
 		args = append(args, "%"+params.Search+"%")
-		argID++
+		whereClauses = append(whereClauses, fmt.Sprintf("(p.nama_perusahaan ILIKE $%d OR p.posisi_jabatan ILIKE $%d OR p.bidang_industri ILIKE $%d OR a.nama ILIKE $%d)", len(args), len(args), len(args), len(args)))
 	}
+	whereClauses, args = pekerjaanFilterColumns.whereFilters(params.Filters, whereClauses, args, domain.PekerjaanFilters)
 
 	if len(whereClauses) > 0 {
-		whereSQL := " WHERE " + strings.Join(whereClauses, " AND ")
-		baseQuery += whereSQL
-		countQuery += whereSQL
-	}
-
-	// Get total count
-	var total int64
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, err
+		fromSQL += " WHERE " + strings.Join(whereClauses, " AND ")
 	}
 
 	// Sorting
@@ -82,15 +88,32 @@ func (r *pekerjaanRepository) FindAll(ctx context.Context, params domain.Paginat
 			sortOrder = "ASC"
 		}
 	}
-	baseQuery += fmt.Sprintf(" ORDER BY %s %s", sortColumn, sortOrder)
+	return fromSQL, args, fmt.Sprintf(" ORDER BY %s %s, p.id %s", sortColumn, sortOrder, sortOrder)
+}
+
+const pekerjaanSelectColumns = `SELECT p.id, p.alumni_id, p.nama_perusahaan, p.posisi_jabatan, p.bidang_industri, p.lokasi_kerja, p.gaji_range, p.tanggal_mulai_kerja, p.tanggal_selesai_kerja, p.status_pekerjaan, p.deskripsi_pekerjaan, p.created_at, p.updated_at`
+
+func scanPekerjaan(row pgx.Row, p *domain.Pekerjaan) error {
+	return row.Scan(&p.ID, &p.AlumniID, &p.NamaPerusahaan, &p.PosisiJabatan, &p.BidangIndustri, &p.LokasiKerja, &p.GajiRange, &p.TanggalMulaiKerja, &p.TanggalSelesaiKerja, &p.StatusPekerjaan, &p.DeskripsiPekerjaan, &p.CreatedAt, &p.UpdatedAt)
+}
+
+func (r *pekerjaanRepository) FindAll(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Pekerjaan], error) {
+	fromSQL, args, orderSQL := pekerjaanListQuery(params)
+
+	// Get total count
+	var total int64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(p.id)`+fromSQL, args...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
 
 	// Pagination
 	offset := (params.Page - 1) * params.Limit
-	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argID, argID+1)
+	query := pekerjaanSelectColumns + fromSQL + orderSQL + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 	args = append(args, params.Limit, offset)
 
 	// Execute main query
-	rows, err := r.db.Query(ctx, baseQuery, args...)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +122,7 @@ func (r *pekerjaanRepository) FindAll(ctx context.Context, params domain.Paginat
 	pekerjaanList := []domain.Pekerjaan{}
 	for rows.Next() {
 		var p domain.Pekerjaan
-		if err := rows.Scan(&p.ID, &p.AlumniID, &p.NamaPerusahaan, &p.PosisiJabatan, &p.BidangIndustri, &p.LokasiKerja, &p.GajiRange, &p.TanggalMulaiKerja, &p.TanggalSelesaiKerja, &p.StatusPekerjaan, &p.DeskripsiPekerjaan, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := scanPekerjaan(rows, &p); err != nil {
 			return nil, err
 		}
 		pekerjaanList = append(pekerjaanList, p)
@@ -125,37 +148,123 @@ func (r *pekerjaanRepository) FindAll(ctx context.Context, params domain.Paginat
 	return result, nil
 }
 
+// StreamAll memanggil fn untuk setiap pekerjaan yang cocok dengan search/filter/sort tanpa paging
+func (r *pekerjaanRepository) StreamAll(ctx context.Context, params domain.PaginationParams, fn func(*domain.Pekerjaan) error) error {
+	fromSQL, args, orderSQL := pekerjaanListQuery(params)
+	rows, err := r.db.Query(ctx, pekerjaanSelectColumns+fromSQL+orderSQL, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var p domain.Pekerjaan
+	for rows.Next() {
+		if err := scanPekerjaan(rows, &p); err != nil {
+			return err
+		}
+		if err := fn(&p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (r *pekerjaanRepository) FindByID(ctx context.Context, id int) (*domain.Pekerjaan, error) {
 	var p domain.Pekerjaan
 	query := `SELECT id, alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja, gaji_range, tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, deskripsi_pekerjaan, created_at, updated_at FROM pekerjaan WHERE id = $1`
 	err := r.db.QueryRow(ctx, query, id).Scan(&p.ID, &p.AlumniID, &p.NamaPerusahaan, &p.PosisiJabatan, &p.BidangIndustri, &p.LokasiKerja, &p.GajiRange, &p.TanggalMulaiKerja, &p.TanggalSelesaiKerja, &p.StatusPekerjaan, &p.DeskripsiPekerjaan, &p.CreatedAt, &p.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.New("pekerjaan not found")
+			return nil, domain.ErrPekerjaanNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *pekerjaanRepository) FindByAlumniID(ctx context.Context, alumniID int) ([]domain.Pekerjaan, error) {
+	query := `SELECT id, alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja, gaji_range, tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, deskripsi_pekerjaan, created_at, updated_at
+              FROM pekerjaan WHERE alumni_id = $1 ORDER BY tanggal_mulai_kerja DESC`
+	rows, err := r.db.Query(ctx, query, alumniID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pekerjaanList := []domain.Pekerjaan{}
+	for rows.Next() {
+		var p domain.Pekerjaan
+		if err := rows.Scan(&p.ID, &p.AlumniID, &p.NamaPerusahaan, &p.PosisiJabatan, &p.BidangIndustri, &p.LokasiKerja, &p.GajiRange, &p.TanggalMulaiKerja, &p.TanggalSelesaiKerja, &p.StatusPekerjaan, &p.DeskripsiPekerjaan, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
 		}
+		pekerjaanList = append(pekerjaanList, p)
+	}
+	return pekerjaanList, rows.Err()
+}
+
+// lockPekerjaan membaca dan mengunci baris pekerjaan sebagai kondisi "sebelum" untuk audit log
+func lockPekerjaan(ctx context.Context, tx pgx.Tx, id int) (*domain.Pekerjaan, error) {
+	var p domain.Pekerjaan
+	query := `SELECT id, alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja, gaji_range, tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, deskripsi_pekerjaan, created_at, updated_at FROM pekerjaan WHERE id = $1 FOR UPDATE`
+	err := tx.QueryRow(ctx, query, id).Scan(&p.ID, &p.AlumniID, &p.NamaPerusahaan, &p.PosisiJabatan, &p.BidangIndustri, &p.LokasiKerja, &p.GajiRange, &p.TanggalMulaiKerja, &p.TanggalSelesaiKerja, &p.StatusPekerjaan, &p.DeskripsiPekerjaan, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
-func (r *pekerjaanRepository) Update(ctx context.Context, p *domain.Pekerjaan) (*domain.Pekerjaan, error) {
+func (r *pekerjaanRepository) Update(ctx context.Context, p *domain.Pekerjaan, meta domain.AuditMeta) (*domain.Pekerjaan, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockPekerjaan(ctx, tx, p.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrPekerjaanNotFound
+		}
+		return nil, err
+	}
+
 	query := `UPDATE pekerjaan SET nama_perusahaan=$1, posisi_jabatan=$2, bidang_industri=$3, lokasi_kerja=$4, gaji_range=$5, tanggal_mulai_kerja=$6, tanggal_selesai_kerja=$7, status_pekerjaan=$8, deskripsi_pekerjaan=$9, updated_at=NOW()
               WHERE id=$10 RETURNING updated_at`
-	err := r.db.QueryRow(ctx, query, p.NamaPerusahaan, p.PosisiJabatan, p.BidangIndustri, p.LokasiKerja, p.GajiRange, p.TanggalMulaiKerja, p.TanggalSelesaiKerja, p.StatusPekerjaan, p.DeskripsiPekerjaan, p.ID).Scan(&p.UpdatedAt)
+	err = tx.QueryRow(ctx, query, p.NamaPerusahaan, p.PosisiJabatan, p.BidangIndustri, p.LokasiKerja, p.GajiRange, p.TanggalMulaiKerja, p.TanggalSelesaiKerja, p.StatusPekerjaan, p.DeskripsiPekerjaan, p.ID).Scan(&p.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionUpdate, domain.AuditEntityPekerjaan, p.ID, before, p); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return p, nil
 }
 
-func (r *pekerjaanRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM pekerjaan WHERE id = $1`
-	cmdTag, err := r.db.Exec(ctx, query, id)
+func (r *pekerjaanRepository) Delete(ctx context.Context, id int, meta domain.AuditMeta) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	if cmdTag.RowsAffected() != 1 {
-		return errors.New("no row found to delete")
+	defer tx.Rollback(ctx)
+
+	before, err := lockPekerjaan(ctx, tx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.ErrPekerjaanNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM pekerjaan WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	if err := writeAudit(ctx, tx, meta, domain.AuditActionDelete, domain.AuditEntityPekerjaan, id, before, nil); err != nil {
+		return err
 	}
-	return nil
+	return tx.Commit(ctx)
 }
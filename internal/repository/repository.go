@@ -3,6 +3,7 @@ package repository
 import (
 	"back-train/internal/domain"
 	"context"
+	"time"
 )
 
 // Definisikan interface untuk setiap repository
@@ -11,28 +12,186 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User, roleName string) (*domain.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetUserByID(ctx context.Context, id int) (*domain.User, error)
+	FindAll(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.User], error)
+	SetRoles(ctx context.Context, userID int, roleNames []string) error
+	SetActive(ctx context.Context, userID int, active bool) error
+	MarkEmailVerified(ctx context.Context, id int) error
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	// UpdateEmail juga menandai email baru sebagai terverifikasi; ErrEmailTaken jika sudah dipakai user lain
+	UpdateEmail(ctx context.Context, id int, email string) error
+	// UpgradePasswordHash mengganti hash hanya jika hash lama belum berubah, agar tidak menimpa password yang baru diganti
+	UpgradePasswordHash(ctx context.Context, id int, oldHash, newHash string) error
+	Delete(ctx context.Context, id int) error
+}
+
+type RoleRepository interface {
+	FindAll(ctx context.Context) ([]domain.Role, error)
+	FindByID(ctx context.Context, id int) (*domain.Role, error)
+	Create(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	Rename(ctx context.Context, id int, name string) error
+	SetPermissions(ctx context.Context, id int, permissionNames []string) error
+	Delete(ctx context.Context, id int) error
+	FindAllPermissions(ctx context.Context) ([]domain.Permission, error)
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *domain.Session, refreshTokenHash string, refreshExpiresAt time.Time) error
+	// RotateRefreshToken juga memperbarui last_seen_at dan IP session
+	RotateRefreshToken(ctx context.Context, oldHash, newHash string, newExpiresAt time.Time, client domain.ClientInfo) (*domain.Session, error)
+	FindByID(ctx context.Context, id string) (*domain.Session, error)
+	// FindActiveByUserID mengembalikan session yang belum di-revoke dan belum expired, terbaru dulu
+	FindActiveByUserID(ctx context.Context, userID int) ([]domain.Session, error)
+	Revoke(ctx context.Context, id string, reason string) error
+	// RevokeForUser mengembalikan ErrSessionNotFound jika session tidak aktif atau bukan milik user
+	RevokeForUser(ctx context.Context, userID int, id string, reason string) error
+	RevokeAllForUser(ctx context.Context, userID int, reason string) error
+	// RevokeOthersForUser mencabut semua session user kecuali keepID
+	RevokeOthersForUser(ctx context.Context, userID int, keepID string, reason string) error
+}
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	// FindUserID mengembalikan pemilik token yang masih berlaku tanpa memakainya
+	FindUserID(ctx context.Context, tokenHash string) (int, error)
+	// Consume memakai token reset dan mengganti password user dalam satu transaksi
+	Consume(ctx context.Context, tokenHash string, newPasswordHash string) (int, error)
+}
+
+type MFARepository interface {
+	// FindByUserID mengembalikan domain.ErrMFANotEnabled jika user belum pernah memulai setup
+	FindByUserID(ctx context.Context, userID int) (*domain.UserMFA, error)
+	// SavePendingSecret menyimpan secret baru selama 2FA belum aktif
+	SavePendingSecret(ctx context.Context, userID int, secret string) error
+	// Enable mengaktifkan 2FA dan menyimpan recovery code dalam satu transaksi
+	Enable(ctx context.Context, userID int, usedStep int64, recoveryCodeHashes []string) error
+	Disable(ctx context.Context, userID int) error
+	// UseStep mencatat time step TOTP; false jika step tersebut (atau yang lebih baru) sudah dipakai
+	UseStep(ctx context.Context, userID int, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+	CountRecoveryCodes(ctx context.Context, userID int) (int, error)
+}
+
+type LoginFailureRepository interface {
+	// LockedUntil mengembalikan waktu akhir lock yang masih berlaku, atau nil
+	LockedUntil(ctx context.Context, scope, key string) (*time.Time, error)
+	// RegisterFailure menambah penghitung gagal; penghitung dimulai ulang jika gagal terakhir lebih lama dari window
+	RegisterFailure(ctx context.Context, scope, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, scope, key string, until time.Time) error
+	Reset(ctx context.Context, scope, key string) error
+}
+
+type AuthEventRepository interface {
+	Record(ctx context.Context, event *domain.AuthEvent) error
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey, keyHash string) error
+	FindByUserID(ctx context.Context, userID int) ([]domain.APIKey, error)
+	FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	// Revoke hanya mencabut key milik userID
+	Revoke(ctx context.Context, userID, id int) error
+	TouchLastUsed(ctx context.Context, id int) error
+}
+
+type UserIdentityRepository interface {
+	// FindUserID mengembalikan domain.ErrUserNotFound jika identitas belum terhubung ke user mana pun
+	FindUserID(ctx context.Context, provider, subject string) (int, error)
+	// Link menghubungkan identitas ke user atau memperbarui last_login_at jika sudah terhubung
+	Link(ctx context.Context, userID int, provider, subject, email string) error
 }
 
+type AlumniClaimRepository interface {
+	Create(ctx context.Context, userID, alumniID int, tokenHash string, expiresAt time.Time) error
+	// Consume memakai token klaim milik userID dan menghubungkan akun ke data alumni dalam satu transaksi
+	Consume(ctx context.Context, userID int, tokenHash string, meta domain.AuditMeta) (int, error)
+	// LinkIfUnclaimed mengembalikan ErrAlumniAlreadyLinked jika alumni atau akun sudah terhubung
+	LinkIfUnclaimed(ctx context.Context, userID, alumniID int, meta domain.AuditMeta) error
+}
+
+// Method yang mengubah data alumni, mahasiswa, dan pekerjaan menulis audit log dalam transaksi yang sama
 type AlumniRepository interface {
-	Create(ctx context.Context, alumni *domain.Alumni) (*domain.Alumni, error)
+	Create(ctx context.Context, alumni *domain.Alumni, meta domain.AuditMeta) (*domain.Alumni, error)
+	CreateMany(ctx context.Context, alumni []*domain.Alumni, meta domain.AuditMeta) error
+	FindExisting(ctx context.Context, nims, emails []string) (existingNIMs, existingEmails map[string]bool, err error)
 	FindAll(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Alumni], error)
+	StreamAll(ctx context.Context, params domain.PaginationParams, fn func(*domain.Alumni) error) error
 	FindByID(ctx context.Context, id int) (*domain.Alumni, error)
-	Update(ctx context.Context, alumni *domain.Alumni) (*domain.Alumni, error)
-	Delete(ctx context.Context, id int) error
+	FindByNIM(ctx context.Context, nim string) (*domain.Alumni, error)
+	FindByUserID(ctx context.Context, userID int) (*domain.Alumni, error)
+	SetUser(ctx context.Context, alumniID int, userID *int, meta domain.AuditMeta) error
+	Update(ctx context.Context, alumni *domain.Alumni, meta domain.AuditMeta) (*domain.Alumni, error)
+	Delete(ctx context.Context, id int, meta domain.AuditMeta) error
 }
 
 type MahasiswaRepository interface {
-	Create(ctx context.Context, mahasiswa *domain.Mahasiswa) (*domain.Mahasiswa, error)
+	Create(ctx context.Context, mahasiswa *domain.Mahasiswa, meta domain.AuditMeta) (*domain.Mahasiswa, error)
 	FindAll(ctx context.Context) ([]domain.Mahasiswa, error)
 	FindByID(ctx context.Context, id int) (*domain.Mahasiswa, error)
-	Update(ctx context.Context, mahasiswa *domain.Mahasiswa) (*domain.Mahasiswa, error)
-	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, mahasiswa *domain.Mahasiswa, meta domain.AuditMeta) (*domain.Mahasiswa, error)
+	Delete(ctx context.Context, id int, meta domain.AuditMeta) error
 }
 
 type PekerjaanRepository interface {
-	Create(ctx context.Context, pekerjaan *domain.Pekerjaan) (*domain.Pekerjaan, error)
+	Create(ctx context.Context, pekerjaan *domain.Pekerjaan, meta domain.AuditMeta) (*domain.Pekerjaan, error)
 	FindAll(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Pekerjaan], error)
+	StreamAll(ctx context.Context, params domain.PaginationParams, fn func(*domain.Pekerjaan) error) error
 	FindByID(ctx context.Context, id int) (*domain.Pekerjaan, error)
-	Update(ctx context.Context, pekerjaan *domain.Pekerjaan) (*domain.Pekerjaan, error)
-	Delete(ctx context.Context, id int) error
+	FindByAlumniID(ctx context.Context, alumniID int) ([]domain.Pekerjaan, error)
+	Update(ctx context.Context, pekerjaan *domain.Pekerjaan, meta domain.AuditMeta) (*domain.Pekerjaan, error)
+	Delete(ctx context.Context, id int, meta domain.AuditMeta) error
+}
+
+type AuditRepository interface {
+	FindAll(ctx context.Context, filter domain.AuditFilter) (*domain.PaginationResult[domain.AuditLog], error)
+	// FindByEntity mengembalikan semua catatan satu data, urut dari yang paling lama
+	FindByEntity(ctx context.Context, entityType string, entityID int) ([]domain.AuditLog, error)
+}
+
+type JobRepository interface {
+	Create(ctx context.Context, job *domain.Job) (*domain.Job, error)
+	FindByID(ctx context.Context, id int64) (*domain.Job, error)
+	FindAll(ctx context.Context, filter domain.JobFilter) (*domain.PaginationResult[domain.Job], error)
+	RequestCancel(ctx context.Context, id int64) (*domain.Job, error)
+	DeleteFinishedBefore(ctx context.Context, before time.Time) ([]string, error)
+
+	// Dipakai worker; perubahan status hanya berhasil selama workerID masih memegang job
+	Claim(ctx context.Context, workerID string, lease time.Duration) (*domain.Job, error)
+	Heartbeat(ctx context.Context, id int64, workerID string, done, total int, lease time.Duration) (cancelRequested bool, err error)
+	Complete(ctx context.Context, job *domain.Job, workerID string) error
+	Retry(ctx context.Context, id int64, workerID, lastError string, runAt time.Time) error
+	Release(ctx context.Context, id int64, workerID string) error
+	Finish(ctx context.Context, id int64, workerID, status string, lastError *string) error
+}
+
+type TracerRepository interface {
+	CreateQuestionnaire(ctx context.Context, q *domain.Questionnaire) (*domain.Questionnaire, error)
+	UpdateQuestionnaire(ctx context.Context, q *domain.Questionnaire) (*domain.Questionnaire, error)
+	FindQuestionnaires(ctx context.Context) ([]domain.Questionnaire, error)
+	FindQuestionnaireByID(ctx context.Context, id int) (*domain.Questionnaire, error)
+
+	CreateVersion(ctx context.Context, questionnaireID int, questions []domain.Question, createdBy *int) (*domain.QuestionnaireVersion, error)
+	FindVersions(ctx context.Context, questionnaireID int) ([]domain.QuestionnaireVersion, error)
+	FindVersion(ctx context.Context, questionnaireID, version int) (*domain.QuestionnaireVersion, error)
+
+	// CreateInvitations mengembalikan map alumni_id -> invitation_id untuk undangan yang baru dibuat
+	CreateInvitations(ctx context.Context, questionnaireID int, invitations []InvitationToken) (map[int]int, error)
+	// RotateInvitationToken tidak mengganti token undangan yang sudah diisi; cek SubmittedAt pada hasilnya
+	RotateInvitationToken(ctx context.Context, questionnaireID, invitationID int, tokenHash string) (*domain.QuestionnaireInvitation, error)
+	MarkInvitationSent(ctx context.Context, invitationID int) error
+	FindInvitationByToken(ctx context.Context, tokenHash string) (*domain.QuestionnaireInvitation, error)
+	FindInvitations(ctx context.Context, questionnaireID int, status string, page, limit int) (*domain.PaginationResult[domain.QuestionnaireInvitation], error)
+
+	// CreateSubmission mengembalikan ErrAlreadySubmitted jika alumni sudah mengisi pada periode yang sama
+	CreateSubmission(ctx context.Context, invitation *domain.QuestionnaireInvitation, version *domain.QuestionnaireVersion, period string, answers map[string]interface{}) (*domain.QuestionnaireSubmission, error)
+	HasSubmittedPeriod(ctx context.Context, alumniID int, period string) (bool, error)
+	HasSubmissions(ctx context.Context, questionnaireID int) (bool, error)
+	FindSubmissions(ctx context.Context, questionnaireID, page, limit int) (*domain.PaginationResult[domain.QuestionnaireSubmission], error)
+	ResponseRates(ctx context.Context, questionnaireID int, groupBy string) ([]domain.ResponseRate, error)
+}
+
+type StatsRepository interface {
+	Employment(ctx context.Context, filter domain.StatsFilter, graduationMonth int) (*domain.EmploymentSummary, error)
+	// Distribution mengelompokkan alumni berdasarkan kolom pekerjaan: bidang_industri atau gaji_range
+	Distribution(ctx context.Context, filter domain.StatsFilter, column string) (*domain.StatsDistribution, error)
 }
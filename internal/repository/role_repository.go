@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type roleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRoleRepository(db *pgxpool.Pool) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+const roleSelectSQL = `
+		SELECT r.id, r.name, COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}') as permissions
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id`
+
+func (r *roleRepository) FindAll(ctx context.Context) ([]domain.Role, error) {
+	rows, err := r.db.Query(ctx, roleSelectSQL+` GROUP BY r.id ORDER BY r.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []domain.Role{}
+	for rows.Next() {
+		var role domain.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Permissions); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *roleRepository) FindByID(ctx context.Context, id int) (*domain.Role, error) {
+	var role domain.Role
+	err := r.db.QueryRow(ctx, roleSelectSQL+` WHERE r.id = $1 GROUP BY r.id`, id).Scan(&role.ID, &role.Name, &role.Permissions)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := ensureRoleNameFree(ctx, tx, role.Name, 0); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `INSERT INTO roles (name) VALUES ($1) RETURNING id`, role.Name).Scan(&role.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := replaceRolePermissions(ctx, tx, role.ID, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (r *roleRepository) Rename(ctx context.Context, id int, name string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := ensureRoleNameFree(ctx, tx, name, id); err != nil {
+		return err
+	}
+
+	cmdTag, err := tx.Exec(ctx, `UPDATE roles SET name = $1 WHERE id = $2`, name, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() != 1 {
+		return domain.ErrRoleNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *roleRepository) SetPermissions(ctx context.Context, id int, permissionNames []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM roles WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrRoleNotFound
+	}
+
+	if err := replaceRolePermissions(ctx, tx, id, permissionNames); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *roleRepository) Delete(ctx context.Context, id int) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM roles WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() != 1 {
+		return domain.ErrRoleNotFound
+	}
+	return nil
+}
+
+func (r *roleRepository) FindAllPermissions(ctx context.Context) ([]domain.Permission, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, name, description FROM permissions ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []domain.Permission{}
+	for rows.Next() {
+		var p domain.Permission
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, rows.Err()
+}
+
+// ensureRoleNameFree menolak nama role yang sudah dipakai role lain
+func ensureRoleNameFree(ctx context.Context, tx pgx.Tx, name string, exceptID int) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM roles WHERE name = $1 AND id <> $2)`
+	if err := tx.QueryRow(ctx, query, name, exceptID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrRoleExists
+	}
+	return nil
+}
+
+// replaceRolePermissions mengganti seluruh permission role; nama yang tidak dikenal membatalkan transaksi
+func replaceRolePermissions(ctx context.Context, tx pgx.Tx, roleID int, permissionNames []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM role_permissions WHERE role_id = $1`, roleID); err != nil {
+		return err
+	}
+	if len(permissionNames) == 0 {
+		return nil
+	}
+
+	insertSQL := `INSERT INTO role_permissions (role_id, permission_id) SELECT $1, id FROM permissions WHERE name = ANY($2)`
+	cmdTag, err := tx.Exec(ctx, insertSQL, roleID, permissionNames)
+	if err != nil {
+		return err
+	}
+	if int(cmdTag.RowsAffected()) != len(permissionNames) {
+		return domain.ErrPermissionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// cachedSessionRepository menyimpan hasil FindByID di memori agar middleware auth tidak query
+// database di setiap request. Revoke lewat instance ini langsung menghapus cache; revoke dari
+// instance server lain baru terlihat setelah entry cache kedaluwarsa (maksimal ttl).
+type cachedSessionRepository struct {
+	SessionRepository
+	ttl time.Duration
+
+	mu         sync.Mutex
+	entries    map[string]sessionCacheEntry
+	lastSweep  time.Time
+	generation uint64 // naik setiap ada revoke, agar hasil query yang mendahului revoke tidak disimpan
+}
+
+type sessionCacheEntry struct {
+	session  domain.Session
+	cachedAt time.Time
+}
+
+func NewCachedSessionRepository(inner SessionRepository, ttl time.Duration) SessionRepository {
+	if ttl <= 0 {
+		return inner
+	}
+	return &cachedSessionRepository{
+		SessionRepository: inner,
+		ttl:               ttl,
+		entries:           map[string]sessionCacheEntry{},
+		lastSweep:         time.Now(),
+	}
+}
+
+func (r *cachedSessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	now := time.Now()
+
+	r.mu.Lock()
+	entry, ok := r.entries[id]
+	generation := r.generation
+	r.mu.Unlock()
+	if ok && now.Sub(entry.cachedAt) < r.ttl {
+		session := entry.session
+		return &session, nil
+	}
+
+	session, err := r.SessionRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if r.generation == generation {
+		r.entries[id] = sessionCacheEntry{session: *session, cachedAt: now}
+	}
+	r.sweep(now)
+	r.mu.Unlock()
+	return session, nil
+}
+
+func (r *cachedSessionRepository) RotateRefreshToken(ctx context.Context, oldHash, newHash string, newExpiresAt time.Time, client domain.ClientInfo) (*domain.Session, error) {
+	session, err := r.SessionRepository.RotateRefreshToken(ctx, oldHash, newHash, newExpiresAt, client)
+	if errors.Is(err, domain.ErrRefreshTokenReused) {
+		// Session yang dicabut karena reuse tidak diketahui ID-nya di sini, jadi kosongkan semua cache
+		r.mu.Lock()
+		r.entries = map[string]sessionCacheEntry{}
+		r.generation++
+		r.mu.Unlock()
+	}
+	return session, err
+}
+
+func (r *cachedSessionRepository) Revoke(ctx context.Context, id string, reason string) error {
+	err := r.SessionRepository.Revoke(ctx, id, reason)
+	r.forget(func(s *domain.Session) bool { return s.ID == id })
+	return err
+}
+
+func (r *cachedSessionRepository) RevokeForUser(ctx context.Context, userID int, id string, reason string) error {
+	err := r.SessionRepository.RevokeForUser(ctx, userID, id, reason)
+	r.forget(func(s *domain.Session) bool { return s.ID == id })
+	return err
+}
+
+func (r *cachedSessionRepository) RevokeAllForUser(ctx context.Context, userID int, reason string) error {
+	err := r.SessionRepository.RevokeAllForUser(ctx, userID, reason)
+	r.forget(func(s *domain.Session) bool { return s.UserID == userID })
+	return err
+}
+
+func (r *cachedSessionRepository) RevokeOthersForUser(ctx context.Context, userID int, keepID string, reason string) error {
+	err := r.SessionRepository.RevokeOthersForUser(ctx, userID, keepID, reason)
+	r.forget(func(s *domain.Session) bool { return s.UserID == userID && s.ID != keepID })
+	return err
+}
+
+// forget menghapus entry yang cocok; dipanggil juga saat revoke gagal karena sebagian baris mungkin sudah berubah
+func (r *cachedSessionRepository) forget(match func(s *domain.Session) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	for id, entry := range r.entries {
+		if match(&entry.session) {
+			delete(r.entries, id)
+		}
+	}
+}
+
+// sweep membuang entry kedaluwarsa secara berkala agar map tidak tumbuh terus; mu harus sudah dikunci
+func (r *cachedSessionRepository) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < r.ttl {
+		return
+	}
+	for id, entry := range r.entries {
+		if now.Sub(entry.cachedAt) >= r.ttl {
+			delete(r.entries, id)
+		}
+	}
+	r.lastSweep = now
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubSessionRepo menyimpan session di memori; onFind dipanggil setelah data dibaca tetapi sebelum
+// FindByID selesai, untuk mensimulasikan revoke yang terjadi saat query masih berjalan
+type stubSessionRepo struct {
+	SessionRepository
+	sessions  map[string]domain.Session
+	finds     map[string]int
+	onFind    func(id string)
+	rotateErr error
+}
+
+func newStubSessionRepo(sessions ...domain.Session) *stubSessionRepo {
+	r := &stubSessionRepo{sessions: map[string]domain.Session{}, finds: map[string]int{}}
+	for _, s := range sessions {
+		r.sessions[s.ID] = s
+	}
+	return r
+}
+
+func (r *stubSessionRepo) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, domain.ErrSessionNotFound
+	}
+	r.finds[id]++
+	if r.onFind != nil {
+		r.onFind(id)
+	}
+	return &s, nil
+}
+
+func (r *stubSessionRepo) RotateRefreshToken(ctx context.Context, oldHash, newHash string, newExpiresAt time.Time, client domain.ClientInfo) (*domain.Session, error) {
+	if r.rotateErr != nil {
+		return nil, r.rotateErr
+	}
+	s := r.sessions["a"]
+	return &s, nil
+}
+
+func (r *stubSessionRepo) revoke(match func(s domain.Session) bool) {
+	now := time.Now()
+	for id, s := range r.sessions {
+		if match(s) {
+			s.RevokedAt = &now
+			r.sessions[id] = s
+		}
+	}
+}
+
+func (r *stubSessionRepo) Revoke(ctx context.Context, id string, reason string) error {
+	r.revoke(func(s domain.Session) bool { return s.ID == id })
+	return nil
+}
+
+func (r *stubSessionRepo) RevokeForUser(ctx context.Context, userID int, id string, reason string) error {
+	r.revoke(func(s domain.Session) bool { return s.UserID == userID && s.ID == id })
+	return nil
+}
+
+func (r *stubSessionRepo) RevokeAllForUser(ctx context.Context, userID int, reason string) error {
+	r.revoke(func(s domain.Session) bool { return s.UserID == userID })
+	return nil
+}
+
+func (r *stubSessionRepo) RevokeOthersForUser(ctx context.Context, userID int, keepID string, reason string) error {
+	r.revoke(func(s domain.Session) bool { return s.UserID == userID && s.ID != keepID })
+	return nil
+}
+
+// newTestSessionCache: session a dan b milik user 1, c milik user 2; ketiganya sudah ada di cache
+func newTestSessionCache(t *testing.T) (*cachedSessionRepository, *stubSessionRepo) {
+	t.Helper()
+	inner := newStubSessionRepo(
+		domain.Session{ID: "a", UserID: 1},
+		domain.Session{ID: "b", UserID: 1},
+		domain.Session{ID: "c", UserID: 2},
+	)
+	cache := NewCachedSessionRepository(inner, time.Minute).(*cachedSessionRepository)
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := cache.FindByID(context.Background(), id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cache, inner
+}
+
+func TestNewCachedSessionRepositoryDisabled(t *testing.T) {
+	inner := newStubSessionRepo()
+	if got := NewCachedSessionRepository(inner, 0); got != SessionRepository(inner) {
+		t.Fatalf("ttl 0 should return the inner repository, got %T", got)
+	}
+}
+
+func TestSessionCacheFindByID(t *testing.T) {
+	cache, inner := newTestSessionCache(t)
+	ctx := context.Background()
+
+	s, err := cache.FindByID(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inner.finds["a"] != 1 {
+		t.Fatalf("inner FindByID called %d times, want 1", inner.finds["a"])
+	}
+	// Mengubah hasil tidak boleh mengubah isi cache
+	s.UserID = 99
+	if s, _ := cache.FindByID(ctx, "a"); s.UserID != 1 {
+		t.Fatalf("cached session modified through returned pointer: %+v", s)
+	}
+
+	if _, err := cache.FindByID(ctx, "missing"); !errors.Is(err, domain.ErrSessionNotFound) {
+		t.Fatalf("err = %v, want ErrSessionNotFound", err)
+	}
+	if _, ok := cache.entries["missing"]; ok {
+		t.Fatal("error result was cached")
+	}
+
+	// Entry yang sudah kedaluwarsa diambil ulang dari database
+	entry := cache.entries["a"]
+	entry.cachedAt = time.Now().Add(-time.Minute)
+	cache.entries["a"] = entry
+	if _, err := cache.FindByID(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if inner.finds["a"] != 2 {
+		t.Fatalf("expired entry: inner FindByID called %d times, want 2", inner.finds["a"])
+	}
+}
+
+func TestSessionCacheRevokeInvalidates(t *testing.T) {
+	tests := []struct {
+		name        string
+		revoke      func(r SessionRepository) error
+		wantRevoked []string
+		wantCached  []string
+	}{
+		{
+			name:        "Revoke",
+			revoke:      func(r SessionRepository) error { return r.Revoke(context.Background(), "a", "logout") },
+			wantRevoked: []string{"a"},
+			wantCached:  []string{"b", "c"},
+		},
+		{
+			name:        "RevokeForUser",
+			revoke:      func(r SessionRepository) error { return r.RevokeForUser(context.Background(), 1, "b", "logout") },
+			wantRevoked: []string{"b"},
+			wantCached:  []string{"a", "c"},
+		},
+		{
+			name: "RevokeAllForUser",
+			revoke: func(r SessionRepository) error {
+				return r.RevokeAllForUser(context.Background(), 1, "password_changed")
+			},
+			wantRevoked: []string{"a", "b"},
+			wantCached:  []string{"c"},
+		},
+		{
+			name: "RevokeOthersForUser",
+			revoke: func(r SessionRepository) error {
+				return r.RevokeOthersForUser(context.Background(), 1, "a", "logout_others")
+			},
+			wantRevoked: []string{"b"},
+			wantCached:  []string{"a", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, inner := newTestSessionCache(t)
+			if err := tt.revoke(cache); err != nil {
+				t.Fatal(err)
+			}
+			for _, id := range tt.wantRevoked {
+				s, err := cache.FindByID(context.Background(), id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if s.RevokedAt == nil {
+					t.Errorf("session %s still served from cache after revoke", id)
+				}
+			}
+			for _, id := range tt.wantCached {
+				if _, ok := cache.entries[id]; !ok {
+					t.Errorf("session %s was evicted but not revoked", id)
+				}
+				if inner.finds[id] != 1 {
+					t.Errorf("session %s: inner FindByID called %d times, want 1", id, inner.finds[id])
+				}
+			}
+		})
+	}
+}
+
+func TestSessionCacheRevokeDuringRead(t *testing.T) {
+	inner := newStubSessionRepo(domain.Session{ID: "a", UserID: 1})
+	cache := NewCachedSessionRepository(inner, time.Minute).(*cachedSessionRepository)
+	ctx := context.Background()
+
+	// Query pertama sudah membaca session sebelum revoke selesai
+	inner.onFind = func(id string) {
+		inner.onFind = nil
+		if err := cache.Revoke(ctx, id, "logout"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stale, err := cache.FindByID(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stale.RevokedAt != nil {
+		t.Fatal("stub should return the session as read before the revoke")
+	}
+	if _, ok := cache.entries["a"]; ok {
+		t.Fatal("read that started before the revoke repopulated the cache")
+	}
+
+	s, err := cache.FindByID(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.RevokedAt == nil {
+		t.Fatal("revoked session served as active")
+	}
+	if _, ok := cache.entries["a"]; !ok {
+		t.Fatal("read after the revoke should be cached")
+	}
+}
+
+func TestSessionCacheRotateReuseFlushesAll(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantFlush bool
+	}{
+		{"success keeps cache", nil, false},
+		{"invalid token keeps cache", domain.ErrInvalidRefreshToken, false},
+		{"reuse flushes cache", domain.ErrRefreshTokenReused, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, inner := newTestSessionCache(t)
+			inner.rotateErr = tt.err
+			generation := cache.generation
+
+			_, err := cache.RotateRefreshToken(context.Background(), "old", "new", time.Now().Add(time.Hour), domain.ClientInfo{})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if flushed := len(cache.entries) == 0; flushed != tt.wantFlush {
+				t.Fatalf("%d entries left, want flush = %v", len(cache.entries), tt.wantFlush)
+			}
+			if bumped := cache.generation != generation; bumped != tt.wantFlush {
+				t.Fatalf("generation bumped = %v, want %v", bumped, tt.wantFlush)
+			}
+		})
+	}
+}
+
+func TestSessionCacheRotateReuseDuringRead(t *testing.T) {
+	inner := newStubSessionRepo(domain.Session{ID: "a", UserID: 1})
+	inner.rotateErr = domain.ErrRefreshTokenReused
+	cache := NewCachedSessionRepository(inner, time.Minute).(*cachedSessionRepository)
+	ctx := context.Background()
+
+	inner.onFind = func(string) {
+		inner.onFind = nil
+		if _, err := cache.RotateRefreshToken(ctx, "old", "new", time.Now().Add(time.Hour), domain.ClientInfo{}); !errors.Is(err, domain.ErrRefreshTokenReused) {
+			t.Fatalf("err = %v, want ErrRefreshTokenReused", err)
+		}
+	}
+	if _, err := cache.FindByID(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.entries) != 0 {
+		t.Fatal("read that started before the reuse flush repopulated the cache")
+	}
+}
+
+func TestSessionCacheSweep(t *testing.T) {
+	cache, _ := newTestSessionCache(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	expire := func(id string) {
+		entry := cache.entries[id]
+		entry.cachedAt = now.Add(-2 * time.Minute)
+		cache.entries[id] = entry
+	}
+	expire("a")
+	expire("b")
+
+	// Sweep baru berjalan satu ttl setelah sweep terakhir
+	if _, err := cache.FindByID(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.entries) != 3 {
+		t.Fatalf("sweep ran too early: %d entries left, want 3", len(cache.entries))
+	}
+
+	cache.lastSweep = now.Add(-2 * time.Minute)
+	if _, err := cache.FindByID(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.entries["a"]; ok {
+		t.Error("expired entry a not swept")
+	}
+	for _, id := range []string{"b", "c"} {
+		if _, ok := cache.entries[id]; !ok {
+			t.Errorf("fresh entry %s was swept", id)
+		}
+	}
+	if !cache.lastSweep.After(now.Add(-time.Minute)) {
+		t.Errorf("lastSweep not updated: %v", cache.lastSweep)
+	}
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type sessionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepository(db *pgxpool.Pool) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *domain.Session, refreshTokenHash string, refreshExpiresAt time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sessionSQL := `
+		INSERT INTO user_sessions (id, user_id, user_agent, ip_address, expires_at, impersonator_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, last_seen_at`
+	err = tx.QueryRow(ctx, sessionSQL, session.ID, session.UserID, session.UserAgent, session.IPAddress, session.ExpiresAt, session.ImpersonatorID).Scan(&session.CreatedAt, &session.LastSeenAt)
+	if err != nil {
+		return err
+	}
+
+	tokenSQL := `INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(ctx, tokenSQL, session.ID, refreshTokenHash, refreshExpiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *sessionRepository) RotateRefreshToken(ctx context.Context, oldHash, newHash string, newExpiresAt time.Time, client domain.ClientInfo) (*domain.Session, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var tokenID int
+	var usedAt *time.Time
+	var tokenExpiresAt time.Time
+	var s domain.Session
+	query := `
+		SELECT rt.id, rt.used_at, rt.expires_at, ` + sessionColumns + `
+		FROM refresh_tokens rt
+		JOIN user_sessions s ON s.id = rt.session_id
+		WHERE rt.token_hash = $1
+		FOR UPDATE OF rt, s`
+	err = tx.QueryRow(ctx, query, oldHash).Scan(append([]interface{}{&tokenID, &usedAt, &tokenExpiresAt}, sessionFields(&s)...)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if err := checkRefreshToken(&s, usedAt, tokenExpiresAt, time.Now()); err != nil {
+		if err != domain.ErrRefreshTokenReused {
+			return nil, err
+		}
+		revokeSQL := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = 'refresh_token_reuse' WHERE id = $1`
+		if _, err := tx.Exec(ctx, revokeSQL, s.ID); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1`, tokenID); err != nil {
+		return nil, err
+	}
+
+	insertSQL := `INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(ctx, insertSQL, s.ID, newHash, newExpiresAt); err != nil {
+		return nil, err
+	}
+
+	touchSQL := `UPDATE user_sessions SET last_seen_at = NOW(), ip_address = $2 WHERE id = $1 RETURNING last_seen_at`
+	if err := tx.QueryRow(ctx, touchSQL, s.ID, client.IPAddress).Scan(&s.LastSeenAt); err != nil {
+		return nil, err
+	}
+	s.IPAddress = client.IPAddress
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// checkRefreshToken menentukan apakah refresh token boleh dirotasi. Token yang sudah pernah dirotasi
+// lalu dipakai lagi dianggap dicuri (ErrRefreshTokenReused) dan seluruh session-nya harus dicabut.
+func checkRefreshToken(s *domain.Session, usedAt *time.Time, tokenExpiresAt, now time.Time) error {
+	if s.RevokedAt != nil || now.After(s.ExpiresAt) || now.After(tokenExpiresAt) {
+		return domain.ErrInvalidRefreshToken
+	}
+	if usedAt != nil {
+		return domain.ErrRefreshTokenReused
+	}
+	return nil
+}
+
+func (r *sessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	var s domain.Session
+	query := `SELECT ` + sessionColumns + ` FROM user_sessions s WHERE s.id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(sessionFields(&s)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *sessionRepository) FindActiveByUserID(ctx context.Context, userID int) ([]domain.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM user_sessions s
+		WHERE s.user_id = $1 AND s.revoked_at IS NULL AND s.expires_at > NOW()
+		ORDER BY s.last_seen_at DESC`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain.Session{}
+	for rows.Next() {
+		var s domain.Session
+		if err := rows.Scan(sessionFields(&s)...); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, id string, reason string) error {
+	query := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $2 WHERE id = $1 AND revoked_at IS NULL`
+	_, err := r.db.Exec(ctx, query, id, reason)
+	return err
+}
+
+func (r *sessionRepository) RevokeForUser(ctx context.Context, userID int, id string, reason string) error {
+	query := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, id, userID, reason)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *sessionRepository) RevokeAllForUser(ctx context.Context, userID int, reason string) error {
+	query := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $2 WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := r.db.Exec(ctx, query, userID, reason)
+	return err
+}
+
+func (r *sessionRepository) RevokeOthersForUser(ctx context.Context, userID int, keepID string, reason string) error {
+	query := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $3 WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL`
+	_, err := r.db.Exec(ctx, query, userID, keepID, reason)
+	return err
+}
+
+// sessionColumns dan sessionFields harus selalu berurutan sama; tabel user_sessions di-alias "s"
+const sessionColumns = `s.id, s.user_id, s.user_agent, s.ip_address, s.created_at, s.last_seen_at, s.expires_at, s.revoked_at, s.revoked_reason, s.impersonator_id`
+
+func sessionFields(s *domain.Session) []interface{} {
+	return []interface{}{&s.ID, &s.UserID, &s.UserAgent, &s.IPAddress, &s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt, &s.RevokedAt, &s.RevokedReason, &s.ImpersonatorID}
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestCheckRefreshToken(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	revoked := now.Add(-time.Hour)
+	used := now.Add(-time.Minute)
+
+	tests := []struct {
+		name           string
+		session        domain.Session
+		usedAt         *time.Time
+		tokenExpiresAt time.Time
+		want           error
+	}{
+		{"valid", domain.Session{ExpiresAt: now.Add(time.Hour)}, nil, now.Add(time.Hour), nil},
+		{"reused", domain.Session{ExpiresAt: now.Add(time.Hour)}, &used, now.Add(time.Hour), domain.ErrRefreshTokenReused},
+		{"session revoked", domain.Session{ExpiresAt: now.Add(time.Hour), RevokedAt: &revoked}, nil, now.Add(time.Hour), domain.ErrInvalidRefreshToken},
+		{"session expired", domain.Session{ExpiresAt: now.Add(-time.Second)}, nil, now.Add(time.Hour), domain.ErrInvalidRefreshToken},
+		{"token expired", domain.Session{ExpiresAt: now.Add(time.Hour)}, nil, now.Add(-time.Second), domain.ErrInvalidRefreshToken},
+		// Token curian pada session yang sudah dicabut tidak perlu mencabut ulang
+		{"reused on revoked session", domain.Session{ExpiresAt: now.Add(time.Hour), RevokedAt: &revoked}, &used, now.Add(time.Hour), domain.ErrInvalidRefreshToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkRefreshToken(&tt.session, tt.usedAt, tt.tokenExpiresAt, now); got != tt.want {
+				t.Fatalf("checkRefreshToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type statsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewStatsRepository(db *pgxpool.Pool) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+// statsCohort menyusun CTE cohort berisi alumni yang cocok dengan filter
+func statsCohort(filter domain.StatsFilter) (string, []interface{}) {
+	var args []interface{}
+	var whereClauses []string
+	add := func(clause string, value interface{}) {
+		args = append(args, value)
+		whereClauses = append(whereClauses, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Jurusan != "" {
+		add("LOWER(a.jurusan) = LOWER($%d)", filter.Jurusan)
+	}
+	if filter.Angkatan != nil {
+		add("a.angkatan = $%d", *filter.Angkatan)
+	}
+	if filter.TahunLulusFrom != nil {
+		add("a.tahun_lulus >= $%d", *filter.TahunLulusFrom)
+	}
+	if filter.TahunLulusTo != nil {
+		add("a.tahun_lulus <= $%d", *filter.TahunLulusTo)
+	}
+
+	whereSQL := ""
+	if len(whereClauses) > 0 {
+		whereSQL = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+	return `cohort AS (SELECT a.id, a.tahun_lulus FROM alumni a` + whereSQL + `)`, args
+}
+
+// Employment menghitung statistik keseluruhan dan per tahun_lulus dalam satu query (GROUPING SETS).
+// Masa tunggu dalam bulan dari graduationMonth pada tahun lulus sampai pekerjaan pertama dimulai;
+// pekerjaan yang dimulai sebelum lulus dihitung 0 bulan.
+func (r *statsRepository) Employment(ctx context.Context, filter domain.StatsFilter, graduationMonth int) (*domain.EmploymentSummary, error) {
+	cohort, args := statsCohort(filter)
+	args = append(args, graduationMonth)
+	query := fmt.Sprintf(`
+		WITH %s,
+		jobs AS (
+			SELECT p.alumni_id, MIN(p.tanggal_mulai_kerja) AS first_start,
+				BOOL_OR(p.tanggal_selesai_kerja IS NULL OR p.tanggal_selesai_kerja >= CURRENT_DATE) AS is_current
+			FROM pekerjaan p JOIN cohort c ON c.id = p.alumni_id
+			GROUP BY p.alumni_id
+		),
+		w AS (
+			SELECT c.tahun_lulus, j.alumni_id IS NOT NULL AS employed, COALESCE(j.is_current, FALSE) AS is_current,
+				CASE WHEN j.first_start IS NOT NULL THEN GREATEST(0,
+					(EXTRACT(YEAR FROM j.first_start)::int - c.tahun_lulus) * 12 + EXTRACT(MONTH FROM j.first_start)::int - $%d)
+				END AS waiting
+			FROM cohort c LEFT JOIN jobs j ON j.alumni_id = c.id
+		)
+		SELECT GROUPING(tahun_lulus) = 1, tahun_lulus,
+			COUNT(*), COUNT(*) FILTER (WHERE employed), COUNT(*) FILTER (WHERE is_current),
+			COALESCE(ROUND(COUNT(*) FILTER (WHERE employed)::numeric / NULLIF(COUNT(*), 0), 4), 0)::float8,
+			ROUND(AVG(waiting), 1)::float8,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY waiting),
+			COUNT(*) FILTER (WHERE waiting <= 6)
+		FROM w
+		GROUP BY GROUPING SETS ((), (tahun_lulus))
+		ORDER BY GROUPING(tahun_lulus) DESC, tahun_lulus`, cohort, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := &domain.EmploymentSummary{ByTahunLulus: []domain.CohortEmploymentStats{}}
+	for rows.Next() {
+		var overall bool
+		var tahunLulus *int
+		var s domain.EmploymentStats
+		if err := rows.Scan(&overall, &tahunLulus, &s.TotalAlumni, &s.Employed, &s.CurrentlyEmployed, &s.EmploymentRate,
+			&s.AvgWaitingMonths, &s.MedianWaitingMonths, &s.EmployedWithin6Months); err != nil {
+			return nil, err
+		}
+		if overall {
+			summary.EmploymentStats = s
+		} else if tahunLulus != nil {
+			summary.ByTahunLulus = append(summary.ByTahunLulus, domain.CohortEmploymentStats{TahunLulus: *tahunLulus, EmploymentStats: s})
+		}
+	}
+	return summary, rows.Err()
+}
+
+// Distribution menghitung jumlah alumni per nilai kolom pekerjaan (bidang_industri atau gaji_range).
+// Setiap alumni dihitung sekali memakai pekerjaan terbaru atau pertamanya sesuai filter.Job.
+func (r *statsRepository) Distribution(ctx context.Context, filter domain.StatsFilter, column string) (*domain.StatsDistribution, error) {
+	groupSQL, ok := map[string]string{
+		"bidang_industri": "NULLIF(TRIM(p.bidang_industri), '')",
+		"gaji_range":      "NULLIF(TRIM(p.gaji_range), '')",
+	}[column]
+	if !ok {
+		return nil, fmt.Errorf("unknown distribution column %q", column)
+	}
+	order := "DESC"
+	if filter.Job == "first" {
+		order = "ASC"
+	}
+
+	cohort, args := statsCohort(filter)
+	query := fmt.Sprintf(`
+		WITH %s,
+		job AS (
+			SELECT DISTINCT ON (p.alumni_id) %s AS grp
+			FROM pekerjaan p JOIN cohort c ON c.id = p.alumni_id
+			ORDER BY p.alumni_id, p.tanggal_mulai_kerja %s, p.id %s
+		)
+		SELECT grp, COUNT(*), (SUM(COUNT(*)) OVER ())::int, ROUND(COUNT(*)::numeric / SUM(COUNT(*)) OVER (), 4)::float8
+		FROM job
+		GROUP BY grp
+		ORDER BY COUNT(*) DESC, grp NULLS LAST`, cohort, groupSQL, order, order)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dist := &domain.StatsDistribution{Buckets: []domain.StatsBucket{}}
+	for rows.Next() {
+		var b domain.StatsBucket
+		if err := rows.Scan(&b.Group, &b.Count, &dist.Total, &b.Percentage); err != nil {
+			return nil, err
+		}
+		dist.Buckets = append(dist.Buckets, b)
+	}
+	return dist, rows.Err()
+}
@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type tracerRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTracerRepository(db *pgxpool.Pool) TracerRepository {
+	return &tracerRepository{db: db}
+}
+
+// InvitationToken adalah undangan baru beserta hash token yang dikirim ke alumni
+type InvitationToken struct {
+	AlumniID  int
+	TokenHash string
+}
+
+const questionnaireColumns = `q.id, q.title, q.description, q.period, q.opens_at, q.closes_at,
+	(SELECT MAX(v.version) FROM questionnaire_versions v WHERE v.questionnaire_id = q.id), q.created_at, q.updated_at`
+
+func scanQuestionnaire(row pgx.Row) (*domain.Questionnaire, error) {
+	var q domain.Questionnaire
+	err := row.Scan(&q.ID, &q.Title, &q.Description, &q.Period, &q.OpensAt, &q.ClosesAt, &q.CurrentVersion, &q.CreatedAt, &q.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrTracerNotFound
+		}
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *tracerRepository) CreateQuestionnaire(ctx context.Context, q *domain.Questionnaire) (*domain.Questionnaire, error) {
+	query := `
+		WITH q AS (
+			INSERT INTO questionnaires (title, description, period, opens_at, closes_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING *
+		)
+		SELECT ` + questionnaireColumns + ` FROM q`
+	return scanQuestionnaire(r.db.QueryRow(ctx, query, q.Title, q.Description, q.Period, q.OpensAt, q.ClosesAt))
+}
+
+func (r *tracerRepository) UpdateQuestionnaire(ctx context.Context, q *domain.Questionnaire) (*domain.Questionnaire, error) {
+	query := `
+		WITH q AS (
+			UPDATE questionnaires SET title = $2, description = $3, period = $4, opens_at = $5, closes_at = $6, updated_at = NOW()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + questionnaireColumns + ` FROM q`
+	return scanQuestionnaire(r.db.QueryRow(ctx, query, q.ID, q.Title, q.Description, q.Period, q.OpensAt, q.ClosesAt))
+}
+
+func (r *tracerRepository) FindQuestionnaires(ctx context.Context) ([]domain.Questionnaire, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+questionnaireColumns+` FROM questionnaires q ORDER BY q.created_at DESC, q.id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []domain.Questionnaire{}
+	for rows.Next() {
+		q, err := scanQuestionnaire(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *q)
+	}
+	return list, rows.Err()
+}
+
+func (r *tracerRepository) FindQuestionnaireByID(ctx context.Context, id int) (*domain.Questionnaire, error) {
+	return scanQuestionnaire(r.db.QueryRow(ctx, `SELECT `+questionnaireColumns+` FROM questionnaires q WHERE q.id = $1`, id))
+}
+
+const versionColumns = `id, questionnaire_id, version, questions, created_by, created_at`
+
+func scanVersion(row pgx.Row) (*domain.QuestionnaireVersion, error) {
+	var v domain.QuestionnaireVersion
+	var questions []byte
+	if err := row.Scan(&v.ID, &v.QuestionnaireID, &v.Version, &questions, &v.CreatedBy, &v.CreatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrVersionNotFound
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(questions, &v.Questions); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// CreateVersion menyimpan set pertanyaan sebagai versi berikutnya. Baris kuesioner dikunci agar
+// dua versi yang dibuat bersamaan tidak mendapat nomor yang sama.
+func (r *tracerRepository) CreateVersion(ctx context.Context, questionnaireID int, questions []domain.Question, createdBy *int) (*domain.QuestionnaireVersion, error) {
+	raw, err := json.Marshal(questions)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	if err := tx.QueryRow(ctx, `SELECT id FROM questionnaires WHERE id = $1 FOR UPDATE`, questionnaireID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrTracerNotFound
+		}
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO questionnaire_versions (questionnaire_id, version, questions, created_by)
+		VALUES ($1, (SELECT COALESCE(MAX(version), 0) + 1 FROM questionnaire_versions WHERE questionnaire_id = $1), $2, $3)
+		RETURNING ` + versionColumns
+	version, err := scanVersion(tx.QueryRow(ctx, query, questionnaireID, raw, createdBy))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE questionnaires SET updated_at = NOW() WHERE id = $1`, questionnaireID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (r *tracerRepository) FindVersions(ctx context.Context, questionnaireID int) ([]domain.QuestionnaireVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM questionnaire_versions WHERE questionnaire_id = $1 ORDER BY version`
+	rows, err := r.db.Query(ctx, query, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []domain.QuestionnaireVersion{}
+	for rows.Next() {
+		v, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *v)
+	}
+	return versions, rows.Err()
+}
+
+// FindVersion dengan version 0 mengembalikan versi terbaru
+func (r *tracerRepository) FindVersion(ctx context.Context, questionnaireID, version int) (*domain.QuestionnaireVersion, error) {
+	query := `
+		SELECT ` + versionColumns + ` FROM questionnaire_versions
+		WHERE questionnaire_id = $1 AND ($2 = 0 OR version = $2)
+		ORDER BY version DESC
+		LIMIT 1`
+	return scanVersion(r.db.QueryRow(ctx, query, questionnaireID, version))
+}
+
+// CreateInvitations mengembalikan ID alumni yang benar-benar diundang; alumni yang sudah
+// punya undangan untuk kuesioner ini dilewati
+func (r *tracerRepository) CreateInvitations(ctx context.Context, questionnaireID int, invitations []InvitationToken) (map[int]int, error) {
+	alumniIDs := make([]int, len(invitations))
+	hashes := make([]string, len(invitations))
+	for i, inv := range invitations {
+		alumniIDs[i] = inv.AlumniID
+		hashes[i] = inv.TokenHash
+	}
+
+	query := `
+		INSERT INTO questionnaire_invitations (questionnaire_id, alumni_id, token_hash)
+		SELECT $1, t.alumni_id, t.token_hash FROM UNNEST($2::int[], $3::text[]) AS t(alumni_id, token_hash)
+		ON CONFLICT (questionnaire_id, alumni_id) DO NOTHING
+		RETURNING alumni_id, id`
+	rows, err := r.db.Query(ctx, query, questionnaireID, alumniIDs, hashes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	created := map[int]int{}
+	for rows.Next() {
+		var alumniID, id int
+		if err := rows.Scan(&alumniID, &id); err != nil {
+			return nil, err
+		}
+		created[alumniID] = id
+	}
+	return created, rows.Err()
+}
+
+// RotateInvitationToken mengganti token undangan yang belum diisi, misalnya saat email dikirim ulang
+func (r *tracerRepository) RotateInvitationToken(ctx context.Context, questionnaireID, invitationID int, tokenHash string) (*domain.QuestionnaireInvitation, error) {
+	query := `
+		WITH i AS (
+			UPDATE questionnaire_invitations
+			SET token_hash = CASE WHEN submitted_at IS NULL THEN $3 ELSE token_hash END
+			WHERE id = $2 AND questionnaire_id = $1
+			RETURNING *
+		)
+		SELECT ` + invitationColumns + ` FROM i JOIN alumni a ON a.id = i.alumni_id`
+	return scanInvitation(r.db.QueryRow(ctx, query, questionnaireID, invitationID, tokenHash))
+}
+
+func (r *tracerRepository) MarkInvitationSent(ctx context.Context, invitationID int) error {
+	_, err := r.db.Exec(ctx, `UPDATE questionnaire_invitations SET sent_at = NOW() WHERE id = $1`, invitationID)
+	return err
+}
+
+const invitationColumns = `i.id, i.questionnaire_id, i.alumni_id, a.nim, a.nama, a.email, i.sent_at, i.submitted_at, i.created_at`
+
+func scanInvitation(row pgx.Row) (*domain.QuestionnaireInvitation, error) {
+	var inv domain.QuestionnaireInvitation
+	err := row.Scan(&inv.ID, &inv.QuestionnaireID, &inv.AlumniID, &inv.AlumniNIM, &inv.AlumniNama, &inv.Email, &inv.SentAt, &inv.SubmittedAt, &inv.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *tracerRepository) FindInvitationByToken(ctx context.Context, tokenHash string) (*domain.QuestionnaireInvitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM questionnaire_invitations i JOIN alumni a ON a.id = i.alumni_id WHERE i.token_hash = $1`
+	return scanInvitation(r.db.QueryRow(ctx, query, tokenHash))
+}
+
+// FindInvitations memfilter status "pending" (belum mengisi) atau "submitted"; kosong berarti semua
+func (r *tracerRepository) FindInvitations(ctx context.Context, questionnaireID int, status string, page, limit int) (*domain.PaginationResult[domain.QuestionnaireInvitation], error) {
+	whereSQL := ` WHERE i.questionnaire_id = $1`
+	switch status {
+	case "pending":
+		whereSQL += ` AND i.submitted_at IS NULL`
+	case "submitted":
+		whereSQL += ` AND i.submitted_at IS NOT NULL`
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(i.id) FROM questionnaire_invitations i`+whereSQL, questionnaireID).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + invitationColumns + ` FROM questionnaire_invitations i JOIN alumni a ON a.id = i.alumni_id` +
+		whereSQL + ` ORDER BY a.nim, i.id LIMIT $2 OFFSET $3`
+	rows, err := r.db.Query(ctx, query, questionnaireID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []domain.QuestionnaireInvitation{}
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *inv)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return paginationResult(invitations, total, page, limit), nil
+}
+
+// CreateSubmission menyimpan jawaban dan menandai undangan sudah diisi dalam satu transaksi.
+// Constraint (alumni_id, period) memastikan satu pengisian per alumni per periode walaupun
+// dua request dikirim bersamaan.
+func (r *tracerRepository) CreateSubmission(ctx context.Context, invitation *domain.QuestionnaireInvitation, version *domain.QuestionnaireVersion, period string, answers map[string]interface{}) (*domain.QuestionnaireSubmission, error) {
+	raw, err := json.Marshal(answers)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	sub := &domain.QuestionnaireSubmission{
+		QuestionnaireID: invitation.QuestionnaireID,
+		Version:         version.Version,
+		AlumniID:        invitation.AlumniID,
+		AlumniNIM:       invitation.AlumniNIM,
+		AlumniNama:      invitation.AlumniNama,
+		Period:          period,
+		Answers:         raw,
+	}
+	query := `
+		INSERT INTO questionnaire_submissions (questionnaire_id, version_id, invitation_id, alumni_id, period, answers)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (alumni_id, period) DO NOTHING
+		RETURNING id, submitted_at`
+	err = tx.QueryRow(ctx, query, invitation.QuestionnaireID, version.ID, invitation.ID, invitation.AlumniID, period, raw).Scan(&sub.ID, &sub.SubmittedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrAlreadySubmitted
+		}
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE questionnaire_invitations SET submitted_at = $2 WHERE id = $1`, invitation.ID, sub.SubmittedAt); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// HasSubmittedPeriod bernilai true jika alumni sudah mengisi kuesioner mana pun pada periode tersebut
+func (r *tracerRepository) HasSubmittedPeriod(ctx context.Context, alumniID int, period string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM questionnaire_submissions WHERE alumni_id = $1 AND period = $2)`
+	err := r.db.QueryRow(ctx, query, alumniID, period).Scan(&exists)
+	return exists, err
+}
+
+// HasSubmissions bernilai true jika kuesioner sudah punya jawaban masuk
+func (r *tracerRepository) HasSubmissions(ctx context.Context, questionnaireID int) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM questionnaire_submissions WHERE questionnaire_id = $1)`
+	err := r.db.QueryRow(ctx, query, questionnaireID).Scan(&exists)
+	return exists, err
+}
+
+func (r *tracerRepository) FindSubmissions(ctx context.Context, questionnaireID, page, limit int) (*domain.PaginationResult[domain.QuestionnaireSubmission], error) {
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(id) FROM questionnaire_submissions WHERE questionnaire_id = $1`, questionnaireID).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT s.id, s.questionnaire_id, v.version, s.alumni_id, a.nim, a.nama, a.jurusan, a.tahun_lulus, s.period, s.answers, s.submitted_at
+		FROM questionnaire_submissions s
+		JOIN questionnaire_versions v ON v.id = s.version_id
+		JOIN alumni a ON a.id = s.alumni_id
+		WHERE s.questionnaire_id = $1
+		ORDER BY s.submitted_at, s.id
+		LIMIT $2 OFFSET $3`
+	rows, err := r.db.Query(ctx, query, questionnaireID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	submissions := []domain.QuestionnaireSubmission{}
+	for rows.Next() {
+		var s domain.QuestionnaireSubmission
+		if err := rows.Scan(&s.ID, &s.QuestionnaireID, &s.Version, &s.AlumniID, &s.AlumniNIM, &s.AlumniNama, &s.Jurusan, &s.TahunLulus, &s.Period, &s.Answers, &s.SubmittedAt); err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, s)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return paginationResult(submissions, total, page, limit), nil
+}
+
+// ResponseRates menghitung jumlah undangan dan pengisian per kelompok alumni (jurusan atau tahun_lulus)
+func (r *tracerRepository) ResponseRates(ctx context.Context, questionnaireID int, groupBy string) ([]domain.ResponseRate, error) {
+	column, ok := map[string]string{"jurusan": "a.jurusan", "tahun_lulus": "a.tahun_lulus::text"}[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown response rate group %q", groupBy)
+	}
+
+	query := `
+		SELECT ` + column + `, COUNT(i.id), COUNT(i.submitted_at)
+		FROM questionnaire_invitations i
+		JOIN alumni a ON a.id = i.alumni_id
+		WHERE i.questionnaire_id = $1
+		GROUP BY 1
+		ORDER BY 1`
+	rows, err := r.db.Query(ctx, query, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []domain.ResponseRate{}
+	for rows.Next() {
+		var rate domain.ResponseRate
+		if err := rows.Scan(&rate.Group, &rate.Invited, &rate.Submitted); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func paginationResult[T any](data []T, total int64, page, limit int) *domain.PaginationResult[T] {
+	lastPage := int(math.Ceil(float64(total) / float64(limit)))
+	if lastPage < 1 && total > 0 {
+		lastPage = 1
+	}
+	return &domain.PaginationResult[T]{
+		Data:     data,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+		LastPage: lastPage,
+	}
+}
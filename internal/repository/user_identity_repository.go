@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"back-train/internal/domain"
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type userIdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserIdentityRepository(db *pgxpool.Pool) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) FindUserID(ctx context.Context, provider, subject string) (int, error) {
+	var userID int
+	query := `SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2`
+	err := r.db.QueryRow(ctx, query, provider, subject).Scan(&userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, domain.ErrUserNotFound
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (r *userIdentityRepository) Link(ctx context.Context, userID int, provider, subject, email string) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (provider, subject) DO UPDATE SET email = EXCLUDED.email, last_login_at = NOW()`
+	_, err := r.db.Exec(ctx, query, userID, provider, subject, email)
+	return err
+}
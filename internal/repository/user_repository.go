@@ -4,7 +4,11 @@ import (
 	"back-train/internal/domain"
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strings"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
@@ -17,6 +21,34 @@ func NewUserRepository(db *pgxpool.Pool) UserRepository {
 	return &userRepository{db: db}
 }
 
+// userSelectSQL memilih user beserta daftar role dan permission efektifnya; tambahkan WHERE lalu GROUP BY u.id
+const userSelectSQL = `
+		SELECT u.id, u.email, u.password_hash, u.is_active, u.email_verified_at, u.created_at, u.updated_at,
+			EXISTS (SELECT 1 FROM user_mfa m WHERE m.user_id = u.id AND m.enabled_at IS NOT NULL) as mfa_enabled,
+			COALESCE(array_agg(r.name) FILTER (WHERE r.name IS NOT NULL), '{}') as roles,
+			COALESCE((
+				SELECT array_agg(DISTINCT p.name)
+				FROM user_roles ur2
+				JOIN role_permissions rp ON rp.role_id = ur2.role_id
+				JOIN permissions p ON p.id = rp.permission_id
+				WHERE ur2.user_id = u.id
+			), '{}') as permissions
+		FROM users u
+		LEFT JOIN user_roles ur ON u.id = ur.user_id
+		LEFT JOIN roles r ON ur.role_id = r.id`
+
+func scanUser(row pgx.Row) (*domain.User, error) {
+	user := &domain.User{}
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.IsActive, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt, &user.MFAEnabled, &user.Roles, &user.Permissions)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
 func (r *userRepository) CreateUser(ctx context.Context, user *domain.User, roleName string) (*domain.User, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
@@ -25,9 +57,12 @@ func (r *userRepository) CreateUser(ctx context.Context, user *domain.User, role
 	defer tx.Rollback(ctx)
 
 	// Insert user
-	userSQL := `INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, created_at, updated_at`
-	err = tx.QueryRow(ctx, userSQL, user.Email, user.PasswordHash).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	userSQL := `INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, is_active, created_at, updated_at`
+	err = tx.QueryRow(ctx, userSQL, user.Email, user.PasswordHash).Scan(&user.ID, &user.IsActive, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrEmailTaken
+		}
 		return nil, err
 	}
 
@@ -37,7 +72,7 @@ func (r *userRepository) CreateUser(ctx context.Context, user *domain.User, role
 	err = tx.QueryRow(ctx, roleSQL, roleName).Scan(&roleID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.New("role not found")
+			return nil, domain.ErrRoleNotFound
 		}
 		return nil, err
 	}
@@ -56,40 +91,311 @@ func (r *userRepository) CreateUser(ctx context.Context, user *domain.User, role
 	return user, nil
 }
 
+// isUniqueViolation mengenali pelanggaran constraint UNIQUE dari Postgres (SQLSTATE 23505)
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
 func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
-	user := &domain.User{}
-	query := `
-		SELECT u.id, u.email, u.password_hash, u.created_at, u.updated_at, array_agg(r.name) as roles
-		FROM users u
-		LEFT JOIN user_roles ur ON u.id = ur.user_id
-		LEFT JOIN roles r ON ur.role_id = r.id
-		WHERE u.email = $1
-		GROUP BY u.id`
-	err := r.db.QueryRow(ctx, query, email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt, &user.Roles)
+	query := userSelectSQL + ` WHERE lower(u.email) = lower($1) GROUP BY u.id`
+	return scanUser(r.db.QueryRow(ctx, query, email))
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
+	query := userSelectSQL + ` WHERE u.id = $1 GROUP BY u.id`
+	return scanUser(r.db.QueryRow(ctx, query, id))
+}
+
+func (r *userRepository) FindAll(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.User], error) {
+	var args []interface{}
+	var whereClauses []string
+	argID := 1
+
+	baseQuery := userSelectSQL
+	countQuery := `SELECT COUNT(u.id) FROM users u`
+
+	if params.Search != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("u.email ILIKE $%d", argID))
+		args = append(args, "%"+params.Search+"%")
+		argID++
+	}
+
+	if len(whereClauses) > 0 {
+		whereSQL := " WHERE " + strings.Join(whereClauses, " AND ")
+		baseQuery += whereSQL
+		countQuery += whereSQL
+	}
+	baseQuery += " GROUP BY u.id"
+
+	// Get total count
+	var total int64
+	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, errors.New("user not found")
+		return nil, err
+	}
+
+	// Sorting
+	validSortColumns := map[string]string{
+		"id":         "u.id",
+		"email":      "u.email",
+		"created_at": "u.created_at",
+	}
+	sortColumn := "u.created_at" // default sort
+	sortOrder := "DESC"
+
+	if params.Sort != "" {
+		parts := strings.Split(params.Sort, ":")
+		col := strings.ToLower(parts[0])
+		if mappedCol, ok := validSortColumns[col]; ok {
+			sortColumn = mappedCol
+		}
+		if len(parts) > 1 && strings.ToUpper(parts[1]) == "ASC" {
+			sortOrder = "ASC"
 		}
+	}
+	baseQuery += fmt.Sprintf(" ORDER BY %s %s", sortColumn, sortOrder)
+
+	// Pagination
+	offset := (params.Page - 1) * params.Limit
+	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argID, argID+1)
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.Query(ctx, baseQuery, args...)
+	if err != nil {
 		return nil, err
 	}
-	return user, nil
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	lastPage := int(math.Ceil(float64(total) / float64(params.Limit)))
+	if lastPage < 1 && total > 0 {
+		lastPage = 1
+	}
+
+	return &domain.PaginationResult[domain.User]{
+		Data:     users,
+		Total:    total,
+		Page:     params.Page,
+		Limit:    params.Limit,
+		LastPage: lastPage,
+	}, nil
 }
 
-func (r *userRepository) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
-	user := &domain.User{}
+func (r *userRepository) SetRoles(ctx context.Context, userID int, roleNames []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := touchUser(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	if !containsString(roleNames, "admin") {
+		if err := ensureOtherAdminExists(ctx, tx, userID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_roles WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	insertSQL := `INSERT INTO user_roles (user_id, role_id) SELECT $1, id FROM roles WHERE name = ANY($2)`
+	cmdTag, err := tx.Exec(ctx, insertSQL, userID, roleNames)
+	if err != nil {
+		return err
+	}
+	if int(cmdTag.RowsAffected()) != len(roleNames) {
+		return domain.ErrRoleNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *userRepository) SetActive(ctx context.Context, userID int, active bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if !active {
+		if err := ensureOtherAdminExists(ctx, tx, userID); err != nil {
+			return err
+		}
+	}
+
+	cmdTag, err := tx.Exec(ctx, `UPDATE users SET is_active = $1, updated_at = NOW() WHERE id = $2`, active, userID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() != 1 {
+		return domain.ErrUserNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *userRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := ensureOtherAdminExists(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_roles WHERE user_id = $1`, id); err != nil {
+		return err
+	}
+
+	cmdTag, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() != 1 {
+		return domain.ErrUserNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id int) error {
+	query := `UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW() WHERE id = $1`
+	cmdTag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() != 1 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`
+	cmdTag, err := r.db.Exec(ctx, query, passwordHash, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() != 1 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) UpdateEmail(ctx context.Context, id int, email string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var taken bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE lower(email) = lower($1) AND id <> $2)`, email, id).Scan(&taken)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return domain.ErrEmailTaken
+	}
+
+	// Email baru sudah dibuktikan lewat link konfirmasi, jadi langsung dianggap terverifikasi
+	query := `UPDATE users SET email = $1, email_verified_at = NOW(), updated_at = NOW() WHERE id = $2`
+	cmdTag, err := tx.Exec(ctx, query, email, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrEmailTaken
+		}
+		return err
+	}
+	if cmdTag.RowsAffected() != 1 {
+		return domain.ErrUserNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *userRepository) UpgradePasswordHash(ctx context.Context, id int, oldHash, newHash string) error {
+	// updated_at sengaja tidak diubah karena password user tidak berubah
+	query := `UPDATE users SET password_hash = $1 WHERE id = $2 AND password_hash = $3`
+	_, err := r.db.Exec(ctx, query, newHash, id, oldHash)
+	return err
+}
+
+// touchUser memperbarui updated_at sekaligus memastikan user ada
+func touchUser(ctx context.Context, tx pgx.Tx, userID int) error {
+	cmdTag, err := tx.Exec(ctx, `UPDATE users SET updated_at = NOW() WHERE id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() != 1 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// ensureOtherAdminExists mengunci semua admin aktif lalu menolak perubahan jika userID
+// adalah satu-satunya admin aktif yang tersisa.
+func ensureOtherAdminExists(ctx context.Context, tx pgx.Tx, userID int) error {
 	query := `
-		SELECT u.id, u.email, u.password_hash, u.created_at, u.updated_at, array_agg(r.name) as roles
+		SELECT u.id
 		FROM users u
-		LEFT JOIN user_roles ur ON u.id = ur.user_id
-		LEFT JOIN roles r ON ur.role_id = r.id
-		WHERE u.id = $1
-		GROUP BY u.id`
-	err := r.db.QueryRow(ctx, query, id).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt, &user.Roles)
+		JOIN user_roles ur ON u.id = ur.user_id
+		JOIN roles r ON ur.role_id = r.id
+		WHERE r.name = 'admin' AND u.is_active
+		FOR UPDATE OF u`
+	rows, err := tx.Query(ctx, query)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, errors.New("user not found")
+		return err
+	}
+	defer rows.Close()
+
+	isAdmin := false
+	otherAdmins := 0
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		if id == userID {
+			isAdmin = true
+		} else {
+			otherAdmins++
 		}
-		return nil, err
 	}
-	return user, nil
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	if isAdmin && otherAdmins == 0 {
+		return domain.ErrLastAdmin
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
 }
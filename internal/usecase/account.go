@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/mailer"
+	"back-train/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// emailChangePayload adalah isi link konfirmasi ganti email. Email lama ikut ditandatangani agar
+// link otomatis tidak berlaku jika email sudah diganti lewat link lain.
+type emailChangePayload struct {
+	UserID    int    `json:"uid"`
+	OldEmail  string `json:"old"`
+	NewEmail  string `json:"new"`
+	SessionID string `json:"sid"` // session yang meminta perubahan, tidak ikut dicabut
+	ExpiresAt int64  `json:"exp"`
+}
+
+// emailChangeKey diturunkan dari secret JWT tetapi berbeda dari kunci verifikasi email biasa
+func (u *authUsecase) emailChangeKey() []byte {
+	return []byte("email-change:" + u.opts.JWTSecret)
+}
+
+// verifyCurrentPassword memakai penghitung lockout yang sama dengan login, agar session yang
+// dicuri tidak bisa dipakai untuk menebak password tanpa batas
+func (u *authUsecase) verifyCurrentPassword(ctx context.Context, userID int, password string, client domain.ClientInfo) (*domain.User, error) {
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkLockout(ctx, user.Email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	// Akun SSO/LDAP tanpa password lokal juga ditolak di sini; mereka bisa memakai forgot-password
+	ok, err := u.opts.PasswordHasher.Verify(password, user.PasswordHash)
+	if err != nil || !ok {
+		u.registerLoginFailure(ctx, &user.ID, user.Email, client.IPAddress)
+		return nil, domain.ErrWrongPassword
+	}
+	u.clearLoginFailures(ctx, user.Email)
+	return user, nil
+}
+
+func (u *authUsecase) ChangePassword(ctx context.Context, userID int, currentSessionID string, req *domain.ChangePasswordRequest, client domain.ClientInfo) error {
+	user, err := u.verifyCurrentPassword(ctx, userID, req.CurrentPassword, client)
+	if err != nil {
+		return err
+	}
+	if err := u.checkPassword(req.NewPassword, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := u.opts.PasswordHasher.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := u.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	// Perangkat lain mungkin login dengan password lama yang bocor, jadi dikeluarkan semua
+	if err := u.sessionRepo.RevokeOthersForUser(ctx, user.ID, currentSessionID, "password_changed"); err != nil {
+		return err
+	}
+	u.recordAuthEvent(ctx, &domain.AuthEvent{
+		EventType: "password_changed",
+		UserID:    &user.ID,
+		Email:     &user.Email,
+		IPAddress: optionalString(client.IPAddress),
+	})
+
+	msg := mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Password Anda telah diganti",
+		Body: "Password akun Anda baru saja diganti dan semua perangkat lain telah dikeluarkan.\n\n" +
+			"Jika bukan Anda yang menggantinya, segera reset password melalui halaman lupa password.",
+	}
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		log.Printf("failed to send password change notification to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+func (u *authUsecase) RequestEmailChange(ctx context.Context, userID int, currentSessionID string, req *domain.ChangeEmailRequest, client domain.ClientInfo) error {
+	newEmail, err := normalizeEmail(req.NewEmail)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.verifyCurrentPassword(ctx, userID, req.CurrentPassword, client)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return domain.ErrEmailUnchanged
+	}
+
+	// Dicek lagi saat konfirmasi; di sini hanya agar user tidak menunggu email yang pasti gagal
+	if _, err := u.userRepo.GetUserByEmail(ctx, newEmail); err == nil {
+		return domain.ErrEmailTaken
+	} else if !errors.Is(err, domain.ErrUserNotFound) {
+		return err
+	}
+
+	payload, err := json.Marshal(emailChangePayload{
+		UserID:    user.ID,
+		OldEmail:  user.Email,
+		NewEmail:  newEmail,
+		SessionID: currentSessionID,
+		ExpiresAt: time.Now().Add(u.opts.EmailVerificationTTL).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	token := utils.SignToken(u.emailChangeKey(), payload)
+
+	confirm := mailer.Message{
+		To:      []string{newEmail},
+		Subject: "Konfirmasi perubahan email",
+		Body: fmt.Sprintf("Ada permintaan untuk memakai alamat ini sebagai email akun Anda.\n\n"+
+			"Buka link berikut untuk mengonfirmasi (berlaku %d jam):\n%s/confirm-email-change?token=%s",
+			int(u.opts.EmailVerificationTTL.Hours()), u.opts.AppBaseURL, url.QueryEscape(token)),
+	}
+	if err := u.mailer.Send(ctx, confirm); err != nil {
+		return err
+	}
+
+	notice := mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Permintaan perubahan email",
+		Body: fmt.Sprintf("Ada permintaan untuk mengganti email akun Anda menjadi %s. Email baru berlaku "+
+			"setelah link konfirmasi yang dikirim ke alamat tersebut dibuka.\n\n"+
+			"Jika bukan Anda yang memintanya, segera ganti password Anda.", newEmail),
+	}
+	if err := u.mailer.Send(ctx, notice); err != nil {
+		log.Printf("failed to send email change notice to user %d: %v", user.ID, err)
+	}
+
+	detail := "new email: " + newEmail
+	u.recordAuthEvent(ctx, &domain.AuthEvent{
+		EventType: "email_change_requested",
+		UserID:    &user.ID,
+		Email:     &user.Email,
+		IPAddress: optionalString(client.IPAddress),
+		Detail:    &detail,
+	})
+	return nil
+}
+
+func (u *authUsecase) ConfirmEmailChange(ctx context.Context, token string) error {
+	raw, ok := utils.VerifySignedToken(u.emailChangeKey(), token)
+	if !ok {
+		return domain.ErrInvalidEmailChange
+	}
+
+	var payload emailChangePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return domain.ErrInvalidEmailChange
+	}
+	if time.Now().Unix() > payload.ExpiresAt {
+		return domain.ErrInvalidEmailChange
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.ErrInvalidEmailChange
+		}
+		return err
+	}
+	if user.Email != payload.OldEmail {
+		return domain.ErrInvalidEmailChange
+	}
+
+	if err := u.userRepo.UpdateEmail(ctx, user.ID, payload.NewEmail); err != nil {
+		return err
+	}
+	if err := u.sessionRepo.RevokeOthersForUser(ctx, user.ID, payload.SessionID, "email_changed"); err != nil {
+		return err
+	}
+
+	detail := fmt.Sprintf("%s -> %s", payload.OldEmail, payload.NewEmail)
+	u.recordAuthEvent(ctx, &domain.AuthEvent{
+		EventType: "email_changed",
+		UserID:    &user.ID,
+		Email:     &payload.NewEmail,
+		Detail:    &detail,
+	})
+
+	msg := mailer.Message{
+		To:      []string{payload.OldEmail},
+		Subject: "Email akun Anda telah diganti",
+		Body: fmt.Sprintf("Email akun Anda telah diganti menjadi %s dan alamat ini tidak lagi bisa dipakai untuk login.\n\n"+
+			"Jika bukan Anda yang menggantinya, segera hubungi administrator.", payload.NewEmail),
+	}
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		log.Printf("failed to send email changed notice to user %d: %v", user.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/spreadsheet"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AlumniImportOptions mengatur batas dan validasi import alumni
+type AlumniImportOptions struct {
+	MaxRows   int
+	NIMFormat *regexp.Regexp
+}
+
+// tahunMinimum adalah batas bawah angkatan dan tahun lulus yang masuk akal
+const tahunMinimum = 1950
+
+var importRequiredColumns = []string{"nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email"}
+
+// importColumnAliases memetakan nama kolom yang sering dipakai di spreadsheet ke field CreateAlumniRequest
+var importColumnAliases = map[string]string{
+	"no_hp":       "no_telepon",
+	"telepon":     "no_telepon",
+	"tahun_masuk": "angkatan",
+}
+
+// importColumnIndex memetakan header (tidak peka huruf besar dan spasi) ke indeks kolom
+func importColumnIndex(header []string) (map[string]int, error) {
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.NewReplacer(" ", "_", "-", "_", ".", "").Replace(name)
+		if alias, ok := importColumnAliases[name]; ok {
+			name = alias
+		}
+		if _, dup := columns[name]; !dup {
+			columns[name] = i
+		}
+	}
+
+	var missing []string
+	for _, name := range importRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrImportMissingColumn, strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+type importRow struct {
+	line   int
+	alumni *domain.Alumni
+	errors []string
+}
+
+// parseImportRow mengisi alumni dari satu baris dan mengumpulkan semua kesalahan field sekaligus
+func (u *alumniUsecase) parseImportRow(line int, record []string, columns map[string]int) *importRow {
+	row := &importRow{line: line, alumni: &domain.Alumni{}}
+	cell := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	fail := func(field, msg string) {
+		row.errors = append(row.errors, field+": "+msg)
+	}
+	text := func(field string, maxLen int) string {
+		v := cell(field)
+		if v == "" {
+			fail(field, "is required")
+		} else if len(v) > maxLen {
+			fail(field, fmt.Sprintf("must be at most %d characters", maxLen))
+		}
+		return v
+	}
+	optional := func(field string, maxLen int) *string {
+		v := cell(field)
+		if v == "" {
+			return nil
+		}
+		if maxLen > 0 && len(v) > maxLen {
+			fail(field, fmt.Sprintf("must be at most %d characters", maxLen))
+		}
+		return &v
+	}
+	year := func(field string) int {
+		v := cell(field)
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			fail(field, "must be a year")
+			return 0
+		}
+		if n < tahunMinimum || n > time.Now().Year() {
+			fail(field, fmt.Sprintf("must be between %d and %d", tahunMinimum, time.Now().Year()))
+		}
+		return n
+	}
+
+	a := row.alumni
+	a.NIM = cell("nim")
+	if a.NIM == "" {
+		fail("nim", "is required")
+	} else if len(a.NIM) > 20 {
+		fail("nim", "must be at most 20 characters")
+	} else if u.importOpts.NIMFormat != nil && !u.importOpts.NIMFormat.MatchString(a.NIM) {
+		fail("nim", "invalid format")
+	}
+	a.Nama = text("nama", 100)
+	a.Jurusan = text("jurusan", 100)
+	a.Angkatan = year("angkatan")
+	a.TahunLulus = year("tahun_lulus")
+	if a.Angkatan > 0 && a.TahunLulus > 0 && a.TahunLulus < a.Angkatan {
+		fail("tahun_lulus", "must not be before angkatan")
+	}
+	if email, err := normalizeEmail(cell("email")); err != nil {
+		fail("email", "invalid email address")
+	} else {
+		a.Email = email
+	}
+	a.NoTelepon = optional("no_telepon", 20)
+	a.Alamat = optional("alamat", 0)
+	return row
+}
+
+// ImportAlumni memvalidasi semua baris terlebih dahulu, lalu (jika bukan dry run) menyimpan
+// baris yang valid dalam satu transaksi. Baris yang tidak valid dilaporkan dan dilewati.
+func (u *alumniUsecase) ImportAlumni(ctx context.Context, filename string, r io.Reader, dryRun bool, meta domain.AuditMeta) (*domain.ImportReport, error) {
+	format, err := spreadsheet.FormatFromFilename(filename)
+	if err != nil {
+		return nil, err
+	}
+	records, err := spreadsheet.ReadAll(format, r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrImportUnreadable, err)
+	}
+	if len(records) < 2 {
+		return nil, domain.ErrImportEmpty
+	}
+	columns, err := importColumnIndex(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*importRow
+	for i, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		rows = append(rows, u.parseImportRow(i+2, record, columns))
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrImportEmpty
+	}
+	if u.importOpts.MaxRows > 0 && len(rows) > u.importOpts.MaxRows {
+		return nil, fmt.Errorf("%w: %d rows, maximum is %d", domain.ErrImportTooManyRows, len(rows), u.importOpts.MaxRows)
+	}
+	reportJobProgress(ctx, 0, len(rows))
+
+	// Duplikat di dalam file: baris yang muncul lebih dulu dianggap benar
+	nimRows := map[string]int{}
+	emailRows := map[string]int{}
+	var nims, emails []string
+	for _, row := range rows {
+		a := row.alumni
+		if first, ok := nimRows[a.NIM]; ok && a.NIM != "" {
+			row.errors = append(row.errors, fmt.Sprintf("nim: duplicate of row %d", first))
+		} else if a.NIM != "" {
+			nimRows[a.NIM] = row.line
+			nims = append(nims, a.NIM)
+		}
+		email := strings.ToLower(a.Email)
+		if first, ok := emailRows[email]; ok && email != "" {
+			row.errors = append(row.errors, fmt.Sprintf("email: duplicate of row %d", first))
+		} else if email != "" {
+			emailRows[email] = row.line
+			emails = append(emails, email)
+		}
+	}
+
+	existingNIMs, existingEmails, err := u.alumniRepo.FindExisting(ctx, nims, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ImportReport{DryRun: dryRun, TotalRows: len(rows), Errors: []domain.ImportRowError{}}
+	var valid []*domain.Alumni
+	for _, row := range rows {
+		if existingNIMs[row.alumni.NIM] {
+			row.errors = append(row.errors, "nim: already exists")
+		}
+		if existingEmails[strings.ToLower(row.alumni.Email)] {
+			row.errors = append(row.errors, "email: already used by another alumni")
+		}
+		if len(row.errors) > 0 {
+			report.Errors = append(report.Errors, domain.ImportRowError{Row: row.line, NIM: row.alumni.NIM, Errors: row.errors})
+			continue
+		}
+		valid = append(valid, row.alumni)
+	}
+	report.ValidRows = len(valid)
+	report.InvalidRows = len(report.Errors)
+
+	if dryRun || len(valid) == 0 {
+		reportJobProgress(ctx, len(rows), len(rows))
+		return report, nil
+	}
+	if err := u.alumniRepo.CreateMany(ctx, valid, meta); err != nil {
+		return nil, err
+	}
+	report.Imported = len(valid)
+	reportJobProgress(ctx, len(rows), len(rows))
+	return report, nil
+}
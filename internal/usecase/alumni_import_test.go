@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportColumnIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  []string
+		want    map[string]int // hanya kolom yang dicek
+		wantErr string         // kolom yang hilang; kosong berarti tidak error
+	}{
+		{
+			name:   "exact names",
+			header: []string{"nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email"},
+			want:   map[string]int{"nim": 0, "angkatan": 3, "tahun_lulus": 4, "email": 5},
+		},
+		{
+			name:   "case, spaces and punctuation normalized",
+			header: []string{" NIM ", "Nama", "JURUSAN", "Angkatan", "Tahun-Lulus", "EMAIL", "No. Telepon"},
+			want:   map[string]int{"nim": 0, "tahun_lulus": 4, "email": 5, "no_telepon": 6},
+		},
+		{
+			name:   "aliases",
+			header: []string{"nim", "nama", "jurusan", "Tahun Masuk", "tahun_lulus", "email", "No HP"},
+			want:   map[string]int{"angkatan": 3, "no_telepon": 6},
+		},
+		{
+			name:   "telepon alias",
+			header: []string{"nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email", "telepon"},
+			want:   map[string]int{"no_telepon": 6},
+		},
+		{
+			name:   "first duplicate column wins",
+			header: []string{"nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email", "no_hp", "no_telepon"},
+			want:   map[string]int{"no_telepon": 6},
+		},
+		{
+			name:    "missing columns listed in order",
+			header:  []string{"nama", "email", "jurusan"},
+			wantErr: "nim, angkatan, tahun_lulus",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := importColumnIndex(tt.header)
+			if tt.wantErr != "" {
+				if !errors.Is(err, domain.ErrImportMissingColumn) || !strings.HasSuffix(err.Error(), ": "+tt.wantErr) {
+					t.Fatalf("err = %v, want missing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, i := range tt.want {
+				if got[name] != i {
+					t.Errorf("columns[%q] = %d, want %d", name, got[name], i)
+				}
+			}
+		})
+	}
+}
+
+func TestParseImportRow(t *testing.T) {
+	columns, err := importColumnIndex([]string{"nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email", "no_telepon", "alamat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nextYear := strconv.Itoa(time.Now().Year() + 1)
+	u := &alumniUsecase{importOpts: AlumniImportOptions{NIMFormat: regexp.MustCompile(`^[0-9]{8,12}$`)}}
+
+	tests := []struct {
+		name   string
+		record []string
+		want   []string
+	}{
+		{"valid row", []string{"12345678", "Budi", "TI", "2018", "2022", "Budi@Example.com", "0812", "Jl. Mawar"}, nil},
+		{"short record", []string{"12345678", "Budi", "TI", "2018", "2022", "budi@example.com"}, nil},
+		{"nim required", []string{"", "Budi", "TI", "2018", "2022", "budi@example.com"}, []string{"nim: is required"}},
+		{"nim too long", []string{strings.Repeat("1", 21), "Budi", "TI", "2018", "2022", "budi@example.com"}, []string{"nim: must be at most 20 characters"}},
+		{"nim format", []string{"A1234567", "Budi", "TI", "2018", "2022", "budi@example.com"}, []string{"nim: invalid format"}},
+		{"nama and jurusan required", []string{"12345678", " ", "", "2018", "2022", "budi@example.com"}, []string{"nama: is required", "jurusan: is required"}},
+		{"nama too long", []string{"12345678", strings.Repeat("a", 101), "TI", "2018", "2022", "budi@example.com"}, []string{"nama: must be at most 100 characters"}},
+		{"angkatan not a year", []string{"12345678", "Budi", "TI", "dua ribu", "2022", "budi@example.com"}, []string{"angkatan: must be a year"}},
+		{"angkatan before minimum", []string{"12345678", "Budi", "TI", "1949", "2022", "budi@example.com"}, []string{"angkatan: must be between 1950 and " + strconv.Itoa(time.Now().Year())}},
+		{"angkatan at minimum", []string{"12345678", "Budi", "TI", "1950", "1954", "budi@example.com"}, nil},
+		{"tahun_lulus in the future", []string{"12345678", "Budi", "TI", "2018", nextYear, "budi@example.com"}, []string{"tahun_lulus: must be between 1950 and " + strconv.Itoa(time.Now().Year())}},
+		{"tahun_lulus before angkatan", []string{"12345678", "Budi", "TI", "2020", "2019", "budi@example.com"}, []string{"tahun_lulus: must not be before angkatan"}},
+		{"invalid email", []string{"12345678", "Budi", "TI", "2018", "2022", "budi"}, []string{"email: invalid email address"}},
+		{"no_telepon too long", []string{"12345678", "Budi", "TI", "2018", "2022", "budi@example.com", strings.Repeat("0", 21)}, []string{"no_telepon: must be at most 20 characters"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := u.parseImportRow(2, tt.record, columns)
+			if !reflect.DeepEqual(row.errors, tt.want) {
+				t.Fatalf("errors = %q, want %q", row.errors, tt.want)
+			}
+		})
+	}
+
+	row := u.parseImportRow(2, tests[0].record, columns)
+	if a := row.alumni; a.Email != "budi@example.com" || a.Angkatan != 2018 || a.NoTelepon == nil || *a.NoTelepon != "0812" {
+		t.Errorf("alumni = %+v", a)
+	}
+}
+
+func TestImportAlumni(t *testing.T) {
+	const header = "nim,nama,jurusan,tahun_masuk,tahun_lulus,email\n"
+	tests := []struct {
+		name       string
+		csv        string
+		dryRun     bool
+		maxRows    int
+		wantErr    error
+		wantErrors []domain.ImportRowError
+		wantValid  int
+		imported   int
+	}{
+		{
+			name:      "all rows valid",
+			csv:       header + "1001,Budi,TI,2018,2022,budi@example.com\n1002,Ani,SI,2019,2023,ani@example.com\n",
+			wantValid: 2,
+			imported:  2,
+		},
+		{
+			name:      "dry run saves nothing",
+			csv:       header + "1001,Budi,TI,2018,2022,budi@example.com\n",
+			dryRun:    true,
+			wantValid: 1,
+		},
+		{
+			name: "duplicates within the file",
+			csv: header + "1001,Budi,TI,2018,2022,budi@example.com\n" +
+				"1001,Budi Lagi,TI,2018,2022,lain@example.com\n" +
+				"1003,Cici,TI,2018,2022,BUDI@example.com\n",
+			wantErrors: []domain.ImportRowError{
+				{Row: 3, NIM: "1001", Errors: []string{"nim: duplicate of row 2"}},
+				{Row: 4, NIM: "1003", Errors: []string{"email: duplicate of row 2"}},
+			},
+			wantValid: 1,
+			imported:  1,
+		},
+		{
+			name: "duplicates of existing alumni",
+			csv: header + "9000,Lama,TI,2018,2022,baru@example.com\n" +
+				"1002,Ani,SI,2019,2023,Lama@Example.com\n",
+			wantErrors: []domain.ImportRowError{
+				{Row: 2, NIM: "9000", Errors: []string{"nim: already exists"}},
+				{Row: 3, NIM: "1002", Errors: []string{"email: already used by another alumni"}},
+			},
+		},
+		{
+			name: "blank rows skipped and line numbers kept",
+			csv:  header + ",,,,,\n1001,Budi,TI,2020,2019,budi@example.com\n",
+			wantErrors: []domain.ImportRowError{
+				{Row: 3, NIM: "1001", Errors: []string{"tahun_lulus: must not be before angkatan"}},
+			},
+		},
+		{name: "header only", csv: header, wantErr: domain.ErrImportEmpty},
+		{name: "only blank rows", csv: header + ",,,,,\n", wantErr: domain.ErrImportEmpty},
+		{name: "missing column", csv: "nim,nama\n1001,Budi\n", wantErr: domain.ErrImportMissingColumn},
+		{
+			name:    "too many rows",
+			csv:     header + "1001,Budi,TI,2018,2022,budi@example.com\n1002,Ani,SI,2019,2023,ani@example.com\n",
+			maxRows: 1,
+			wantErr: domain.ErrImportTooManyRows,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ar := newFakeAlumniRepo(&domain.Alumni{ID: 1, NIM: "9000", Nama: "Lama", Email: "lama@example.com"})
+			u := &alumniUsecase{alumniRepo: ar, importOpts: AlumniImportOptions{MaxRows: tt.maxRows}}
+
+			report, err := u.ImportAlumni(context.Background(), "alumni.csv", strings.NewReader(tt.csv), tt.dryRun, domain.AuditMeta{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			wantErrors := tt.wantErrors
+			if wantErrors == nil {
+				wantErrors = []domain.ImportRowError{}
+			}
+			if !reflect.DeepEqual(report.Errors, wantErrors) {
+				t.Errorf("Errors = %+v, want %+v", report.Errors, wantErrors)
+			}
+			if report.ValidRows != tt.wantValid || report.InvalidRows != len(wantErrors) || report.Imported != tt.imported {
+				t.Errorf("report = %+v, want %d valid, %d imported", report, tt.wantValid, tt.imported)
+			}
+			if saved := len(ar.alumni) - 1; saved != tt.imported {
+				t.Errorf("saved %d alumni, want %d", saved, tt.imported)
+			}
+		})
+	}
+}
+
+func TestImportAlumniUnsupportedFile(t *testing.T) {
+	u := &alumniUsecase{alumniRepo: newFakeAlumniRepo()}
+	if _, err := u.ImportAlumni(context.Background(), "alumni.txt", strings.NewReader(""), true, domain.AuditMeta{}); err == nil {
+		t.Fatal("expected error for .txt file")
+	}
+}
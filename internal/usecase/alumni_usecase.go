@@ -9,13 +9,16 @@ import (
 
 type alumniUsecase struct {
 	alumniRepo repository.AlumniRepository
+	userRepo   repository.UserRepository
+	importOpts AlumniImportOptions
+	exportOpts ExportOptions
 }
 
-func NewAlumniUsecase(ar repository.AlumniRepository) AlumniUsecase {
-	return &alumniUsecase{alumniRepo: ar}
+func NewAlumniUsecase(ar repository.AlumniRepository, ur repository.UserRepository, importOpts AlumniImportOptions, exportOpts ExportOptions) AlumniUsecase {
+	return &alumniUsecase{alumniRepo: ar, userRepo: ur, importOpts: importOpts, exportOpts: exportOpts}
 }
 
-func (u *alumniUsecase) CreateAlumni(ctx context.Context, req *domain.CreateAlumniRequest) (*domain.Alumni, error) {
+func (u *alumniUsecase) CreateAlumni(ctx context.Context, req *domain.CreateAlumniRequest, meta domain.AuditMeta) (*domain.Alumni, error) {
 	alumni := &domain.Alumni{
 		NIM:        req.NIM,
 		Nama:       req.Nama,
@@ -26,7 +29,7 @@ func (u *alumniUsecase) CreateAlumni(ctx context.Context, req *domain.CreateAlum
 		NoTelepon:  req.NoTelepon,
 		Alamat:     req.Alamat,
 	}
-	return u.alumniRepo.Create(ctx, alumni)
+	return u.alumniRepo.Create(ctx, alumni, meta)
 }
 
 func (u *alumniUsecase) GetAllAlumni(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Alumni], error) {
@@ -37,7 +40,7 @@ func (u *alumniUsecase) GetAlumniByID(ctx context.Context, id int) (*domain.Alum
 	return u.alumniRepo.FindByID(ctx, id)
 }
 
-func (u *alumniUsecase) UpdateAlumni(ctx context.Context, id int, req *domain.UpdateAlumniRequest) (*domain.Alumni, error) {
+func (u *alumniUsecase) UpdateAlumni(ctx context.Context, id int, req *domain.UpdateAlumniRequest, meta domain.AuditMeta) (*domain.Alumni, error) {
 	alumni, err := u.alumniRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -51,9 +54,22 @@ func (u *alumniUsecase) UpdateAlumni(ctx context.Context, id int, req *domain.Up
 	alumni.NoTelepon = req.NoTelepon
 	alumni.Alamat = req.Alamat
 
-	return u.alumniRepo.Update(ctx, alumni)
+	return u.alumniRepo.Update(ctx, alumni, meta)
 }
 
-func (u *alumniUsecase) DeleteAlumni(ctx context.Context, id int) error {
-	return u.alumniRepo.Delete(ctx, id)
+func (u *alumniUsecase) LinkUser(ctx context.Context, id int, req *domain.LinkAlumniUserRequest, meta domain.AuditMeta) (*domain.Alumni, error) {
+	if req.UserID != nil {
+		if _, err := u.userRepo.GetUserByID(ctx, *req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.alumniRepo.SetUser(ctx, id, req.UserID, meta); err != nil {
+		return nil, err
+	}
+	return u.alumniRepo.FindByID(ctx, id)
+}
+
+func (u *alumniUsecase) DeleteAlumni(ctx context.Context, id int, meta domain.AuditMeta) error {
+	return u.alumniRepo.Delete(ctx, id, meta)
 }
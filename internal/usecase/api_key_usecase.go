@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"back-train/pkg/utils"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyPrefix         = "btk_"
+	apiKeyDefaultTTLDays = 90
+	apiKeyMaxTTLDays     = 365
+)
+
+type apiKeyUsecase struct {
+	apiKeyRepo repository.APIKeyRepository
+	userRepo   repository.UserRepository
+}
+
+func NewAPIKeyUsecase(akr repository.APIKeyRepository, ur repository.UserRepository) APIKeyUsecase {
+	return &apiKeyUsecase{apiKeyRepo: akr, userRepo: ur}
+}
+
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, userID int, req *domain.CreateAPIKeyRequest) (*domain.CreateAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, domain.ErrInvalidAPIKeyName
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = apiKeyDefaultTTLDays
+	}
+	if days < 1 || days > apiKeyMaxTTLDays {
+		return nil, domain.ErrInvalidAPIKeyExpiry
+	}
+
+	scopes := uniqueStrings(req.Scopes)
+	if len(scopes) == 0 {
+		return nil, domain.ErrScopesRequired
+	}
+
+	// Key tidak boleh memberi akses melebihi permission pemiliknya saat ini
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range scopes {
+		if !containsPermission(user.Permissions, scope) {
+			return nil, domain.ErrScopeNotGranted
+		}
+	}
+
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	raw := apiKeyPrefix + secret
+
+	key := &domain.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    raw[:12],
+		Scopes:    scopes,
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+	}
+	if err := u.apiKeyRepo.Create(ctx, key, utils.HashToken(raw)); err != nil {
+		return nil, err
+	}
+	return &domain.CreateAPIKeyResponse{APIKey: *key, Key: raw}, nil
+}
+
+func (u *apiKeyUsecase) GetMyAPIKeys(ctx context.Context, userID int) ([]domain.APIKey, error) {
+	return u.apiKeyRepo.FindByUserID(ctx, userID)
+}
+
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, userID, id int) error {
+	return u.apiKeyRepo.Revoke(ctx, userID, id)
+}
+
+func (u *apiKeyUsecase) AuthenticateAPIKey(ctx context.Context, rawKey string) (*domain.User, *domain.APIKey, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+
+	key, err := u.apiKeyRepo.FindByHash(ctx, utils.HashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return nil, nil, domain.ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+	if key.RevokedAt != nil || time.Now().After(key.ExpiresAt) {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+
+	// Permission efektif = scope key yang masih dimiliki user, jadi pencabutan role langsung berlaku
+	permissions := []string{}
+	for _, scope := range key.Scopes {
+		if containsPermission(user.Permissions, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+	user.Permissions = permissions
+
+	if err := u.apiKeyRepo.TouchLastUsed(ctx, key.ID); err != nil {
+		log.Printf("failed to update last_used_at for api key %d: %v", key.ID, err)
+	}
+	return user, key, nil
+}
+
+func containsPermission(permissions []string, name string) bool {
+	for _, p := range permissions {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
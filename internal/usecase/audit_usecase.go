@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"context"
+)
+
+type auditUsecase struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditUsecase(ar repository.AuditRepository) AuditUsecase {
+	return &auditUsecase{auditRepo: ar}
+}
+
+func (u *auditUsecase) GetAuditLogs(ctx context.Context, filter domain.AuditFilter) (*domain.PaginationResult[domain.AuditLog], error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, domain.ErrInvalidAuditFilter
+	}
+	return u.auditRepo.FindAll(ctx, filter)
+}
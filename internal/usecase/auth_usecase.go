@@ -3,30 +3,96 @@ package usecase
 import (
 	"back-train/internal/domain"
 	"back-train/internal/repository"
+	"back-train/pkg/jwtkeys"
+	"back-train/pkg/mailer"
+	"back-train/pkg/passwordhash"
+	"back-train/pkg/passwordpolicy"
 	"back-train/pkg/utils"
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// AuthOptions berisi pengaturan token dan link yang dipakai authUsecase
+type AuthOptions struct {
+	JWTSecret            string          // dipakai untuk menurunkan kunci HMAC link email dan challenge MFA
+	Keys                 *jwtkeys.KeySet // menandatangani access token
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+	AppBaseURL           string // dipakai untuk membuat link di email
+
+	MFAIssuer           string        // nama yang tampil di aplikasi authenticator
+	MFAChallengeTTL     time.Duration // masa berlaku mfa_token antara langkah password dan kode TOTP
+	MFARequiredForAdmin bool          // admin tanpa 2FA dipaksa setup sebelum mendapat token
+
+	Lockout LockoutPolicy
+
+	ImpersonationTTL time.Duration // masa berlaku token impersonation admin
+
+	// PasswordPolicy diterapkan pada register, reset, dan ganti password; nil berarti tanpa aturan
+	PasswordPolicy *passwordpolicy.Policy
+
+	// PasswordHasher membuat hash password baru; nil berarti Argon2id dengan parameter bawaan
+	PasswordHasher *passwordhash.Hasher
+
+	// Authenticators dicoba berurutan saat Login; jika kosong hanya password lokal yang dipakai
+	Authenticators []Authenticator
+}
+
 type authUsecase struct {
-	userRepo           repository.UserRepository
-	jwtSecret          string
-	jwtExpirationHours time.Duration
+	userRepo          repository.UserRepository
+	sessionRepo       repository.SessionRepository
+	passwordResetRepo repository.PasswordResetRepository
+	mfaRepo           repository.MFARepository
+	loginFailureRepo  repository.LoginFailureRepository
+	authEventRepo     repository.AuthEventRepository
+	mailer            mailer.Mailer
+	authenticators    []Authenticator
+	opts              AuthOptions
+
+	// background menunggu pengiriman email reset password yang masih berjalan
+	background sync.WaitGroup
 }
 
-func NewAuthUsecase(ur repository.UserRepository, secret string, exp time.Duration) AuthUsecase {
+func NewAuthUsecase(ur repository.UserRepository, sr repository.SessionRepository, prr repository.PasswordResetRepository, mr repository.MFARepository, lfr repository.LoginFailureRepository, aer repository.AuthEventRepository, m mailer.Mailer, opts AuthOptions) AuthUsecase {
+	if opts.PasswordHasher == nil {
+		opts.PasswordHasher, _ = passwordhash.New(passwordhash.DefaultConfig())
+	}
+	authenticators := opts.Authenticators
+	if len(authenticators) == 0 {
+		authenticators = []Authenticator{NewPasswordAuthenticator(ur, opts.PasswordHasher)}
+	}
+
 	return &authUsecase{
-		userRepo:           ur,
-		jwtSecret:          secret,
-		jwtExpirationHours: exp,
+		userRepo:          ur,
+		sessionRepo:       sr,
+		passwordResetRepo: prr,
+		mfaRepo:           mr,
+		loginFailureRepo:  lfr,
+		authEventRepo:     aer,
+		mailer:            m,
+		authenticators:    authenticators,
+		opts:              opts,
 	}
 }
 
 func (u *authUsecase) Register(ctx context.Context, email, password string) (*domain.User, error) {
-	hashedPassword, err := utils.HashPassword(password)
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkPassword(password, email); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := u.opts.PasswordHasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
@@ -37,32 +103,265 @@ func (u *authUsecase) Register(ctx context.Context, email, password string) (*do
 	}
 
 	// Default role is 'user'
-	return u.userRepo.CreateUser(ctx, user, "user")
+	created, err := u.userRepo.CreateUser(ctx, user, "user")
+	if err != nil {
+		return nil, err
+	}
+
+	// Akun baru belum bisa login sampai link verifikasi dibuka
+	if err := u.sendVerificationEmail(ctx, created); err != nil {
+		log.Printf("failed to send verification email to user %d: %v", created.ID, err)
+	}
+	return created, nil
+}
+
+func (u *authUsecase) Login(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	if err := u.checkLockout(ctx, email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	user, err := u.authenticate(ctx, email, password)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			// Email yang tidak terdaftar tetap dihitung agar lockout tidak membocorkan akun mana yang ada
+			var userID *int
+			if user != nil {
+				userID = &user.ID
+			}
+			u.registerLoginFailure(ctx, userID, email, client.IPAddress)
+		}
+		return nil, err
+	}
+	u.clearLoginFailures(ctx, email)
+
+	if !user.IsActive {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	if user.EmailVerifiedAt == nil {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	return u.CompleteLogin(ctx, user, client)
+}
+
+// authenticate mencoba setiap authenticator sesuai urutan konfigurasi. Authenticator yang gagal
+// karena error selain kredensial salah (misalnya server LDAP down) dilewati agar backend lain tetap bisa dipakai.
+func (u *authUsecase) authenticate(ctx context.Context, email, password string) (*domain.User, error) {
+	var known *domain.User
+	unavailable := false
+	for _, authenticator := range u.authenticators {
+		user, err := authenticator.Authenticate(ctx, email, password)
+		if err == nil {
+			return user, nil
+		}
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			if user != nil {
+				known = user
+			}
+			continue
+		}
+		log.Printf("%s authenticator failed: %v", authenticator.Name(), err)
+		unavailable = true
+	}
+	// Jangan hitung ke lockout jika kegagalannya mungkin karena backend yang down, kecuali akunnya dikenal backend lain
+	if unavailable && known == nil {
+		return nil, domain.ErrAuthUnavailable
+	}
+	return known, domain.ErrInvalidCredentials
+}
+
+func (u *authUsecase) CompleteLogin(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	// Identitas yang sudah terverifikasi saja belum cukup jika 2FA aktif atau diwajibkan untuk role user
+	if user.MFAEnabled || u.mfaRequiredFor(user) {
+		return u.mfaChallenge(user)
+	}
+
+	return u.startSession(ctx, user, client)
+}
+
+func (u *authUsecase) Refresh(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	if refreshToken == "" {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	newRefreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := u.sessionRepo.RotateRefreshToken(ctx, utils.HashToken(refreshToken), utils.HashToken(newRefreshToken), time.Now().Add(u.opts.RefreshTokenTTL), client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ambil ulang user agar perubahan role langsung tercermin di access token baru
+	user, err := u.userRepo.GetUserByID(ctx, session.UserID)
+	if err != nil || !user.IsActive {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	accessToken, err := u.generateAccessToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResponse{
+		Token:        accessToken,
+		RefreshToken: newRefreshToken,
+		ExpiresIn:    int64(u.opts.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (u *authUsecase) Logout(ctx context.Context, sessionID string) error {
+	return u.sessionRepo.Revoke(ctx, sessionID, "logout")
 }
 
-func (u *authUsecase) Login(ctx context.Context, email, password string) (string, error) {
+func (u *authUsecase) ValidateSession(ctx context.Context, sessionID string) (bool, error) {
+	session, err := u.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	return session.RevokedAt == nil && time.Now().Before(session.ExpiresAt), nil
+}
+
+// passwordResetSendTimeout membatasi pembuatan token dan pengiriman email reset di background
+const passwordResetSendTimeout = time.Minute
+
+func (u *authUsecase) ForgotPassword(ctx context.Context, email string) error {
+	// Alamat yang tidak valid pasti tidak terdaftar; respons tetap seragam
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	// Jangan bocorkan apakah email terdaftar: email tak dikenal tetap dianggap sukses
 	user, err := u.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return err
 	}
+	if !user.IsActive {
+		return nil
+	}
+
+	// Token dan email dibuat di luar request agar waktu respons sama dengan email yang tidak terdaftar
+	u.background.Add(1)
+	go func() {
+		defer u.background.Done()
+		u.sendPasswordReset(user)
+	}()
+	return nil
+}
+
+// sendPasswordReset tidak memakai context request karena berjalan setelah respons dikirim
+func (u *authUsecase) sendPasswordReset(user *domain.User) {
+	ctx, cancel := context.WithTimeout(context.Background(), passwordResetSendTimeout)
+	defer cancel()
 
-	if !utils.CheckPasswordHash(password, user.PasswordHash) {
-		return "", errors.New("invalid credentials")
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		log.Printf("failed to create password reset token for user %d: %v", user.ID, err)
+		return
+	}
+	if err := u.passwordResetRepo.Create(ctx, user.ID, utils.HashToken(token), time.Now().Add(u.opts.PasswordResetTTL)); err != nil {
+		log.Printf("failed to save password reset token for user %d: %v", user.ID, err)
+		return
 	}
 
-	// Generate JWT
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"email":   user.Email,
-		"roles":   user.Roles,
-		"exp":     time.Now().Add(u.jwtExpirationHours).Unix(),
+	msg := mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Reset password",
+		Body: fmt.Sprintf("Kami menerima permintaan reset password untuk akun Anda.\n\n"+
+			"Buka link berikut untuk membuat password baru (berlaku %d menit):\n%s/reset-password?token=%s\n\n"+
+			"Abaikan email ini jika Anda tidak meminta reset password.",
+			int(u.opts.PasswordResetTTL.Minutes()), u.opts.AppBaseURL, token),
+	}
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		log.Printf("failed to send password reset email to user %d: %v", user.ID, err)
+	}
+}
+
+func (u *authUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return domain.ErrInvalidResetToken
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(u.jwtSecret))
+	// Email user dibutuhkan kebijakan password, jadi token dicek dulu sebelum dipakai
+	userID, err := u.passwordResetRepo.FindUserID(ctx, utils.HashToken(token))
 	if err != nil {
-		return "", err
+		return err
+	}
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := u.checkPassword(newPassword, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := u.opts.PasswordHasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err = u.passwordResetRepo.Consume(ctx, utils.HashToken(token), hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	// Password lama mungkin sudah bocor, jadi semua session yang ada ikut dicabut
+	return u.sessionRepo.RevokeAllForUser(ctx, userID, "password_reset")
+}
+
+// startSession membuat session baru beserta refresh token pertamanya, lalu menerbitkan access token
+func (u *authUsecase) startSession(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	sessionID, err := utils.GenerateRandomToken(24)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(u.opts.RefreshTokenTTL)
+	session := &domain.Session{
+		ID:        sessionID,
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+		ExpiresAt: expiresAt,
+	}
+	if err := u.sessionRepo.Create(ctx, session, utils.HashToken(refreshToken), expiresAt); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := u.generateAccessToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(u.opts.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (u *authUsecase) generateAccessToken(user *domain.User, sessionID string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id":     user.ID,
+		"email":       user.Email,
+		"roles":       user.Roles,
+		"permissions": user.Permissions,
+		"jti":         sessionID, // menghubungkan token ke session agar bisa dicabut
+		"iat":         now.Unix(),
+		"exp":         now.Add(u.opts.AccessTokenTTL).Unix(),
 	}
 
-	return tokenString, nil
+	return u.opts.Keys.Sign(claims)
 }
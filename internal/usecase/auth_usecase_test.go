@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/jwtkeys"
+	"back-train/pkg/utils"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestAuthUsecase(ur *fakeUserRepo, sr *fakeSessionRepo, opts AuthOptions) *authUsecase {
+	opts.Keys = jwtkeys.NewHMACKeySet("test", "test-secret")
+	if opts.AccessTokenTTL == 0 {
+		opts.AccessTokenTTL = 15 * time.Minute
+	}
+	if opts.RefreshTokenTTL == 0 {
+		opts.RefreshTokenTTL = 24 * time.Hour
+	}
+	return NewAuthUsecase(ur, sr, nil, nil, nil, nil, &fakeMailer{}, opts).(*authUsecase)
+}
+
+// seedSession membuat session aktif milik userID dan mengembalikan refresh token mentahnya
+func seedSession(t *testing.T, sr *fakeSessionRepo, userID int) string {
+	t.Helper()
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &domain.Session{ID: "s1", UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := sr.Create(context.Background(), session, utils.HashToken(token), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	active := &domain.User{ID: 1, Email: "a@example.com", IsActive: true}
+
+	t.Run("empty token", func(t *testing.T) {
+		u := newTestAuthUsecase(newFakeUserRepo(active), newFakeSessionRepo(), AuthOptions{})
+		if _, err := u.Refresh(ctx, "", domain.ClientInfo{}); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+			t.Fatalf("err = %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		u := newTestAuthUsecase(newFakeUserRepo(active), newFakeSessionRepo(), AuthOptions{})
+		if _, err := u.Refresh(ctx, "nope", domain.ClientInfo{}); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+			t.Fatalf("err = %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+
+	t.Run("rotation chain", func(t *testing.T) {
+		sr := newFakeSessionRepo()
+		u := newTestAuthUsecase(newFakeUserRepo(active), sr, AuthOptions{})
+		token := seedSession(t, sr, active.ID)
+
+		for i := 0; i < 3; i++ {
+			resp, err := u.Refresh(ctx, token, domain.ClientInfo{IPAddress: "10.0.0.1"})
+			if err != nil {
+				t.Fatalf("refresh %d: %v", i, err)
+			}
+			if resp.RefreshToken == "" || resp.RefreshToken == token {
+				t.Fatalf("refresh %d: refresh token not rotated", i)
+			}
+			if resp.Token == "" || resp.ExpiresIn != int64((15*time.Minute).Seconds()) {
+				t.Fatalf("refresh %d: unexpected access token response %+v", i, resp)
+			}
+			token = resp.RefreshToken
+		}
+		if sr.sessions["s1"].RevokedAt != nil {
+			t.Fatal("session revoked after normal rotation")
+		}
+	})
+
+	t.Run("reuse revokes session", func(t *testing.T) {
+		sr := newFakeSessionRepo()
+		u := newTestAuthUsecase(newFakeUserRepo(active), sr, AuthOptions{})
+		old := seedSession(t, sr, active.ID)
+
+		resp, err := u.Refresh(ctx, old, domain.ClientInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := u.Refresh(ctx, old, domain.ClientInfo{}); !errors.Is(err, domain.ErrRefreshTokenReused) {
+			t.Fatalf("reuse err = %v, want ErrRefreshTokenReused", err)
+		}
+		// Token terbaru milik pemilik asli ikut tidak berlaku karena session sudah dicabut
+		if _, err := u.Refresh(ctx, resp.RefreshToken, domain.ClientInfo{}); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+			t.Fatalf("latest token err = %v, want ErrInvalidRefreshToken", err)
+		}
+		if s := sr.sessions["s1"]; s.RevokedReason == nil || *s.RevokedReason != "refresh_token_reuse" {
+			t.Fatalf("session not revoked for reuse: %+v", s)
+		}
+	})
+
+	t.Run("inactive user", func(t *testing.T) {
+		sr := newFakeSessionRepo()
+		inactive := &domain.User{ID: 2, Email: "b@example.com"}
+		u := newTestAuthUsecase(newFakeUserRepo(inactive), sr, AuthOptions{})
+		token := seedSession(t, sr, inactive.ID)
+		if _, err := u.Refresh(ctx, token, domain.ClientInfo{}); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+			t.Fatalf("err = %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+}
+
+func TestRegisterDuplicateEmail(t *testing.T) {
+	ur := newFakeUserRepo(&domain.User{ID: 1, Email: "taken@example.com", IsActive: true})
+	u := newTestAuthUsecase(ur, newFakeSessionRepo(), AuthOptions{})
+	if _, err := u.Register(context.Background(), "Taken@Example.com", "Str0ng!Passw0rd#2024"); !errors.Is(err, domain.ErrEmailTaken) {
+		t.Fatalf("err = %v, want ErrEmailTaken", err)
+	}
+
+	// Email disimpan dalam huruf kecil agar cocok dengan index unik lower(email)
+	user, err := u.Register(context.Background(), " New.User@Example.COM ", "Str0ng!Passw0rd#2024")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if user.Email != "new.user@example.com" {
+		t.Fatalf("stored email = %q, want new.user@example.com", user.Email)
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"user@example.com", "user@example.com", false},
+		{"  User@Example.COM\t", "user@example.com", false},
+		{"Nama <user@example.com>", "", true},
+		{"not-an-email", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeEmail(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, domain.ErrInvalidEmail) {
+				t.Errorf("normalizeEmail(%q) err = %v, want ErrInvalidEmail", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeEmail(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		wantMail string // kosong berarti tidak ada token maupun email
+	}{
+		{"unknown email", "nobody@example.com", ""},
+		{"invalid email", "not-an-email", ""},
+		{"inactive user", "inactive@example.com", ""},
+		{"active user, email is normalized", " Active@Example.COM ", "active@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ur := newFakeUserRepo(
+				&domain.User{ID: 1, Email: "active@example.com", IsActive: true},
+				&domain.User{ID: 2, Email: "inactive@example.com", IsActive: false},
+			)
+			u := newTestAuthUsecase(ur, newFakeSessionRepo(), AuthOptions{PasswordResetTTL: time.Hour})
+			prr := newFakePasswordResetRepo()
+			u.passwordResetRepo = prr
+
+			if err := u.ForgotPassword(context.Background(), tt.email); err != nil {
+				t.Fatalf("ForgotPassword: %v", err)
+			}
+			u.background.Wait()
+
+			msgs := u.mailer.(*fakeMailer).messages()
+			if tt.wantMail == "" {
+				if prr.count() != 0 || len(msgs) != 0 {
+					t.Fatalf("tokens = %d, mails = %d; want none", prr.count(), len(msgs))
+				}
+				return
+			}
+			if prr.count() != 1 || len(msgs) != 1 || msgs[0].To[0] != tt.wantMail {
+				t.Fatalf("tokens = %d, mails = %v; want one token mailed to %s", prr.count(), msgs, tt.wantMail)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"back-train/pkg/ldapauth"
+	"back-train/pkg/passwordhash"
+	"context"
+	"errors"
+	"log"
+)
+
+// passwordAuthenticator memeriksa password terhadap hash di tabel users dan meng-upgrade
+// hash yang masih memakai algoritma atau parameter lama setelah login berhasil
+type passwordAuthenticator struct {
+	userRepo repository.UserRepository
+	hasher   *passwordhash.Hasher
+}
+
+func NewPasswordAuthenticator(ur repository.UserRepository, hasher *passwordhash.Hasher) Authenticator {
+	return &passwordAuthenticator{userRepo: ur, hasher: hasher}
+}
+
+func (a *passwordAuthenticator) Name() string {
+	return "local"
+}
+
+func (a *passwordAuthenticator) Authenticate(ctx context.Context, email, password string) (*domain.User, error) {
+	user, err := a.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	// Hash kosong atau tidak dikenal (misalnya akun SSO/LDAP) diperlakukan sebagai password salah
+	ok, err := a.hasher.Verify(password, user.PasswordHash)
+	if err != nil || !ok {
+		return user, domain.ErrInvalidCredentials
+	}
+
+	if a.hasher.NeedsRehash(user.PasswordHash) {
+		a.rehash(ctx, user, password)
+	}
+	return user, nil
+}
+
+// rehash menyimpan hash baru dengan pengaturan aktif; kegagalan hanya dicatat karena login tetap sah
+func (a *passwordAuthenticator) rehash(ctx context.Context, user *domain.User, password string) {
+	newHash, err := a.hasher.Hash(password)
+	if err != nil {
+		log.Printf("failed to rehash password for user %d: %v", user.ID, err)
+		return
+	}
+	if err := a.userRepo.UpgradePasswordHash(ctx, user.ID, user.PasswordHash, newHash); err != nil {
+		log.Printf("failed to save rehashed password for user %d: %v", user.ID, err)
+		return
+	}
+	user.PasswordHash = newHash
+}
+
+// LDAPDirectory adalah directory yang bisa memverifikasi kredensial, diimplementasikan oleh *ldapauth.Client
+type LDAPDirectory interface {
+	Authenticate(email, password string) (*ldapauth.Entry, error)
+}
+
+// LDAPOptions berisi pemetaan grup directory ke role lokal
+type LDAPOptions struct {
+	RoleMappings map[string]string // CN atau DN grup -> nama role lokal
+	DefaultRole  string
+}
+
+// ldapAuthenticator memverifikasi password lewat bind LDAP dan membuat user lokal pada login pertama
+type ldapAuthenticator struct {
+	directory    LDAPDirectory
+	userRepo     repository.UserRepository
+	identityRepo repository.UserIdentityRepository
+	opts         LDAPOptions
+}
+
+func NewLDAPAuthenticator(dir LDAPDirectory, ur repository.UserRepository, uir repository.UserIdentityRepository, opts LDAPOptions) Authenticator {
+	return &ldapAuthenticator{
+		directory:    dir,
+		userRepo:     ur,
+		identityRepo: uir,
+		opts:         opts,
+	}
+}
+
+func (a *ldapAuthenticator) Name() string {
+	return "ldap"
+}
+
+func (a *ldapAuthenticator) Authenticate(ctx context.Context, email, password string) (*domain.User, error) {
+	entry, err := a.directory.Authenticate(email, password)
+	if err != nil {
+		if errors.Is(err, ldapauth.ErrInvalidCredentials) {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	return provisionExternalUser(ctx, a.userRepo, a.identityRepo, externalIdentity{
+		Provider: a.Name(),
+		Subject:  entry.DN,
+		Email:    entry.Email,
+		Groups:   ldapauth.GroupNames(entry.Groups),
+	}, roleMapping{Mappings: a.opts.RoleMappings, DefaultRole: a.opts.DefaultRole})
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/ldapauth"
+	"back-train/pkg/passwordhash"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// stubAuthenticator mengembalikan hasil tetap dan mencatat apakah dipanggil
+type stubAuthenticator struct {
+	name   string
+	user   *domain.User
+	err    error
+	called bool
+}
+
+func (s *stubAuthenticator) Name() string { return s.name }
+
+func (s *stubAuthenticator) Authenticate(ctx context.Context, email, password string) (*domain.User, error) {
+	s.called = true
+	return s.user, s.err
+}
+
+func TestAuthenticateFallback(t *testing.T) {
+	alice := &domain.User{ID: 1, Email: "alice@example.com"}
+	down := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		chain    []*stubAuthenticator
+		wantUser *domain.User
+		wantErr  error
+		skipped  []bool // authenticator yang tidak boleh dipanggil
+	}{
+		{
+			name:     "first backend succeeds",
+			chain:    []*stubAuthenticator{{name: "local", user: alice}, {name: "ldap", err: down}},
+			wantUser: alice,
+			skipped:  []bool{false, true},
+		},
+		{
+			name:     "falls through to second backend",
+			chain:    []*stubAuthenticator{{name: "local", err: domain.ErrInvalidCredentials}, {name: "ldap", user: alice}},
+			wantUser: alice,
+		},
+		{
+			name:    "all backends reject",
+			chain:   []*stubAuthenticator{{name: "local", err: domain.ErrInvalidCredentials}, {name: "ldap", err: domain.ErrInvalidCredentials}},
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "unknown user while a backend is down",
+			chain:   []*stubAuthenticator{{name: "local", err: domain.ErrInvalidCredentials}, {name: "ldap", err: down}},
+			wantErr: domain.ErrAuthUnavailable,
+		},
+		{
+			// Akun lokal yang dikenal tetap dihitung ke lockout walau LDAP sedang down
+			name:     "known user while a backend is down",
+			chain:    []*stubAuthenticator{{name: "local", user: alice, err: domain.ErrInvalidCredentials}, {name: "ldap", err: down}},
+			wantUser: alice,
+			wantErr:  domain.ErrInvalidCredentials,
+		},
+		{
+			name:     "down backend before a working one",
+			chain:    []*stubAuthenticator{{name: "ldap", err: down}, {name: "local", user: alice}},
+			wantUser: alice,
+		},
+		{
+			name:    "only backend down",
+			chain:   []*stubAuthenticator{{name: "ldap", err: down}},
+			wantErr: domain.ErrAuthUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticators := make([]Authenticator, len(tt.chain))
+			for i, a := range tt.chain {
+				authenticators[i] = a
+			}
+			u := &authUsecase{authenticators: authenticators}
+
+			user, err := u.authenticate(context.Background(), "alice@example.com", "pw")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if user != tt.wantUser {
+				t.Fatalf("user = %+v, want %+v", user, tt.wantUser)
+			}
+			for i, skip := range tt.skipped {
+				if tt.chain[i].called == skip {
+					t.Fatalf("authenticator %s called = %v", tt.chain[i].name, tt.chain[i].called)
+				}
+			}
+		})
+	}
+}
+
+type stubDirectory struct {
+	entry *ldapauth.Entry
+	err   error
+}
+
+func (d stubDirectory) Authenticate(email, password string) (*ldapauth.Entry, error) {
+	return d.entry, d.err
+}
+
+func sortedRoles(u *domain.User) []string {
+	roles := append([]string(nil), u.Roles...)
+	sort.Strings(roles)
+	return roles
+}
+
+func TestLDAPAuthenticatorProvisioning(t *testing.T) {
+	ctx := context.Background()
+	opts := LDAPOptions{
+		RoleMappings: map[string]string{"staff": "admin", "cn=alumni,ou=groups,dc=example,dc=org": "alumni"},
+		DefaultRole:  "user",
+	}
+	entry := func(groups ...string) *ldapauth.Entry {
+		return &ldapauth.Entry{DN: "uid=alice,dc=example,dc=org", Email: "alice@example.com", Groups: groups}
+	}
+
+	t.Run("first login creates user with mapped roles", func(t *testing.T) {
+		ur, uir := newFakeUserRepo(), newFakeIdentityRepo()
+		a := NewLDAPAuthenticator(stubDirectory{entry: entry("cn=staff,ou=groups,dc=example,dc=org")}, ur, uir, opts)
+
+		user, err := a.Authenticate(ctx, "alice@example.com", "pw")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Default role diberikan saat user dibuat, role hasil pemetaan ditambahkan di atasnya
+		if !reflect.DeepEqual(sortedRoles(user), []string{"admin", "user"}) {
+			t.Fatalf("roles = %v, want [admin user]", user.Roles)
+		}
+		if user.EmailVerifiedAt == nil || user.PasswordHash != "" {
+			t.Fatalf("provisioned user should be verified without local password: %+v", user)
+		}
+		if uir.links["ldap|uid=alice,dc=example,dc=org"] != user.ID {
+			t.Fatal("identity not linked")
+		}
+
+		// Login kedua memakai identitas yang sudah terhubung, tidak membuat user baru
+		if _, err := a.Authenticate(ctx, "alice@example.com", "pw"); err != nil || len(ur.users) != 1 {
+			t.Fatalf("second login: err %v, users %d", err, len(ur.users))
+		}
+	})
+
+	t.Run("links existing local account by email and keeps manual roles", func(t *testing.T) {
+		existing := &domain.User{ID: 5, Email: "alice@example.com", Roles: []string{"user", "admin"}, IsActive: true}
+		ur, uir := newFakeUserRepo(existing), newFakeIdentityRepo()
+		a := NewLDAPAuthenticator(stubDirectory{entry: entry("cn=alumni,ou=groups,dc=example,dc=org")}, ur, uir, opts)
+
+		user, err := a.Authenticate(ctx, "alice@example.com", "pw")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.ID != existing.ID || len(ur.users) != 1 {
+			t.Fatalf("user %d, users %d: want existing account linked", user.ID, len(ur.users))
+		}
+		// admin dikelola lewat pemetaan grup jadi dicabut; user diberikan manual jadi tetap
+		if !reflect.DeepEqual(sortedRoles(user), []string{"alumni", "user"}) {
+			t.Fatalf("roles = %v, want [alumni user]", user.Roles)
+		}
+	})
+
+	t.Run("no mapped group falls back to default role", func(t *testing.T) {
+		existing := &domain.User{ID: 5, Email: "alice@example.com", Roles: []string{"admin"}, IsActive: true}
+		ur := newFakeUserRepo(existing)
+		a := NewLDAPAuthenticator(stubDirectory{entry: entry()}, ur, newFakeIdentityRepo(), opts)
+
+		user, err := a.Authenticate(ctx, "alice@example.com", "pw")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sortedRoles(user), []string{"user"}) {
+			t.Fatalf("roles = %v, want [user]", user.Roles)
+		}
+	})
+
+	t.Run("directory errors", func(t *testing.T) {
+		ur := newFakeUserRepo()
+		bad := NewLDAPAuthenticator(stubDirectory{err: ldapauth.ErrInvalidCredentials}, ur, newFakeIdentityRepo(), opts)
+		if _, err := bad.Authenticate(ctx, "alice@example.com", "pw"); !errors.Is(err, domain.ErrInvalidCredentials) {
+			t.Fatalf("err = %v, want ErrInvalidCredentials", err)
+		}
+		down := NewLDAPAuthenticator(stubDirectory{err: errors.New("ldapauth: connect: refused")}, ur, newFakeIdentityRepo(), opts)
+		if _, err := down.Authenticate(ctx, "alice@example.com", "pw"); err == nil || errors.Is(err, domain.ErrInvalidCredentials) {
+			t.Fatalf("err = %v, want connection error", err)
+		}
+		if len(ur.users) != 0 {
+			t.Fatal("user provisioned on failed login")
+		}
+	})
+}
+
+func TestRoleMappingApply(t *testing.T) {
+	m := roleMapping{Mappings: map[string]string{"staff": "admin", "grads": "alumni"}, DefaultRole: "user"}
+
+	tests := []struct {
+		name        string
+		current     []string
+		groups      []string
+		want        []string
+		wantChanged bool
+	}{
+		{"adds mapped role", []string{"user"}, []string{"staff"}, []string{"admin", "user"}, true},
+		{"removes managed role", []string{"user", "admin"}, nil, []string{"user"}, true},
+		{"unchanged", []string{"admin", "user"}, []string{"staff"}, []string{"admin", "user"}, false},
+		{"default when nothing left", []string{"admin"}, []string{"other"}, []string{"user"}, true},
+		{"duplicate groups", nil, []string{"staff", "staff", "grads"}, []string{"admin", "alumni"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := m.apply(tt.current, tt.groups)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) || changed != tt.wantChanged {
+				t.Fatalf("apply() = %v, %v; want %v, %v", got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+
+	if roles, changed := (roleMapping{DefaultRole: "user"}).apply([]string{"admin"}, []string{"staff"}); changed || !reflect.DeepEqual(roles, []string{"admin"}) {
+		t.Fatalf("without mappings roles must be left alone, got %v %v", roles, changed)
+	}
+}
+
+func TestPasswordAuthenticatorRehash(t *testing.T) {
+	ctx := context.Background()
+	legacy, _ := passwordhash.New(passwordhash.Config{Algorithm: passwordhash.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+	current, _ := passwordhash.New(passwordhash.Config{Algorithm: passwordhash.AlgorithmArgon2id, Argon2id: passwordhash.Argon2idParams{
+		Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 16,
+	}})
+	oldHash, _ := legacy.Hash("pw")
+
+	stored := &domain.User{ID: 1, Email: "alice@example.com", PasswordHash: oldHash, IsActive: true}
+	a := NewPasswordAuthenticator(newFakeUserRepo(stored), current)
+
+	if _, err := a.Authenticate(ctx, "alice@example.com", "wrong"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Fatalf("err = %v, want ErrInvalidCredentials", err)
+	}
+	if stored.PasswordHash != oldHash {
+		t.Fatal("hash upgraded after wrong password")
+	}
+
+	if _, err := a.Authenticate(ctx, "alice@example.com", "pw"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored.PasswordHash, "$argon2id$") || current.NeedsRehash(stored.PasswordHash) {
+		t.Fatalf("hash not upgraded: %s", stored.PasswordHash)
+	}
+	if _, err := a.Authenticate(ctx, "alice@example.com", "pw"); err != nil {
+		t.Fatalf("login with upgraded hash: %v", err)
+	}
+}
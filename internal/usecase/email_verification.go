@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/mailer"
+	"back-train/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+)
+
+// emailVerificationPayload adalah isi link verifikasi. Email ikut ditandatangani agar
+// link otomatis tidak berlaku lagi jika email akun sudah berganti.
+type emailVerificationPayload struct {
+	UserID    int    `json:"uid"`
+	Email     string `json:"email"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// verificationKey diturunkan dari secret JWT tetapi berbeda dari kunci access token
+func (u *authUsecase) verificationKey() []byte {
+	return []byte("email-verification:" + u.opts.JWTSecret)
+}
+
+func (u *authUsecase) sendVerificationEmail(ctx context.Context, user *domain.User) error {
+	payload, err := json.Marshal(emailVerificationPayload{
+		UserID:    user.ID,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(u.opts.EmailVerificationTTL).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	token := utils.SignToken(u.verificationKey(), payload)
+
+	msg := mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Verifikasi email",
+		Body: fmt.Sprintf("Terima kasih telah mendaftar.\n\n"+
+			"Buka link berikut untuk memverifikasi email Anda (berlaku %d jam):\n%s/verify-email?token=%s",
+			int(u.opts.EmailVerificationTTL.Hours()), u.opts.AppBaseURL, url.QueryEscape(token)),
+	}
+	return u.mailer.Send(ctx, msg)
+}
+
+func (u *authUsecase) VerifyEmail(ctx context.Context, token string) error {
+	raw, ok := utils.VerifySignedToken(u.verificationKey(), token)
+	if !ok {
+		return domain.ErrInvalidVerification
+	}
+
+	var payload emailVerificationPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return domain.ErrInvalidVerification
+	}
+	if time.Now().Unix() > payload.ExpiresAt {
+		return domain.ErrInvalidVerification
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.ErrInvalidVerification
+		}
+		return err
+	}
+	if user.Email != payload.Email {
+		return domain.ErrInvalidVerification
+	}
+
+	return u.userRepo.MarkEmailVerified(ctx, user.ID)
+}
+
+func (u *authUsecase) ResendVerification(ctx context.Context, email string) error {
+	// Sama seperti forgot-password: email tak dikenal atau sudah terverifikasi tidak dibedakan
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil || !user.IsActive {
+		return nil
+	}
+
+	if err := u.sendVerificationEmail(ctx, user); err != nil {
+		log.Printf("failed to send verification email to user %d: %v", user.ID, err)
+	}
+	return nil
+}
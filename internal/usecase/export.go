@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/spreadsheet"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportOptions mengatur batas export alumni dan pekerjaan
+type ExportOptions struct {
+	PDFMaxRows int           // 0 = tanpa batas
+	Timeout    time.Duration // batas waktu export langsung lewat HTTP
+}
+
+// ExportFile adalah export yang format dan kolomnya sudah divalidasi. Data baru dibaca dari
+// database saat Write dipanggil, sehingga handler bisa mengirim header response lebih dulu.
+type ExportFile struct {
+	Filename    string
+	ContentType string
+	Timeout     time.Duration // 0 = tanpa batas waktu
+	write       func(ctx context.Context, w io.Writer) (int, error)
+	count       func(ctx context.Context) (int64, error)
+}
+
+func (e *ExportFile) Write(ctx context.Context, w io.Writer) error {
+	_, err := e.write(ctx, w)
+	return err
+}
+
+// exportProgressInterval adalah jumlah baris di antara dua laporan progress job export
+const exportProgressInterval = 500
+
+type exportColumn[T any] struct {
+	key   string
+	title string
+	value func(*T) string
+}
+
+// newExport memilih kolom (semua jika keys kosong, urutan mengikuti keys) lalu menyiapkan
+// penulisan baris per baris dari stream. count dipakai untuk menolak PDF yang terlalu besar sebelum
+// response dikirim dan untuk progress job export.
+func newExport[T any](ctx context.Context, name, format string, opts ExportOptions, columns []exportColumn[T], keys []string, stream func(ctx context.Context, fn func(*T) error) error, count func(ctx context.Context) (int64, error)) (*ExportFile, error) {
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX && format != spreadsheet.FormatPDF {
+		return nil, domain.ErrInvalidExportFormat
+	}
+
+	selected := columns
+	if len(keys) > 0 {
+		byKey := make(map[string]exportColumn[T], len(columns))
+		for _, col := range columns {
+			byKey[col.key] = col
+		}
+		selected = make([]exportColumn[T], 0, len(keys))
+		for _, key := range keys {
+			col, ok := byKey[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", domain.ErrInvalidExportColumn, key)
+			}
+			selected = append(selected, col)
+		}
+	}
+
+	// PDF disusun seluruhnya di memori sebelum ditulis, berbeda dengan csv dan xlsx
+	maxRows := 0
+	if format == spreadsheet.FormatPDF && opts.PDFMaxRows > 0 {
+		maxRows = opts.PDFMaxRows
+		total, err := count(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if total > int64(maxRows) {
+			return nil, fmt.Errorf("%w: pdf is limited to %d rows, got %d", domain.ErrExportTooManyRows, maxRows, total)
+		}
+	}
+
+	title := strings.ToUpper(name[:1]) + name[1:]
+	return &ExportFile{
+		Filename:    fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format),
+		ContentType: spreadsheet.ContentType(format),
+		Timeout:     opts.Timeout,
+		count:       count,
+		write: func(ctx context.Context, w io.Writer) (int, error) {
+			out, err := spreadsheet.NewWriter(format, w, title)
+			if err != nil {
+				return 0, err
+			}
+			header := make([]string, len(selected))
+			for i, col := range selected {
+				header[i] = col.title
+			}
+			if err := out.Write(header); err != nil {
+				return 0, err
+			}
+
+			rows := 0
+			row := make([]string, len(selected))
+			err = stream(ctx, func(item *T) error {
+				for i, col := range selected {
+					row[i] = col.value(item)
+				}
+				rows++
+				// Baris bisa bertambah setelah count dicek
+				if maxRows > 0 && rows > maxRows {
+					return domain.ErrExportTooManyRows
+				}
+				if rows%exportProgressInterval == 0 {
+					reportJobProgress(ctx, rows, 0)
+				}
+				return out.Write(row)
+			})
+			if err != nil {
+				return rows, err
+			}
+			reportJobProgress(ctx, rows, 0)
+			return rows, out.Close()
+		},
+	}, nil
+}
+
+// countParams memakai query listing dengan satu baris saja untuk mendapatkan jumlah total
+func countParams(params domain.PaginationParams) domain.PaginationParams {
+	params.Page = 1
+	params.Limit = 1
+	return params
+}
+
+func optionalText(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func optionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+var alumniExportColumns = []exportColumn[domain.Alumni]{
+	{"id", "ID", func(a *domain.Alumni) string { return strconv.Itoa(a.ID) }},
+	{"nim", "NIM", func(a *domain.Alumni) string { return a.NIM }},
+	{"nama", "Nama", func(a *domain.Alumni) string { return a.Nama }},
+	{"jurusan", "Jurusan", func(a *domain.Alumni) string { return a.Jurusan }},
+	{"angkatan", "Angkatan", func(a *domain.Alumni) string { return strconv.Itoa(a.Angkatan) }},
+	{"tahun_lulus", "Tahun Lulus", func(a *domain.Alumni) string { return strconv.Itoa(a.TahunLulus) }},
+	{"email", "Email", func(a *domain.Alumni) string { return a.Email }},
+	{"no_telepon", "No Telepon", func(a *domain.Alumni) string { return optionalText(a.NoTelepon) }},
+	{"alamat", "Alamat", func(a *domain.Alumni) string { return optionalText(a.Alamat) }},
+	{"created_at", "Dibuat", func(a *domain.Alumni) string { return a.CreatedAt.Format(time.RFC3339) }},
+}
+
+var pekerjaanExportColumns = []exportColumn[domain.Pekerjaan]{
+	{"id", "ID", func(p *domain.Pekerjaan) string { return strconv.Itoa(p.ID) }},
+	{"alumni_id", "ID Alumni", func(p *domain.Pekerjaan) string { return strconv.Itoa(p.AlumniID) }},
+	{"nama_perusahaan", "Nama Perusahaan", func(p *domain.Pekerjaan) string { return p.NamaPerusahaan }},
+	{"posisi_jabatan", "Posisi Jabatan", func(p *domain.Pekerjaan) string { return p.PosisiJabatan }},
+	{"bidang_industri", "Bidang Industri", func(p *domain.Pekerjaan) string { return p.BidangIndustri }},
+	{"lokasi_kerja", "Lokasi Kerja", func(p *domain.Pekerjaan) string { return p.LokasiKerja }},
+	{"gaji_range", "Gaji", func(p *domain.Pekerjaan) string { return optionalText(p.GajiRange) }},
+	{"tanggal_mulai_kerja", "Mulai Kerja", func(p *domain.Pekerjaan) string { return p.TanggalMulaiKerja.Format("2006-01-02") }},
+	{"tanggal_selesai_kerja", "Selesai Kerja", func(p *domain.Pekerjaan) string { return optionalDate(p.TanggalSelesaiKerja) }},
+	{"status_pekerjaan", "Status", func(p *domain.Pekerjaan) string { return p.StatusPekerjaan }},
+	{"deskripsi_pekerjaan", "Deskripsi", func(p *domain.Pekerjaan) string { return optionalText(p.DeskripsiPekerjaan) }},
+	{"created_at", "Dibuat", func(p *domain.Pekerjaan) string { return p.CreatedAt.Format(time.RFC3339) }},
+}
+
+// ExportAlumni memakai search, sort dan filter yang sama dengan GetAllAlumni; Page dan Limit diabaikan
+func (u *alumniUsecase) ExportAlumni(ctx context.Context, params domain.PaginationParams, format string, columns []string) (*ExportFile, error) {
+	return newExport(ctx, "alumni", format, u.exportOpts, alumniExportColumns, columns, func(ctx context.Context, fn func(*domain.Alumni) error) error {
+		return u.alumniRepo.StreamAll(ctx, params, fn)
+	}, func(ctx context.Context) (int64, error) {
+		result, err := u.alumniRepo.FindAll(ctx, countParams(params))
+		if err != nil {
+			return 0, err
+		}
+		return result.Total, nil
+	})
+}
+
+// ExportPekerjaan memakai search, sort dan filter yang sama dengan GetAllPekerjaan; Page dan Limit diabaikan
+func (u *pekerjaanUsecase) ExportPekerjaan(ctx context.Context, params domain.PaginationParams, format string, columns []string) (*ExportFile, error) {
+	return newExport(ctx, "pekerjaan", format, u.exportOpts, pekerjaanExportColumns, columns, func(ctx context.Context, fn func(*domain.Pekerjaan) error) error {
+		return u.pekerjaanRepo.StreamAll(ctx, params, fn)
+	}, func(ctx context.Context) (int64, error) {
+		result, err := u.pekerjaanRepo.FindAll(ctx, countParams(params))
+		if err != nil {
+			return 0, err
+		}
+		return result.Total, nil
+	})
+}
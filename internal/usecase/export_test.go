@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// newTestExport membuat export dengan n baris; total adalah jumlah yang dilaporkan count
+func newTestExport(t *testing.T, format string, opts ExportOptions, n int, total int64) (*ExportFile, error) {
+	t.Helper()
+	columns := []exportColumn[int]{{"n", "N", func(v *int) string { return strconv.Itoa(*v) }}}
+	return newExport(context.Background(), "test", format, opts, columns, nil, func(ctx context.Context, fn func(*int) error) error {
+		for i := 0; i < n; i++ {
+			if err := fn(&i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(ctx context.Context) (int64, error) {
+		return total, nil
+	})
+}
+
+func TestExportPDFMaxRows(t *testing.T) {
+	opts := ExportOptions{PDFMaxRows: 3}
+	tests := []struct {
+		name    string
+		format  string
+		rows    int
+		total   int64
+		wantErr bool
+	}{
+		{"pdf within limit", "pdf", 3, 3, false},
+		{"pdf over limit is rejected before writing", "pdf", 4, 4, true},
+		{"csv is not limited", "csv", 10, 10, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export, err := newTestExport(t, tt.format, opts, tt.rows, tt.total)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrExportTooManyRows) {
+					t.Fatalf("err = %v, want ErrExportTooManyRows", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newExport: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := export.Write(context.Background(), &buf); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+		})
+	}
+
+	// Baris yang bertambah setelah count dicek tetap dihentikan saat ditulis
+	export, err := newTestExport(t, "pdf", opts, 5, 2)
+	if err != nil {
+		t.Fatalf("newExport: %v", err)
+	}
+	if err := export.Write(context.Background(), &bytes.Buffer{}); !errors.Is(err, domain.ErrExportTooManyRows) {
+		t.Fatalf("Write err = %v, want ErrExportTooManyRows", err)
+	}
+}
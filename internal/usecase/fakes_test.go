@@ -0,0 +1,556 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"back-train/pkg/mailer"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fake repository in-memory untuk unit test usecase. Interface di-embed agar method yang tidak
+// dipakai test tidak perlu diimplementasikan (memanggilnya akan panic).
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users  map[int]*domain.User
+	nextID int
+}
+
+func newFakeUserRepo(users ...*domain.User) *fakeUserRepo {
+	r := &fakeUserRepo{users: map[int]*domain.User{}}
+	for _, u := range users {
+		r.users[u.ID] = u
+		if u.ID > r.nextID {
+			r.nextID = u.ID
+		}
+	}
+	return r
+}
+
+func (r *fakeUserRepo) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return u, nil
+}
+
+// GetUserByEmail meniru WHERE lower(u.email) = lower($1)
+func (r *fakeUserRepo) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if strings.ToLower(u.Email) == strings.ToLower(email) {
+			return u, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+// CreateUser meniru index unik lower(email)
+func (r *fakeUserRepo) CreateUser(ctx context.Context, user *domain.User, roleName string) (*domain.User, error) {
+	if _, err := r.GetUserByEmail(ctx, user.Email); err == nil {
+		return nil, domain.ErrEmailTaken
+	}
+	r.nextID++
+	created := *user
+	created.ID = r.nextID
+	created.IsActive = true // default kolom users.is_active
+	created.Roles = []string{roleName}
+	r.users[created.ID] = &created
+	return &created, nil
+}
+
+type fakeRefreshToken struct {
+	sessionID string
+	used      bool
+	expiresAt time.Time
+}
+
+type fakeSessionRepo struct {
+	repository.SessionRepository
+	sessions map[string]*domain.Session
+	tokens   map[string]*fakeRefreshToken
+}
+
+func newFakeSessionRepo() *fakeSessionRepo {
+	return &fakeSessionRepo{sessions: map[string]*domain.Session{}, tokens: map[string]*fakeRefreshToken{}}
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session *domain.Session, refreshTokenHash string, refreshExpiresAt time.Time) error {
+	if session.ID == "" {
+		session.ID = "session-" + refreshTokenHash[:8]
+	}
+	s := *session
+	r.sessions[s.ID] = &s
+	r.tokens[refreshTokenHash] = &fakeRefreshToken{sessionID: s.ID, expiresAt: refreshExpiresAt}
+	return nil
+}
+
+// RotateRefreshToken meniru aturan repository Postgres: token yang dipakai ulang mencabut session-nya
+func (r *fakeSessionRepo) RotateRefreshToken(ctx context.Context, oldHash, newHash string, newExpiresAt time.Time, client domain.ClientInfo) (*domain.Session, error) {
+	t, ok := r.tokens[oldHash]
+	if !ok {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	s := r.sessions[t.sessionID]
+	now := time.Now()
+	if s.RevokedAt != nil || now.After(s.ExpiresAt) || now.After(t.expiresAt) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if t.used {
+		reason := "refresh_token_reuse"
+		s.RevokedAt, s.RevokedReason = &now, &reason
+		return nil, domain.ErrRefreshTokenReused
+	}
+	t.used = true
+	r.tokens[newHash] = &fakeRefreshToken{sessionID: s.ID, expiresAt: newExpiresAt}
+	s.LastSeenAt, s.IPAddress = now, client.IPAddress
+	copied := *s
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, domain.ErrSessionNotFound
+	}
+	return s, nil
+}
+
+func (r *fakeSessionRepo) Revoke(ctx context.Context, id string, reason string) error {
+	if s, ok := r.sessions[id]; ok && s.RevokedAt == nil {
+		now := time.Now()
+		s.RevokedAt, s.RevokedReason = &now, &reason
+	}
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeAllForUser(ctx context.Context, userID int, reason string) error {
+	for _, s := range r.sessions {
+		if s.UserID == userID {
+			_ = r.Revoke(ctx, s.ID, reason)
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) Delete(ctx context.Context, id int) error {
+	if _, ok := r.users[id]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
+
+type fakeMFARepo struct {
+	repository.MFARepository
+	mfa           map[int]*domain.UserMFA
+	recoveryCodes map[int]map[string]bool
+}
+
+func newFakeMFARepo() *fakeMFARepo {
+	return &fakeMFARepo{mfa: map[int]*domain.UserMFA{}, recoveryCodes: map[int]map[string]bool{}}
+}
+
+func (r *fakeMFARepo) FindByUserID(ctx context.Context, userID int) (*domain.UserMFA, error) {
+	m, ok := r.mfa[userID]
+	if !ok {
+		return nil, domain.ErrMFANotEnabled
+	}
+	return m, nil
+}
+
+// UseStep meniru UPDATE ... WHERE last_used_step < $step
+func (r *fakeMFARepo) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	m := r.mfa[userID]
+	if step <= m.LastUsedStep {
+		return false, nil
+	}
+	m.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeMFARepo) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	r.recoveryCodes[userID] = map[string]bool{}
+	for _, h := range codeHashes {
+		r.recoveryCodes[userID][h] = true
+	}
+	return nil
+}
+
+func (r *fakeMFARepo) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	if !r.recoveryCodes[userID][codeHash] {
+		return false, nil
+	}
+	delete(r.recoveryCodes[userID], codeHash)
+	return true, nil
+}
+
+type fakeLoginFailures struct {
+	count       int
+	lockedUntil *time.Time
+}
+
+type fakeLoginFailureRepo struct {
+	entries map[string]*fakeLoginFailures
+}
+
+func newFakeLoginFailureRepo() *fakeLoginFailureRepo {
+	return &fakeLoginFailureRepo{entries: map[string]*fakeLoginFailures{}}
+}
+
+func (r *fakeLoginFailureRepo) entry(scope, key string) *fakeLoginFailures {
+	e, ok := r.entries[scope+":"+key]
+	if !ok {
+		e = &fakeLoginFailures{}
+		r.entries[scope+":"+key] = e
+	}
+	return e
+}
+
+func (r *fakeLoginFailureRepo) LockedUntil(ctx context.Context, scope, key string) (*time.Time, error) {
+	e := r.entry(scope, key)
+	if e.lockedUntil != nil && e.lockedUntil.After(time.Now()) {
+		return e.lockedUntil, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeLoginFailureRepo) RegisterFailure(ctx context.Context, scope, key string, window time.Duration) (int, error) {
+	e := r.entry(scope, key)
+	e.count++
+	return e.count, nil
+}
+
+func (r *fakeLoginFailureRepo) Lock(ctx context.Context, scope, key string, until time.Time) error {
+	r.entry(scope, key).lockedUntil = &until
+	return nil
+}
+
+func (r *fakeLoginFailureRepo) Reset(ctx context.Context, scope, key string) error {
+	delete(r.entries, scope+":"+key)
+	return nil
+}
+
+type fakeAuthEventRepo struct {
+	events []domain.AuthEvent
+}
+
+func (r *fakeAuthEventRepo) Record(ctx context.Context, event *domain.AuthEvent) error {
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeUserRepo) MarkEmailVerified(ctx context.Context, id int) error {
+	u, ok := r.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	return nil
+}
+
+func (r *fakeUserRepo) SetRoles(ctx context.Context, userID int, roleNames []string) error {
+	u, ok := r.users[userID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	u.Roles = append([]string(nil), roleNames...)
+	return nil
+}
+
+type fakeIdentityRepo struct {
+	repository.UserIdentityRepository
+	links map[string]int // provider|subject -> user ID
+}
+
+func newFakeIdentityRepo() *fakeIdentityRepo {
+	return &fakeIdentityRepo{links: map[string]int{}}
+}
+
+func (r *fakeIdentityRepo) FindUserID(ctx context.Context, provider, subject string) (int, error) {
+	id, ok := r.links[provider+"|"+subject]
+	if !ok {
+		return 0, domain.ErrUserNotFound
+	}
+	return id, nil
+}
+
+func (r *fakeIdentityRepo) Link(ctx context.Context, userID int, provider, subject, email string) error {
+	r.links[provider+"|"+subject] = userID
+	return nil
+}
+
+func (r *fakeUserRepo) UpgradePasswordHash(ctx context.Context, id int, oldHash, newHash string) error {
+	if u, ok := r.users[id]; ok && u.PasswordHash == oldHash {
+		u.PasswordHash = newHash
+	}
+	return nil
+}
+
+type fakeTracerRepo struct {
+	repository.TracerRepository
+	questionnaires map[int]*domain.Questionnaire
+	submitted      map[int]bool // questionnaire ID -> sudah ada jawaban
+}
+
+func newFakeTracerRepo(qs ...*domain.Questionnaire) *fakeTracerRepo {
+	r := &fakeTracerRepo{questionnaires: map[int]*domain.Questionnaire{}, submitted: map[int]bool{}}
+	for _, q := range qs {
+		r.questionnaires[q.ID] = q
+	}
+	return r
+}
+
+func (r *fakeTracerRepo) FindQuestionnaireByID(ctx context.Context, id int) (*domain.Questionnaire, error) {
+	q, ok := r.questionnaires[id]
+	if !ok {
+		return nil, domain.ErrTracerNotFound
+	}
+	copied := *q
+	return &copied, nil
+}
+
+func (r *fakeTracerRepo) UpdateQuestionnaire(ctx context.Context, q *domain.Questionnaire) (*domain.Questionnaire, error) {
+	if _, ok := r.questionnaires[q.ID]; !ok {
+		return nil, domain.ErrTracerNotFound
+	}
+	updated := *q
+	r.questionnaires[q.ID] = &updated
+	return q, nil
+}
+
+func (r *fakeTracerRepo) HasSubmissions(ctx context.Context, questionnaireID int) (bool, error) {
+	return r.submitted[questionnaireID], nil
+}
+
+// fakeMailer menyimpan email yang dikirim; aman dipakai dari goroutine
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []mailer.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *fakeMailer) messages() []mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mailer.Message(nil), m.sent...)
+}
+
+// fakeJobRepo mencatat status akhir job yang disimpan worker
+type fakeJobRepo struct {
+	repository.JobRepository
+	mu       sync.Mutex
+	finished map[int64]string // job ID -> status akhir
+}
+
+func newFakeJobRepo() *fakeJobRepo {
+	return &fakeJobRepo{finished: map[int64]string{}}
+}
+
+func (r *fakeJobRepo) Heartbeat(ctx context.Context, id int64, workerID string, done, total int, lease time.Duration) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeJobRepo) Complete(ctx context.Context, job *domain.Job, workerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished[job.ID] = domain.JobStatusSucceeded
+	return nil
+}
+
+func (r *fakeJobRepo) Finish(ctx context.Context, id int64, workerID, status string, lastError *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished[id] = status
+	return nil
+}
+
+type fakeAlumniRepo struct {
+	repository.AlumniRepository
+	alumni map[int]*domain.Alumni
+}
+
+func newFakeAlumniRepo(list ...*domain.Alumni) *fakeAlumniRepo {
+	r := &fakeAlumniRepo{alumni: map[int]*domain.Alumni{}}
+	for _, a := range list {
+		r.alumni[a.ID] = a
+	}
+	return r
+}
+
+func (r *fakeAlumniRepo) FindByID(ctx context.Context, id int) (*domain.Alumni, error) {
+	a, ok := r.alumni[id]
+	if !ok {
+		return nil, domain.ErrAlumniNotFound
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (r *fakeAlumniRepo) FindByNIM(ctx context.Context, nim string) (*domain.Alumni, error) {
+	for _, a := range r.alumni {
+		if a.NIM == nim {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrAlumniNotFound
+}
+
+func (r *fakeAlumniRepo) FindByUserID(ctx context.Context, userID int) (*domain.Alumni, error) {
+	for _, a := range r.alumni {
+		if a.UserID != nil && *a.UserID == userID {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrAlumniNotLinked
+}
+
+func (r *fakeAlumniRepo) Update(ctx context.Context, alumni *domain.Alumni, meta domain.AuditMeta) (*domain.Alumni, error) {
+	if _, ok := r.alumni[alumni.ID]; !ok {
+		return nil, domain.ErrAlumniNotFound
+	}
+	updated := *alumni
+	r.alumni[alumni.ID] = &updated
+	return alumni, nil
+}
+
+// FindExisting membandingkan email tanpa membedakan huruf besar, sama seperti repository asli
+func (r *fakeAlumniRepo) FindExisting(ctx context.Context, nims, emails []string) (map[string]bool, map[string]bool, error) {
+	existingNIMs, existingEmails := map[string]bool{}, map[string]bool{}
+	for _, a := range r.alumni {
+		for _, nim := range nims {
+			if a.NIM == nim {
+				existingNIMs[nim] = true
+			}
+		}
+		for _, email := range emails {
+			if strings.EqualFold(a.Email, email) {
+				existingEmails[email] = true
+			}
+		}
+	}
+	return existingNIMs, existingEmails, nil
+}
+
+func (r *fakeAlumniRepo) CreateMany(ctx context.Context, alumni []*domain.Alumni, meta domain.AuditMeta) error {
+	for _, a := range alumni {
+		created := *a
+		created.ID = len(r.alumni) + 1000
+		r.alumni[created.ID] = &created
+	}
+	return nil
+}
+
+// fakeAlumniClaimRepo memakai data fakeAlumniRepo yang sama; beforeLink dipanggil tepat sebelum
+// UPDATE ... WHERE user_id IS NULL untuk mensimulasikan klaim lain yang datang bersamaan
+type fakeAlumniClaimRepo struct {
+	repository.AlumniClaimRepository
+	alumni     *fakeAlumniRepo
+	claims     map[string][2]int // token hash -> {user ID, alumni ID}
+	beforeLink func()
+}
+
+func newFakeAlumniClaimRepo(ar *fakeAlumniRepo) *fakeAlumniClaimRepo {
+	return &fakeAlumniClaimRepo{alumni: ar, claims: map[string][2]int{}}
+}
+
+func (r *fakeAlumniClaimRepo) Create(ctx context.Context, userID, alumniID int, tokenHash string, expiresAt time.Time) error {
+	r.claims[tokenHash] = [2]int{userID, alumniID}
+	return nil
+}
+
+func (r *fakeAlumniClaimRepo) LinkIfUnclaimed(ctx context.Context, userID, alumniID int, meta domain.AuditMeta) error {
+	if r.beforeLink != nil {
+		r.beforeLink()
+	}
+	a, ok := r.alumni.alumni[alumniID]
+	if !ok {
+		return domain.ErrAlumniNotFound
+	}
+	if _, err := r.alumni.FindByUserID(ctx, userID); a.UserID != nil || err == nil {
+		return domain.ErrAlumniAlreadyLinked
+	}
+	a.UserID = &userID
+	return nil
+}
+
+type fakePekerjaanRepo struct {
+	repository.PekerjaanRepository
+	pekerjaan map[int]*domain.Pekerjaan
+}
+
+func newFakePekerjaanRepo(list ...*domain.Pekerjaan) *fakePekerjaanRepo {
+	r := &fakePekerjaanRepo{pekerjaan: map[int]*domain.Pekerjaan{}}
+	for _, p := range list {
+		r.pekerjaan[p.ID] = p
+	}
+	return r
+}
+
+func (r *fakePekerjaanRepo) FindByID(ctx context.Context, id int) (*domain.Pekerjaan, error) {
+	p, ok := r.pekerjaan[id]
+	if !ok {
+		return nil, domain.ErrPekerjaanNotFound
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (r *fakePekerjaanRepo) Update(ctx context.Context, pekerjaan *domain.Pekerjaan, meta domain.AuditMeta) (*domain.Pekerjaan, error) {
+	if _, ok := r.pekerjaan[pekerjaan.ID]; !ok {
+		return nil, domain.ErrPekerjaanNotFound
+	}
+	updated := *pekerjaan
+	r.pekerjaan[pekerjaan.ID] = &updated
+	return pekerjaan, nil
+}
+
+type fakePasswordResetRepo struct {
+	repository.PasswordResetRepository
+	mu     sync.Mutex
+	tokens map[string]int // token hash -> user ID
+}
+
+func newFakePasswordResetRepo() *fakePasswordResetRepo {
+	return &fakePasswordResetRepo{tokens: map[string]int{}}
+}
+
+func (r *fakePasswordResetRepo) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[tokenHash] = userID
+	return nil
+}
+
+func (r *fakePasswordResetRepo) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.tokens)
+}
+
+type fakeAuditRepo struct {
+	repository.AuditRepository
+	logs []domain.AuditLog
+}
+
+func (r *fakeAuditRepo) FindByEntity(ctx context.Context, entityType string, entityID int) ([]domain.AuditLog, error) {
+	logs := []domain.AuditLog{}
+	for _, l := range r.logs {
+		if l.EntityType == entityType && l.EntityID == entityID {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+type historyUsecase struct {
+	auditRepo        repository.AuditRepository
+	alumniUsecase    AlumniUsecase
+	mahasiswaUsecase MahasiswaUsecase
+	pekerjaanUsecase PekerjaanUsecase
+}
+
+func NewHistoryUsecase(ar repository.AuditRepository, au AlumniUsecase, mu MahasiswaUsecase, pu PekerjaanUsecase) HistoryUsecase {
+	return &historyUsecase{auditRepo: ar, alumniUsecase: au, mahasiswaUsecase: mu, pekerjaanUsecase: pu}
+}
+
+// current mengambil data yang tersimpan sekarang beserta waktu pembuatannya
+func (u *historyUsecase) current(ctx context.Context, entityType string, id int) (interface{}, time.Time, error) {
+	switch entityType {
+	case domain.AuditEntityAlumni:
+		a, err := u.alumniUsecase.GetAlumniByID(ctx, id)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return a, a.CreatedAt, nil
+	case domain.AuditEntityMahasiswa:
+		m, err := u.mahasiswaUsecase.GetMahasiswaByID(ctx, id)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return m, m.CreatedAt, nil
+	case domain.AuditEntityPekerjaan:
+		p, err := u.pekerjaanUsecase.GetPekerjaanByID(ctx, id)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return p, p.CreatedAt, nil
+	default:
+		return nil, time.Time{}, fmt.Errorf("unsupported entity type %q", entityType)
+	}
+}
+
+// versionFields mengubah entity ke bentuk field JSON yang sama dengan isi audit log
+func versionFields(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "created_at")
+	delete(fields, "updated_at")
+	return fields, nil
+}
+
+func decodeAuditFields(raw json.RawMessage) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if len(raw) == 0 {
+		return fields, nil
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetHistory menyusun ulang semua versi dari data sekarang dengan mengembalikan nilai "before"
+// setiap perubahan, mulai dari yang terbaru. Data yang sudah ada sebelum audit log aktif
+// mendapat versi baseline berisi kondisi sebelum perubahan pertama yang tercatat.
+func (u *historyUsecase) GetHistory(ctx context.Context, entityType string, id int) ([]domain.EntityVersion, error) {
+	entity, createdAt, err := u.current(ctx, entityType, id)
+	if err != nil {
+		return nil, err
+	}
+	state, err := versionFields(entity)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := u.auditRepo.FindByEntity(ctx, entityType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]domain.EntityVersion, 0, len(logs)+1)
+	baseline := true
+	for i := len(logs) - 1; i >= 0; i-- {
+		l := logs[i]
+		if l.Action == domain.AuditActionDelete {
+			continue
+		}
+		before, err := decodeAuditFields(l.Before)
+		if err != nil {
+			return nil, err
+		}
+		after, err := decodeAuditFields(l.After)
+		if err != nil {
+			return nil, err
+		}
+
+		data := make(map[string]interface{}, len(state))
+		for k, v := range state {
+			data[k] = v
+		}
+		versions = append(versions, domain.EntityVersion{
+			AuditLogID:     &logs[i].ID,
+			Action:         l.Action,
+			ActorUserID:    l.ActorUserID,
+			ImpersonatorID: l.ImpersonatorID,
+			ChangedFields:  sortedKeys(after),
+			Data:           data,
+			CreatedAt:      l.CreatedAt,
+		})
+
+		if l.Action == domain.AuditActionCreate {
+			baseline = false
+			break
+		}
+		for k, v := range before {
+			state[k] = v
+		}
+	}
+	if baseline {
+		versions = append(versions, domain.EntityVersion{
+			Action:        domain.HistoryActionBaseline,
+			ChangedFields: []string{},
+			Data:          state,
+			CreatedAt:     createdAt,
+		})
+	}
+
+	// Dibalik agar versi 1 adalah yang paling lama
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	for i := range versions {
+		versions[i].Version = i + 1
+	}
+	return versions, nil
+}
+
+func findVersion(versions []domain.EntityVersion, version int) (*domain.EntityVersion, error) {
+	if version < 1 || version > len(versions) {
+		return nil, domain.ErrVersionNotFound
+	}
+	return &versions[version-1], nil
+}
+
+func (u *historyUsecase) DiffVersions(ctx context.Context, entityType string, id, fromVersion, toVersion int) (*domain.VersionDiff, error) {
+	versions, err := u.GetHistory(ctx, entityType, id)
+	if err != nil {
+		return nil, err
+	}
+	from, err := findVersion(versions, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := findVersion(versions, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	for k := range from.Data {
+		fields[k] = nil
+	}
+	for k := range to.Data {
+		fields[k] = nil
+	}
+
+	changes := []domain.FieldChange{}
+	for _, field := range sortedKeys(fields) {
+		if !reflect.DeepEqual(from.Data[field], to.Data[field]) {
+			changes = append(changes, domain.FieldChange{Field: field, From: from.Data[field], To: to.Data[field]})
+		}
+	}
+	return &domain.VersionDiff{
+		EntityType:  entityType,
+		EntityID:    id,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Changes:     changes,
+	}, nil
+}
+
+// RestoreVersion menyimpan isi versi lama sebagai update baru sehingga riwayatnya tetap utuh.
+// Hanya field yang bisa diubah lewat PUT yang dikembalikan; akun yang terhubung ke alumni tidak ikut.
+func (u *historyUsecase) RestoreVersion(ctx context.Context, entityType string, id, version int, meta domain.AuditMeta) (interface{}, error) {
+	versions, err := u.GetHistory(ctx, entityType, id)
+	if err != nil {
+		return nil, err
+	}
+	v, err := findVersion(versions, version)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(v.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch entityType {
+	case domain.AuditEntityAlumni:
+		var req domain.UpdateAlumniRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			return nil, err
+		}
+		return u.alumniUsecase.UpdateAlumni(ctx, id, &req, meta)
+	case domain.AuditEntityMahasiswa:
+		var req domain.UpdateMahasiswaRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			return nil, err
+		}
+		return u.mahasiswaUsecase.UpdateMahasiswa(ctx, id, &req, meta)
+	case domain.AuditEntityPekerjaan:
+		// Tanggal tersimpan sebagai timestamp JSON, sedangkan request memakai YYYY-MM-DD
+		var p domain.Pekerjaan
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, err
+		}
+		req := domain.UpdatePekerjaanRequest{
+			NamaPerusahaan:     p.NamaPerusahaan,
+			PosisiJabatan:      p.PosisiJabatan,
+			BidangIndustri:     p.BidangIndustri,
+			LokasiKerja:        p.LokasiKerja,
+			GajiRange:          p.GajiRange,
+			TanggalMulaiKerja:  p.TanggalMulaiKerja.Format("2006-01-02"),
+			StatusPekerjaan:    p.StatusPekerjaan,
+			DeskripsiPekerjaan: p.DeskripsiPekerjaan,
+		}
+		if p.TanggalSelesaiKerja != nil {
+			tglSelesai := p.TanggalSelesaiKerja.Format("2006-01-02")
+			req.TanggalSelesaiKerja = &tglSelesai
+		}
+		return u.pekerjaanUsecase.UpdatePekerjaan(ctx, id, &req, meta)
+	default:
+		return nil, fmt.Errorf("unsupported entity type %q", entityType)
+	}
+}
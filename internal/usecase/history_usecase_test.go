@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var historyCreatedAt = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// auditEntry membuat audit log dengan before/after berupa JSON; string kosong berarti NULL
+func auditEntry(id int64, entityType string, entityID int, action, before, after string) domain.AuditLog {
+	l := domain.AuditLog{
+		ID:         id,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		CreatedAt:  historyCreatedAt.Add(time.Duration(id) * time.Hour),
+	}
+	if before != "" {
+		l.Before = json.RawMessage(before)
+	}
+	if after != "" {
+		l.After = json.RawMessage(after)
+	}
+	return l
+}
+
+func newTestHistoryUsecase(logs []domain.AuditLog, alumni []*domain.Alumni, pekerjaan []*domain.Pekerjaan) (*historyUsecase, *fakePekerjaanRepo) {
+	ar := newFakeAlumniRepo(alumni...)
+	pr := newFakePekerjaanRepo(pekerjaan...)
+	u := NewHistoryUsecase(&fakeAuditRepo{logs: logs},
+		NewAlumniUsecase(ar, newFakeUserRepo(), AlumniImportOptions{}, ExportOptions{}), nil,
+		NewPekerjaanUsecase(pr, ExportOptions{})).(*historyUsecase)
+	return u, pr
+}
+
+func historyAlumni() *domain.Alumni {
+	userID := 7
+	return &domain.Alumni{ID: 10, NIM: "A10", Nama: "Budi Santoso", Jurusan: "TI", Angkatan: 2018, TahunLulus: 2022,
+		Email: "budi@new.com", UserID: &userID, CreatedAt: historyCreatedAt}
+}
+
+func TestGetHistory(t *testing.T) {
+	const e = domain.AuditEntityAlumni
+	tests := []struct {
+		name    string
+		logs    []domain.AuditLog
+		actions []string
+		nama    []string
+		changed [][]string
+	}{
+		{
+			name:    "baseline only",
+			logs:    nil,
+			actions: []string{domain.HistoryActionBaseline},
+			nama:    []string{"Budi Santoso"},
+			changed: [][]string{{}},
+		},
+		{
+			name: "baseline then update",
+			logs: []domain.AuditLog{
+				auditEntry(1, e, 10, domain.AuditActionUpdate, `{"nama":"Budi"}`, `{"nama":"Budi Santoso"}`),
+			},
+			actions: []string{domain.HistoryActionBaseline, domain.AuditActionUpdate},
+			nama:    []string{"Budi", "Budi Santoso"},
+			changed: [][]string{{}, {"nama"}},
+		},
+		{
+			name: "create, updates and link",
+			logs: []domain.AuditLog{
+				auditEntry(1, e, 10, domain.AuditActionCreate, "", `{"nama":"Budi","email":"budi@old.com"}`),
+				auditEntry(2, e, 10, domain.AuditActionUpdate, `{"nama":"Budi"}`, `{"nama":"Budi S"}`),
+				auditEntry(3, e, 10, domain.AuditActionUpdate, `{"nama":"Budi S","email":"budi@old.com"}`, `{"nama":"Budi Santoso","email":"budi@new.com"}`),
+				auditEntry(4, e, 10, domain.AuditActionLink, `{"user_id":null}`, `{"user_id":7}`),
+				auditEntry(5, e, 11, domain.AuditActionUpdate, `{"nama":"Lain"}`, `{"nama":"Lainnya"}`),
+			},
+			actions: []string{domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionUpdate, domain.AuditActionLink},
+			nama:    []string{"Budi", "Budi S", "Budi Santoso", "Budi Santoso"},
+			changed: [][]string{{"email", "nama"}, {"nama"}, {"email", "nama"}, {"user_id"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestHistoryUsecase(tt.logs, []*domain.Alumni{historyAlumni()}, nil)
+			versions, err := u.GetHistory(context.Background(), e, 10)
+			if err != nil {
+				t.Fatalf("GetHistory() error = %v", err)
+			}
+			if len(versions) != len(tt.actions) {
+				t.Fatalf("got %d versions, want %d", len(versions), len(tt.actions))
+			}
+			for i, v := range versions {
+				if v.Version != i+1 {
+					t.Errorf("versions[%d].Version = %d, want %d", i, v.Version, i+1)
+				}
+				if v.Action != tt.actions[i] {
+					t.Errorf("versions[%d].Action = %q, want %q", i, v.Action, tt.actions[i])
+				}
+				if v.Data["nama"] != tt.nama[i] {
+					t.Errorf("versions[%d].Data[nama] = %v, want %q", i, v.Data["nama"], tt.nama[i])
+				}
+				if !reflect.DeepEqual(v.ChangedFields, tt.changed[i]) {
+					t.Errorf("versions[%d].ChangedFields = %v, want %v", i, v.ChangedFields, tt.changed[i])
+				}
+				if _, ok := v.Data["created_at"]; ok {
+					t.Errorf("versions[%d].Data contains created_at", i)
+				}
+			}
+			if first := versions[0]; first.Action == domain.HistoryActionBaseline {
+				if first.AuditLogID != nil || !first.CreatedAt.Equal(historyCreatedAt) {
+					t.Errorf("baseline = %+v, want no audit log and the entity's created_at", first)
+				}
+			}
+		})
+	}
+}
+
+func TestGetHistoryLinkAction(t *testing.T) {
+	const e = domain.AuditEntityAlumni
+	logs := []domain.AuditLog{
+		auditEntry(1, e, 10, domain.AuditActionCreate, "", `{"nama":"Budi Santoso"}`),
+		auditEntry(2, e, 10, domain.AuditActionLink, `{"user_id":null}`, `{"user_id":7}`),
+	}
+	u, _ := newTestHistoryUsecase(logs, []*domain.Alumni{historyAlumni()}, nil)
+
+	versions, err := u.GetHistory(context.Background(), e, 10)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("got %d versions, want 2", len(versions))
+	}
+	if versions[0].Data["user_id"] != nil {
+		t.Errorf("before link user_id = %v, want nil", versions[0].Data["user_id"])
+	}
+	if versions[1].Data["user_id"] != float64(7) {
+		t.Errorf("after link user_id = %v, want 7", versions[1].Data["user_id"])
+	}
+	if versions[1].AuditLogID == nil || *versions[1].AuditLogID != 2 {
+		t.Errorf("link version AuditLogID = %v, want 2", versions[1].AuditLogID)
+	}
+}
+
+func TestDiffVersions(t *testing.T) {
+	const e = domain.AuditEntityAlumni
+	logs := []domain.AuditLog{
+		auditEntry(1, e, 10, domain.AuditActionCreate, "", `{"nama":"Budi"}`),
+		auditEntry(2, e, 10, domain.AuditActionUpdate, `{"nama":"Budi"}`, `{"nama":"Budi S"}`),
+		auditEntry(3, e, 10, domain.AuditActionUpdate, `{"nama":"Budi S","email":"budi@old.com"}`, `{"nama":"Budi Santoso","email":"budi@new.com"}`),
+		auditEntry(4, e, 10, domain.AuditActionLink, `{"user_id":null}`, `{"user_id":7}`),
+	}
+	u, _ := newTestHistoryUsecase(logs, []*domain.Alumni{historyAlumni()}, nil)
+
+	tests := []struct {
+		name     string
+		from, to int
+		want     []domain.FieldChange
+		wantErr  error
+	}{
+		{
+			name: "non-adjacent versions",
+			from: 1, to: 3,
+			want: []domain.FieldChange{
+				{Field: "email", From: "budi@old.com", To: "budi@new.com"},
+				{Field: "nama", From: "Budi", To: "Budi Santoso"},
+			},
+		},
+		{
+			name: "first to last",
+			from: 1, to: 4,
+			want: []domain.FieldChange{
+				{Field: "email", From: "budi@old.com", To: "budi@new.com"},
+				{Field: "nama", From: "Budi", To: "Budi Santoso"},
+				{Field: "user_id", From: nil, To: float64(7)},
+			},
+		},
+		{
+			name: "reversed",
+			from: 3, to: 2,
+			want: []domain.FieldChange{
+				{Field: "email", From: "budi@new.com", To: "budi@old.com"},
+				{Field: "nama", From: "Budi Santoso", To: "Budi S"},
+			},
+		},
+		{name: "same version", from: 2, to: 2, want: []domain.FieldChange{}},
+		{name: "unknown version", from: 1, to: 5, wantErr: domain.ErrVersionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := u.DiffVersions(context.Background(), e, 10, tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DiffVersions() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(diff.Changes, tt.want) {
+				t.Errorf("DiffVersions() changes = %+v, want %+v", diff.Changes, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoreVersionPekerjaanDates(t *testing.T) {
+	const e = domain.AuditEntityPekerjaan
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	selesai := date(2024, 6, 30)
+	current := func() *domain.Pekerjaan {
+		end := selesai
+		return &domain.Pekerjaan{ID: 100, AlumniID: 10, NamaPerusahaan: "PT A", PosisiJabatan: "Engineer",
+			TanggalMulaiKerja: date(2022, 3, 1), TanggalSelesaiKerja: &end, StatusPekerjaan: "selesai", CreatedAt: historyCreatedAt}
+	}
+	logs := []domain.AuditLog{
+		auditEntry(1, e, 100, domain.AuditActionCreate, "", `{"nama_perusahaan":"PT A"}`),
+		auditEntry(2, e, 100, domain.AuditActionUpdate,
+			`{"tanggal_mulai_kerja":"2022-01-10T00:00:00Z","tanggal_selesai_kerja":null,"status_pekerjaan":"aktif"}`,
+			`{"tanggal_mulai_kerja":"2022-03-01T00:00:00Z","tanggal_selesai_kerja":"2024-06-30T00:00:00Z","status_pekerjaan":"selesai"}`),
+	}
+
+	tests := []struct {
+		name        string
+		version     int
+		wantMulai   time.Time
+		wantSelesai *time.Time
+		wantStatus  string
+	}{
+		{name: "version without end date", version: 1, wantMulai: date(2022, 1, 10), wantStatus: "aktif"},
+		{name: "version with end date", version: 2, wantMulai: date(2022, 3, 1), wantSelesai: &selesai, wantStatus: "selesai"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, pr := newTestHistoryUsecase(logs, nil, []*domain.Pekerjaan{current()})
+			if _, err := u.RestoreVersion(context.Background(), e, 100, tt.version, domain.AuditMeta{}); err != nil {
+				t.Fatalf("RestoreVersion() error = %v", err)
+			}
+			got := pr.pekerjaan[100]
+			if !got.TanggalMulaiKerja.Equal(tt.wantMulai) {
+				t.Errorf("TanggalMulaiKerja = %v, want %v", got.TanggalMulaiKerja, tt.wantMulai)
+			}
+			switch {
+			case tt.wantSelesai == nil && got.TanggalSelesaiKerja != nil:
+				t.Errorf("TanggalSelesaiKerja = %v, want nil", *got.TanggalSelesaiKerja)
+			case tt.wantSelesai != nil && (got.TanggalSelesaiKerja == nil || !got.TanggalSelesaiKerja.Equal(*tt.wantSelesai)):
+				t.Errorf("TanggalSelesaiKerja = %v, want %v", got.TanggalSelesaiKerja, *tt.wantSelesai)
+			}
+			if got.StatusPekerjaan != tt.wantStatus || got.NamaPerusahaan != "PT A" {
+				t.Errorf("restored pekerjaan = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/utils"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Impersonate membuat session khusus milik user yang ditiru agar bisa dicabut seperti session biasa
+// (termasuk oleh user itu sendiri), lalu menerbitkan access token tanpa refresh token.
+func (u *authUsecase) Impersonate(ctx context.Context, actorID, userID int, client domain.ClientInfo) (*domain.ImpersonationResponse, error) {
+	actor, err := u.userRepo.GetUserByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.HasRole(domain.RoleAdmin) {
+		return nil, domain.ErrImpersonationDenied
+	}
+
+	if actorID == userID {
+		return nil, domain.ErrCannotImpersonate
+	}
+	target, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Admin lain tidak bisa ditiru agar impersonation tidak bisa dipakai untuk bertindak sebagai admin
+	if !target.IsActive || target.HasRole(domain.RoleAdmin) {
+		return nil, domain.ErrCannotImpersonate
+	}
+
+	sessionID, err := utils.GenerateRandomToken(24)
+	if err != nil {
+		return nil, err
+	}
+	// Refresh token tetap dibuat karena setiap session membutuhkannya, tetapi tidak pernah diberikan
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(u.opts.ImpersonationTTL)
+	session := &domain.Session{
+		ID:             sessionID,
+		UserID:         target.ID,
+		UserAgent:      client.UserAgent,
+		IPAddress:      client.IPAddress,
+		ExpiresAt:      expiresAt,
+		ImpersonatorID: &actor.ID,
+	}
+	if err := u.sessionRepo.Create(ctx, session, utils.HashToken(refreshToken), expiresAt); err != nil {
+		return nil, err
+	}
+
+	token, err := u.generateImpersonationToken(actor, target, sessionID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	u.recordAuthEvent(ctx, &domain.AuthEvent{
+		EventType: "impersonation_started",
+		UserID:    &target.ID,
+		ActorID:   &actor.ID,
+		Email:     &target.Email,
+		IPAddress: optionalString(client.IPAddress),
+		Detail:    optionalString(fmt.Sprintf("session %s until %s", sessionID, expiresAt.UTC().Format(time.RFC3339))),
+	})
+
+	return &domain.ImpersonationResponse{
+		Token:     token,
+		ExpiresIn: int64(u.opts.ImpersonationTTL.Seconds()),
+		User:      target,
+	}, nil
+}
+
+// generateImpersonationToken memakai identitas user yang ditiru dan claim act (RFC 8693) untuk admin.
+// Hanya permission di domain.ImpersonationPermissions yang dibawa, apa pun role user yang ditiru.
+func (u *authUsecase) generateImpersonationToken(actor, target *domain.User, sessionID string, expiresAt time.Time) (string, error) {
+	permissions := make([]string, 0, len(target.Permissions))
+	for _, p := range target.Permissions {
+		if containsPermission(domain.ImpersonationPermissions, p) {
+			permissions = append(permissions, p)
+		}
+	}
+
+	claims := jwt.MapClaims{
+		"user_id":     target.ID,
+		"email":       target.Email,
+		"roles":       target.Roles,
+		"permissions": permissions,
+		"jti":         sessionID,
+		"act": map[string]interface{}{
+			"user_id": actor.ID,
+			"email":   actor.Email,
+		},
+		"iat": time.Now().Unix(),
+		"exp": expiresAt.Unix(),
+	}
+	return u.opts.Keys.Sign(claims)
+}
+
+func (u *authUsecase) RecordImpersonatedRequest(ctx context.Context, actorID, userID int, method, path string, status int, client domain.ClientInfo) {
+	u.recordAuthEvent(ctx, &domain.AuthEvent{
+		EventType: "impersonated_request",
+		UserID:    &userID,
+		ActorID:   &actorID,
+		IPAddress: optionalString(client.IPAddress),
+		Detail:    optionalString(fmt.Sprintf("%s %s -> %d", method, path, status)),
+	})
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/jwtkeys"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// impersonationPermissions menerbitkan token impersonation untuk target dan membaca claim permissions-nya
+func impersonationPermissions(t *testing.T, target *domain.User) map[string]bool {
+	t.Helper()
+	keys := jwtkeys.NewHMACKeySet("test", "test-secret")
+	u := &authUsecase{opts: AuthOptions{Keys: keys}}
+	actor := &domain.User{ID: 1, Email: "admin@example.com", Roles: []string{domain.RoleAdmin}}
+
+	signed, err := u.generateImpersonationToken(actor, target, "s1", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Parse(signed, keys.Keyfunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	if claims["user_id"].(float64) != float64(target.ID) {
+		t.Fatalf("user_id = %v, want %d", claims["user_id"], target.ID)
+	}
+	if act := claims["act"].(map[string]interface{}); act["user_id"].(float64) != 1 {
+		t.Fatalf("act = %v, want admin actor", act)
+	}
+
+	granted := map[string]bool{}
+	for _, p := range claims["permissions"].([]interface{}) {
+		granted[p.(string)] = true
+	}
+	return granted
+}
+
+func TestImpersonationTokenCarriesOnlyAllowedPermissions(t *testing.T) {
+	userPermissions := []string{domain.PermAlumniRead, domain.PermPekerjaanRead, domain.PermPekerjaanWrite}
+	// Permission yang tidak ada di allowlist, termasuk yang belum dikenal, harus ikut dibuang
+	stripped := []string{
+		domain.PermUsersManage, domain.PermRolesManage, domain.PermAuditRead,
+		domain.PermJobsManage, domain.PermTracerManage, "reports:export",
+	}
+	target := &domain.User{
+		ID:          2,
+		Email:       "target@example.com",
+		Roles:       []string{domain.RoleUser, "operator"},
+		Permissions: append(append([]string{}, userPermissions...), stripped...),
+	}
+
+	granted := impersonationPermissions(t, target)
+	for _, p := range stripped {
+		if granted[p] {
+			t.Errorf("permission %s present in impersonation token", p)
+		}
+	}
+	for _, p := range userPermissions {
+		if !granted[p] {
+			t.Errorf("permission %s of the impersonated user was dropped", p)
+		}
+	}
+	if len(granted) != len(userPermissions) {
+		t.Errorf("granted = %v, want only %v", granted, userPermissions)
+	}
+}
+
+func TestImpersonateRequiresAdminRole(t *testing.T) {
+	// Permission users:manage saja (misalnya lewat role kustom) tidak cukup untuk impersonation
+	ur := newFakeUserRepo(
+		&domain.User{ID: 1, Email: "manager@example.com", Roles: []string{"manager"}, Permissions: []string{domain.PermUsersManage}, IsActive: true},
+		&domain.User{ID: 2, Email: "admin@example.com", Roles: []string{domain.RoleAdmin}, IsActive: true},
+		&domain.User{ID: 3, Email: "user@example.com", Roles: []string{domain.RoleUser}, IsActive: true},
+		&domain.User{ID: 4, Email: "admin2@example.com", Roles: []string{domain.RoleUser, domain.RoleAdmin}, IsActive: true},
+	)
+	u := newTestAuthUsecase(ur, newFakeSessionRepo(), AuthOptions{ImpersonationTTL: time.Minute})
+	u.authEventRepo = &fakeAuthEventRepo{}
+
+	if _, err := u.Impersonate(context.Background(), 1, 3, domain.ClientInfo{}); !errors.Is(err, domain.ErrImpersonationDenied) {
+		t.Fatalf("non-admin actor: err = %v, want ErrImpersonationDenied", err)
+	}
+	if _, err := u.Impersonate(context.Background(), 2, 1, domain.ClientInfo{}); err != nil {
+		t.Fatalf("admin impersonating manager: %v", err)
+	}
+	if _, err := u.Impersonate(context.Background(), 2, 4, domain.ClientInfo{}); !errors.Is(err, domain.ErrCannotImpersonate) {
+		t.Fatalf("admin targeting admin: err = %v, want ErrCannotImpersonate", err)
+	}
+}
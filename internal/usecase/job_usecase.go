@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"back-train/pkg/spreadsheet"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// JobOptions mengatur antrian job; dipakai bersama oleh JobUsecase (API) dan JobWorker
+type JobOptions struct {
+	ArtifactDir  string // file upload dan hasil job, harus sama untuk API dan worker
+	MaxAttempts  int
+	Workers      int
+	PollInterval time.Duration
+	Lease        time.Duration
+	RetryBase    time.Duration
+	RetryMax     time.Duration
+	Retention    time.Duration // 0 = job selesai tidak pernah dihapus
+}
+
+type jobUsecase struct {
+	jobRepo          repository.JobRepository
+	alumniUsecase    AlumniUsecase
+	pekerjaanUsecase PekerjaanUsecase
+	opts             JobOptions
+}
+
+func NewJobUsecase(jr repository.JobRepository, au AlumniUsecase, pu PekerjaanUsecase, opts JobOptions) JobUsecase {
+	return &jobUsecase{jobRepo: jr, alumniUsecase: au, pekerjaanUsecase: pu, opts: opts}
+}
+
+type alumniImportJobPayload struct {
+	Filename string `json:"filename"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// exportJobPayload menyimpan parameter export agar worker bisa menyusun export yang sama
+type exportJobPayload struct {
+	Format  string            `json:"format"`
+	Columns []string          `json:"columns,omitempty"`
+	Search  string            `json:"search,omitempty"`
+	Sort    string            `json:"sort,omitempty"`
+	Filters map[string]string `json:"filters,omitempty"`
+}
+
+// exportFile menyusun export sesuai jenis job; format dan kolom divalidasi di sini
+func (u *jobUsecase) exportFile(ctx context.Context, jobType string, p exportJobPayload) (*ExportFile, error) {
+	params := domain.PaginationParams{Sort: p.Sort, Search: p.Search, Filters: p.Filters}
+	if jobType == domain.JobTypePekerjaanExport {
+		return u.pekerjaanUsecase.ExportPekerjaan(ctx, params, p.Format, p.Columns)
+	}
+	return u.alumniUsecase.ExportAlumni(ctx, params, p.Format, p.Columns)
+}
+
+func (u *jobUsecase) enqueue(ctx context.Context, jobType string, payload interface{}, inputPath *string, meta domain.AuditMeta) (*domain.Job, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return u.jobRepo.Create(ctx, &domain.Job{
+		Type:           jobType,
+		Payload:        raw,
+		InputPath:      inputPath,
+		MaxAttempts:    u.opts.MaxAttempts,
+		CreatedBy:      meta.ActorUserID,
+		ImpersonatorID: meta.ImpersonatorID,
+		RequestID:      optionalString(meta.RequestID),
+		IPAddress:      optionalString(meta.IPAddress),
+	})
+}
+
+// EnqueueAlumniImport menyimpan file upload ke ArtifactDir lalu memasukkan job import ke antrian.
+// Format file dicek sekarang agar kesalahan yang pasti gagal langsung dilaporkan.
+func (u *jobUsecase) EnqueueAlumniImport(ctx context.Context, filename string, r io.Reader, dryRun bool, meta domain.AuditMeta) (*domain.Job, error) {
+	format, err := spreadsheet.FormatFromFilename(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(u.opts.ArtifactDir, 0o750); err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(u.opts.ArtifactDir, "input-*."+format)
+	if err != nil {
+		return nil, err
+	}
+	inputPath := file.Name()
+	_, err = io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		removeJobFile(&inputPath)
+		return nil, err
+	}
+
+	job, err := u.enqueue(ctx, domain.JobTypeAlumniImport, alumniImportJobPayload{Filename: filename, DryRun: dryRun}, &inputPath, meta)
+	if err != nil {
+		removeJobFile(&inputPath)
+		return nil, err
+	}
+	return job, nil
+}
+
+func (u *jobUsecase) EnqueueAlumniExport(ctx context.Context, params domain.PaginationParams, format string, columns []string, meta domain.AuditMeta) (*domain.Job, error) {
+	return u.enqueueExport(ctx, domain.JobTypeAlumniExport, params, format, columns, meta)
+}
+
+func (u *jobUsecase) EnqueuePekerjaanExport(ctx context.Context, params domain.PaginationParams, format string, columns []string, meta domain.AuditMeta) (*domain.Job, error) {
+	return u.enqueueExport(ctx, domain.JobTypePekerjaanExport, params, format, columns, meta)
+}
+
+func (u *jobUsecase) enqueueExport(ctx context.Context, jobType string, params domain.PaginationParams, format string, columns []string, meta domain.AuditMeta) (*domain.Job, error) {
+	payload := exportJobPayload{Format: format, Columns: columns, Search: params.Search, Sort: params.Sort, Filters: params.Filters}
+	if _, err := u.exportFile(ctx, jobType, payload); err != nil {
+		return nil, err
+	}
+	return u.enqueue(ctx, jobType, payload, nil, meta)
+}
+
+// GetJob hanya mengembalikan job milik userID kecuali manageAll; job user lain dianggap tidak ada
+func (u *jobUsecase) GetJob(ctx context.Context, id int64, userID int, manageAll bool) (*domain.Job, error) {
+	job, err := u.jobRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !manageAll && (job.CreatedBy == nil || *job.CreatedBy != userID) {
+		return nil, domain.ErrJobNotFound
+	}
+	return job, nil
+}
+
+func (u *jobUsecase) GetJobs(ctx context.Context, filter domain.JobFilter) (*domain.PaginationResult[domain.Job], error) {
+	switch filter.Status {
+	case "", domain.JobStatusQueued, domain.JobStatusRunning, domain.JobStatusSucceeded, domain.JobStatusFailed, domain.JobStatusCancelled:
+	default:
+		return nil, domain.ErrInvalidJobFilter
+	}
+	return u.jobRepo.FindAll(ctx, filter)
+}
+
+func (u *jobUsecase) CancelJob(ctx context.Context, id int64, userID int, manageAll bool) (*domain.Job, error) {
+	if _, err := u.GetJob(ctx, id, userID, manageAll); err != nil {
+		return nil, err
+	}
+	job, err := u.jobRepo.RequestCancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Job yang belum sempat diambil worker tidak akan menghapus file input-nya sendiri
+	if job.Status == domain.JobStatusCancelled {
+		removeJobFile(job.InputPath)
+	}
+	return job, nil
+}
+
+// GetJobArtifact mengembalikan job yang file hasilnya masih tersedia di disk
+func (u *jobUsecase) GetJobArtifact(ctx context.Context, id int64, userID int, manageAll bool) (*domain.Job, error) {
+	job, err := u.GetJob(ctx, id, userID, manageAll)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.JobStatusSucceeded || job.ArtifactPath == nil {
+		return nil, domain.ErrJobNoArtifact
+	}
+	if _, err := os.Stat(*job.ArtifactPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, domain.ErrJobNoArtifact
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func removeJobFile(path *string) {
+	if path == nil {
+		return
+	}
+	if err := os.Remove(*path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("failed to remove job file %s: %v", *path, err)
+	}
+}
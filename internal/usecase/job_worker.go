@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"back-train/pkg/spreadsheet"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// jobCleanupInterval adalah jarak antar penghapusan job lama
+const jobCleanupInterval = time.Hour
+
+var errInvalidJobPayload = errors.New("invalid job payload")
+
+// jobProgress adalah progress job yang sedang berjalan, disimpan ke database saat heartbeat
+type jobProgress struct {
+	done  atomic.Int64
+	total atomic.Int64
+}
+
+type jobProgressKey struct{}
+
+// reportJobProgress dipanggil oleh usecase yang bisa berjalan sebagai job (import/export).
+// Di luar job tidak melakukan apa-apa; total 0 berarti total tidak berubah.
+func reportJobProgress(ctx context.Context, done, total int) {
+	p, ok := ctx.Value(jobProgressKey{}).(*jobProgress)
+	if !ok {
+		return
+	}
+	p.done.Store(int64(done))
+	if total > 0 {
+		p.total.Store(int64(total))
+	}
+}
+
+// jobRetryable bernilai false untuk kesalahan input yang akan tetap gagal walaupun diulang
+func jobRetryable(err error) bool {
+	switch {
+	case errors.Is(err, errInvalidJobPayload), errors.Is(err, os.ErrNotExist),
+		errors.Is(err, spreadsheet.ErrUnsupportedFormat), errors.Is(err, domain.ErrImportUnreadable),
+		errors.Is(err, domain.ErrImportEmpty), errors.Is(err, domain.ErrImportMissingColumn),
+		errors.Is(err, domain.ErrImportTooManyRows), errors.Is(err, domain.ErrInvalidExportFormat),
+		errors.Is(err, domain.ErrInvalidExportColumn), errors.Is(err, domain.ErrInvalidFilter):
+		return false
+	default:
+		return true
+	}
+}
+
+// JobWorker menjalankan job dari antrian. Beberapa proses (API dan/atau cmd/worker) boleh berjalan
+// bersamaan karena setiap job hanya bisa diambil satu worker.
+type JobWorker struct {
+	jobRepo  repository.JobRepository
+	jobs     *jobUsecase
+	opts     JobOptions
+	id       string
+	handlers map[string]func(ctx context.Context, job *domain.Job) error
+}
+
+func NewJobWorker(jr repository.JobRepository, au AlumniUsecase, pu PekerjaanUsecase, opts JobOptions) *JobWorker {
+	hostname, _ := os.Hostname()
+	w := &JobWorker{
+		jobRepo: jr,
+		jobs:    &jobUsecase{jobRepo: jr, alumniUsecase: au, pekerjaanUsecase: pu, opts: opts},
+		opts:    opts,
+		id:      fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano()%100000),
+	}
+	w.handlers = map[string]func(ctx context.Context, job *domain.Job) error{
+		domain.JobTypeAlumniImport:    w.runAlumniImport,
+		domain.JobTypeAlumniExport:    w.runExport,
+		domain.JobTypePekerjaanExport: w.runExport,
+	}
+	return w
+}
+
+// Run menjalankan opts.Workers goroutine sampai ctx dibatalkan. Job yang sedang berjalan saat itu
+// dihentikan dan dikembalikan ke antrian.
+func (w *JobWorker) Run(ctx context.Context) {
+	workers := max(w.opts.Workers, 1)
+	log.Printf("job worker %s started with %d worker(s)", w.id, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		// ID per goroutine agar lease yang diambil alih goroutine lain tetap terdeteksi
+		workerID := fmt.Sprintf("%s-%d", w.id, i+1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx, workerID)
+		}()
+	}
+	if w.opts.Retention > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.cleanupLoop(ctx)
+		}()
+	}
+	wg.Wait()
+	log.Printf("job worker %s stopped", w.id)
+}
+
+func (w *JobWorker) loop(ctx context.Context, workerID string) {
+	for {
+		job, err := w.jobRepo.Claim(ctx, workerID, w.opts.Lease)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("job worker %s: claim failed: %v", workerID, err)
+		}
+		if job != nil {
+			w.process(ctx, workerID, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.opts.PollInterval):
+		}
+	}
+}
+
+func (w *JobWorker) process(ctx context.Context, workerID string, job *domain.Job) {
+	// Pembatalan yang diminta saat job ditinggal worker sebelumnya atau dikembalikan ke antrian
+	// langsung dituntaskan tanpa menjalankan job lagi
+	if job.CancelRequested {
+		w.finish(workerID, job, domain.JobStatusCancelled, "")
+		return
+	}
+	// Lease yang habis (worker mati di tengah job) juga dihitung sebagai percobaan
+	if job.Attempts > job.MaxAttempts {
+		w.finish(workerID, job, domain.JobStatusFailed, "job was interrupted too many times")
+		return
+	}
+	run, ok := w.handlers[job.Type]
+	if !ok {
+		w.finish(workerID, job, domain.JobStatusFailed, "unknown job type "+job.Type)
+		return
+	}
+
+	progress := &jobProgress{}
+	progress.done.Store(int64(job.ProgressDone))
+	progress.total.Store(int64(job.ProgressTotal))
+	jobCtx, cancel := context.WithCancel(context.WithValue(ctx, jobProgressKey{}, progress))
+	defer cancel()
+
+	var cancelled, lost atomic.Bool
+	stop := make(chan struct{})
+	var heartbeat sync.WaitGroup
+	heartbeat.Add(1)
+	go func() {
+		defer heartbeat.Done()
+		ticker := time.NewTicker(min(w.opts.Lease/3, 2*time.Second))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			cancelRequested, err := w.jobRepo.Heartbeat(context.Background(), job.ID, workerID,
+				int(progress.done.Load()), int(progress.total.Load()), w.opts.Lease)
+			switch {
+			case errors.Is(err, domain.ErrJobLost):
+				lost.Store(true)
+				cancel()
+				return
+			case err != nil:
+				log.Printf("job %d: heartbeat failed: %v", job.ID, err)
+			case cancelRequested:
+				cancelled.Store(true)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	err := run(jobCtx, job)
+	close(stop)
+	heartbeat.Wait()
+
+	// Context worker mungkin sudah dibatalkan, status job tetap harus disimpan
+	db := context.Background()
+	switch {
+	case lost.Load():
+		log.Printf("job %d was taken over by another worker, result discarded", job.ID)
+		removeJobFile(job.ArtifactPath)
+	case err == nil:
+		// Job yang sempat selesai tetap dicatat berhasil walaupun pembatalan datang terlambat,
+		// karena data import sudah tersimpan
+		if err := w.jobRepo.Complete(db, job, workerID); err != nil {
+			log.Printf("job %d: failed to save result: %v", job.ID, err)
+			removeJobFile(job.ArtifactPath)
+			return
+		}
+		removeJobFile(job.InputPath)
+	case cancelled.Load():
+		w.finish(workerID, job, domain.JobStatusCancelled, "")
+	case ctx.Err() != nil:
+		// Worker berhenti: job dikembalikan ke antrian tanpa dihitung sebagai percobaan
+		if err := w.jobRepo.Release(db, job.ID, workerID); err != nil {
+			log.Printf("job %d: failed to release: %v", job.ID, err)
+		}
+	case !jobRetryable(err) || job.Attempts >= job.MaxAttempts:
+		w.finish(workerID, job, domain.JobStatusFailed, err.Error())
+	default:
+		delay := w.retryDelay(job.Attempts)
+		log.Printf("job %d attempt %d failed, retrying in %s: %v", job.ID, job.Attempts, delay, err)
+		if err := w.jobRepo.Retry(db, job.ID, workerID, err.Error(), time.Now().Add(delay)); err != nil {
+			log.Printf("job %d: failed to schedule retry: %v", job.ID, err)
+		}
+	}
+}
+
+// finish mengakhiri job (failed/cancelled) dan menghapus file input-nya
+func (w *JobWorker) finish(workerID string, job *domain.Job, status, message string) {
+	if status == domain.JobStatusFailed {
+		log.Printf("job %d failed: %s", job.ID, message)
+	}
+	if err := w.jobRepo.Finish(context.Background(), job.ID, workerID, status, optionalString(message)); err != nil {
+		log.Printf("job %d: failed to mark as %s: %v", job.ID, status, err)
+		return
+	}
+	removeJobFile(job.InputPath)
+}
+
+// retryDelay berlipat dua setiap percobaan, mulai dari RetryBase sampai RetryMax
+func (w *JobWorker) retryDelay(attempts int) time.Duration {
+	delay := w.opts.RetryBase
+	for i := 1; i < attempts && delay < w.opts.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, w.opts.RetryMax)
+}
+
+func (w *JobWorker) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(jobCleanupInterval)
+	defer ticker.Stop()
+	for {
+		paths, err := w.jobRepo.DeleteFinishedBefore(ctx, time.Now().Add(-w.opts.Retention))
+		if err != nil && ctx.Err() == nil {
+			log.Printf("job worker %s: cleanup failed: %v", w.id, err)
+		}
+		for i := range paths {
+			removeJobFile(&paths[i])
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *JobWorker) runAlumniImport(ctx context.Context, job *domain.Job) error {
+	var payload alumniImportJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil || job.InputPath == nil {
+		return errInvalidJobPayload
+	}
+	file, err := os.Open(*job.InputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := w.jobs.alumniUsecase.ImportAlumni(ctx, payload.Filename, file, payload.DryRun, job.AuditMeta())
+	if err != nil {
+		return err
+	}
+	job.Result, err = json.Marshal(report)
+	return err
+}
+
+// runExport menulis file export ke ArtifactDir; file yang belum selesai dihapus jika gagal
+func (w *JobWorker) runExport(ctx context.Context, job *domain.Job) error {
+	var payload exportJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return errInvalidJobPayload
+	}
+	export, err := w.jobs.exportFile(ctx, job.Type, payload)
+	if err != nil {
+		return err
+	}
+	total, err := export.count(ctx)
+	if err != nil {
+		return err
+	}
+	reportJobProgress(ctx, 0, int(total))
+
+	if err := os.MkdirAll(w.opts.ArtifactDir, 0o750); err != nil {
+		return err
+	}
+	path := filepath.Join(w.opts.ArtifactDir, fmt.Sprintf("job-%d-%s", job.ID, export.Filename))
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	rows, err := export.write(ctx, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		removeJobFile(&path)
+		return err
+	}
+
+	job.Result, err = json.Marshal(map[string]int{"rows": rows})
+	if err != nil {
+		removeJobFile(&path)
+		return err
+	}
+	job.ArtifactPath = &path
+	job.ArtifactName = &export.Filename
+	job.ArtifactContentType = &export.ContentType
+	return nil
+}
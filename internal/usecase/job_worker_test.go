@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestProcessCancelRequestedJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		job        domain.Job
+		wantRun    bool
+		wantStatus string
+	}{
+		{"normal job runs", domain.Job{ID: 1, Type: "test", Attempts: 1, MaxAttempts: 3}, true, domain.JobStatusSucceeded},
+		// Job running yang lease-nya habis setelah dibatalkan diambil lagi oleh Claim
+		{"cancelled job is not run again", domain.Job{ID: 2, Type: "test", Attempts: 2, MaxAttempts: 3, CancelRequested: true}, false, domain.JobStatusCancelled},
+		{"cancel wins over too many attempts", domain.Job{ID: 3, Type: "test", Attempts: 4, MaxAttempts: 3, CancelRequested: true}, false, domain.JobStatusCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jr := newFakeJobRepo()
+			ran := false
+			w := &JobWorker{
+				jobRepo: jr,
+				opts:    JobOptions{Lease: time.Minute},
+				handlers: map[string]func(ctx context.Context, job *domain.Job) error{
+					"test": func(ctx context.Context, job *domain.Job) error {
+						ran = true
+						return nil
+					},
+				},
+			}
+
+			job := tt.job
+			w.process(context.Background(), "w1", &job)
+			if ran != tt.wantRun {
+				t.Errorf("handler ran = %v, want %v", ran, tt.wantRun)
+			}
+			if got := jr.finished[job.ID]; got != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	lockScopeAccount = "account"
+	lockScopeIP      = "ip"
+)
+
+// LockoutPolicy mengatur kapan akun atau IP dikunci setelah gagal login berulang
+type LockoutPolicy struct {
+	MaxAccountFailures int           // gagal berturut-turut per email sebelum akun dikunci
+	MaxIPFailures      int           // gagal per alamat IP sebelum IP dikunci
+	FailureWindow      time.Duration // penghitung dimulai ulang jika tidak ada kegagalan selama window ini
+	BaseLockout        time.Duration // lama lock pertama, berlipat dua untuk setiap kegagalan berikutnya
+	MaxLockout         time.Duration
+}
+
+// lockoutDuration menghitung backoff eksponensial: base, 2x base, 4x base, ... dibatasi MaxLockout
+func (p LockoutPolicy) lockoutDuration(failures, max int) time.Duration {
+	d := p.BaseLockout
+	for i := max; i < failures && d < p.MaxLockout; i++ {
+		d *= 2
+	}
+	if d > p.MaxLockout {
+		d = p.MaxLockout
+	}
+	return d
+}
+
+// accountLockKey menyamakan penulisan email agar variasi huruf besar tidak memberi percobaan tambahan
+func accountLockKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// checkLockout dijalankan sebelum password dicek agar percobaan saat terkunci tidak memakan CPU bcrypt
+func (u *authUsecase) checkLockout(ctx context.Context, email, ip string) error {
+	checks := [][2]string{{lockScopeAccount, accountLockKey(email)}}
+	if ip != "" {
+		checks = append(checks, [2]string{lockScopeIP, ip})
+	}
+
+	for _, check := range checks {
+		until, err := u.loginFailureRepo.LockedUntil(ctx, check[0], check[1])
+		if err != nil {
+			return err
+		}
+		if until != nil {
+			return &domain.LockedError{Until: *until}
+		}
+	}
+	return nil
+}
+
+// registerLoginFailure menambah penghitung akun dan IP, lalu mengunci jika batas terlampaui.
+// Error dari penyimpanan hanya di-log agar respons login tetap "invalid credentials".
+func (u *authUsecase) registerLoginFailure(ctx context.Context, userID *int, email, ip string) {
+	policy := u.opts.Lockout
+	key := accountLockKey(email)
+
+	count, err := u.loginFailureRepo.RegisterFailure(ctx, lockScopeAccount, key, policy.FailureWindow)
+	if err != nil {
+		log.Printf("failed to register login failure for %q: %v", key, err)
+	} else if policy.MaxAccountFailures > 0 && count >= policy.MaxAccountFailures {
+		u.lock(ctx, lockScopeAccount, key, policy.lockoutDuration(count, policy.MaxAccountFailures), &domain.AuthEvent{
+			EventType: "account_locked",
+			UserID:    userID,
+			Email:     &key,
+			IPAddress: optionalString(ip),
+		}, count)
+	}
+
+	if ip == "" {
+		return
+	}
+	count, err = u.loginFailureRepo.RegisterFailure(ctx, lockScopeIP, ip, policy.FailureWindow)
+	if err != nil {
+		log.Printf("failed to register login failure for ip %s: %v", ip, err)
+	} else if policy.MaxIPFailures > 0 && count >= policy.MaxIPFailures {
+		u.lock(ctx, lockScopeIP, ip, policy.lockoutDuration(count, policy.MaxIPFailures), &domain.AuthEvent{
+			EventType: "ip_locked",
+			IPAddress: &ip,
+		}, count)
+	}
+}
+
+func (u *authUsecase) lock(ctx context.Context, scope, key string, duration time.Duration, event *domain.AuthEvent, failures int) {
+	until := time.Now().Add(duration)
+	if err := u.loginFailureRepo.Lock(ctx, scope, key, until); err != nil {
+		log.Printf("failed to lock %s %q: %v", scope, key, err)
+		return
+	}
+
+	detail := fmt.Sprintf("%d failed attempts, locked until %s", failures, until.UTC().Format(time.RFC3339))
+	event.Detail = &detail
+	u.recordAuthEvent(ctx, event)
+}
+
+// clearLoginFailures dipanggil setelah login berhasil. Penghitung IP sengaja tidak di-reset
+// agar penyerang tidak bisa menghapusnya dengan sesekali login ke akunnya sendiri.
+func (u *authUsecase) clearLoginFailures(ctx context.Context, email string) {
+	if err := u.loginFailureRepo.Reset(ctx, lockScopeAccount, accountLockKey(email)); err != nil {
+		log.Printf("failed to reset login failures for %q: %v", email, err)
+	}
+}
+
+func (u *authUsecase) recordAuthEvent(ctx context.Context, event *domain.AuthEvent) {
+	if err := u.authEventRepo.Record(ctx, event); err != nil {
+		log.Printf("failed to record auth event %s: %v", event.EventType, err)
+	}
+}
+
+func (u *authUsecase) UnlockAccount(ctx context.Context, actorID, userID int) error {
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	key := accountLockKey(user.Email)
+	if err := u.loginFailureRepo.Reset(ctx, lockScopeAccount, key); err != nil {
+		return err
+	}
+
+	u.recordAuthEvent(ctx, &domain.AuthEvent{
+		EventType: "account_unlocked",
+		UserID:    &user.ID,
+		ActorID:   &actorID,
+		Email:     &key,
+	})
+	return nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
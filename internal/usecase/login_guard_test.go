@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	policy := LockoutPolicy{BaseLockout: time.Minute, MaxLockout: 30 * time.Minute}
+
+	tests := []struct {
+		name     string
+		failures int
+		max      int
+		want     time.Duration
+	}{
+		{"first lock", 5, 5, time.Minute},
+		{"second failure doubles", 6, 5, 2 * time.Minute},
+		{"third failure doubles again", 7, 5, 4 * time.Minute},
+		{"fifth failure", 9, 5, 16 * time.Minute},
+		{"capped at max", 10, 5, 30 * time.Minute},
+		{"far beyond max stays capped", 1000, 5, 30 * time.Minute},
+		{"below threshold uses base", 3, 5, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.lockoutDuration(tt.failures, tt.max); got != tt.want {
+				t.Fatalf("lockoutDuration(%d, %d) = %v, want %v", tt.failures, tt.max, got, tt.want)
+			}
+		})
+	}
+
+	// Base yang lebih besar dari max tetap dibatasi
+	capped := LockoutPolicy{BaseLockout: time.Hour, MaxLockout: 10 * time.Minute}
+	if got := capped.lockoutDuration(1, 1); got != 10*time.Minute {
+		t.Fatalf("base above max = %v, want 10m", got)
+	}
+}
+
+func TestRegisterLoginFailureLocks(t *testing.T) {
+	ctx := context.Background()
+	lfr := newFakeLoginFailureRepo()
+	aer := &fakeAuthEventRepo{}
+	u := &authUsecase{loginFailureRepo: lfr, authEventRepo: aer, opts: AuthOptions{Lockout: LockoutPolicy{
+		MaxAccountFailures: 3,
+		MaxIPFailures:      5,
+		FailureWindow:      15 * time.Minute,
+		BaseLockout:        time.Minute,
+		MaxLockout:         time.Hour,
+	}}}
+
+	for i := 0; i < 2; i++ {
+		u.registerLoginFailure(ctx, nil, "User@Example.com", "10.0.0.1")
+	}
+	if err := u.checkLockout(ctx, "user@example.com", "10.0.0.1"); err != nil {
+		t.Fatalf("locked before threshold: %v", err)
+	}
+
+	start := time.Now()
+	u.registerLoginFailure(ctx, nil, " user@example.com", "10.0.0.1")
+	var locked *domain.LockedError
+	if err := u.checkLockout(ctx, "USER@example.com", "10.0.0.2"); !errors.As(err, &locked) {
+		t.Fatalf("err = %v, want LockedError", err)
+	}
+	if d := locked.Until.Sub(start); d < time.Minute || d > time.Minute+time.Second {
+		t.Fatalf("first lock lasts %v, want 1m", d)
+	}
+	if len(aer.events) != 1 || aer.events[0].EventType != "account_locked" {
+		t.Fatalf("events = %+v, want one account_locked", aer.events)
+	}
+
+	// IP belum mencapai batasnya, jadi email lain dari IP yang sama masih boleh mencoba
+	if err := u.checkLockout(ctx, "other@example.com", "10.0.0.1"); err != nil {
+		t.Fatalf("ip locked too early: %v", err)
+	}
+	u.registerLoginFailure(ctx, nil, "other@example.com", "10.0.0.1")
+	u.registerLoginFailure(ctx, nil, "other@example.com", "10.0.0.1")
+	if err := u.checkLockout(ctx, "third@example.com", "10.0.0.1"); !errors.As(err, &locked) {
+		t.Fatalf("err = %v, want ip LockedError", err)
+	}
+
+	u.clearLoginFailures(ctx, "user@example.com")
+	if err := u.checkLockout(ctx, "user@example.com", ""); err != nil {
+		t.Fatalf("account still locked after reset: %v", err)
+	}
+}
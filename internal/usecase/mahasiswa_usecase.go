@@ -14,7 +14,7 @@ func NewMahasiswaUsecase(mr repository.MahasiswaRepository) MahasiswaUsecase {
 	return &mahasiswaUsecase{mahasiswaRepo: mr}
 }
 
-func (u *mahasiswaUsecase) CreateMahasiswa(ctx context.Context, req *domain.CreateMahasiswaRequest) (*domain.Mahasiswa, error) {
+func (u *mahasiswaUsecase) CreateMahasiswa(ctx context.Context, req *domain.CreateMahasiswaRequest, meta domain.AuditMeta) (*domain.Mahasiswa, error) {
 	mahasiswa := &domain.Mahasiswa{
 		NIM:      req.NIM,
 		Nama:     req.Nama,
@@ -22,7 +22,7 @@ func (u *mahasiswaUsecase) CreateMahasiswa(ctx context.Context, req *domain.Crea
 		Angkatan: req.Angkatan,
 		Email:    req.Email,
 	}
-	return u.mahasiswaRepo.Create(ctx, mahasiswa)
+	return u.mahasiswaRepo.Create(ctx, mahasiswa, meta)
 }
 
 func (u *mahasiswaUsecase) GetAllMahasiswa(ctx context.Context) ([]domain.Mahasiswa, error) {
@@ -33,7 +33,7 @@ func (u *mahasiswaUsecase) GetMahasiswaByID(ctx context.Context, id int) (*domai
 	return u.mahasiswaRepo.FindByID(ctx, id)
 }
 
-func (u *mahasiswaUsecase) UpdateMahasiswa(ctx context.Context, id int, req *domain.UpdateMahasiswaRequest) (*domain.Mahasiswa, error) {
+func (u *mahasiswaUsecase) UpdateMahasiswa(ctx context.Context, id int, req *domain.UpdateMahasiswaRequest, meta domain.AuditMeta) (*domain.Mahasiswa, error) {
 	mahasiswa, err := u.mahasiswaRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -44,9 +44,9 @@ func (u *mahasiswaUsecase) UpdateMahasiswa(ctx context.Context, id int, req *dom
 	mahasiswa.Angkatan = req.Angkatan
 	mahasiswa.Email = req.Email
 
-	return u.mahasiswaRepo.Update(ctx, mahasiswa)
+	return u.mahasiswaRepo.Update(ctx, mahasiswa, meta)
 }
 
-func (u *mahasiswaUsecase) DeleteMahasiswa(ctx context.Context, id int) error {
-	return u.mahasiswaRepo.Delete(ctx, id)
+func (u *mahasiswaUsecase) DeleteMahasiswa(ctx context.Context, id int, meta domain.AuditMeta) error {
+	return u.mahasiswaRepo.Delete(ctx, id, meta)
 }
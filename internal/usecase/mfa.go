@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/utils"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	mfaPurposeVerify = "verify" // 2FA sudah aktif, user tinggal memasukkan kode
+	mfaPurposeEnroll = "enroll" // role mewajibkan 2FA tetapi user belum melakukan setup
+
+	recoveryCodeCount = 10
+)
+
+// mfaChallengePayload adalah isi mfa_token yang dikembalikan Login sebelum access token diterbitkan
+type mfaChallengePayload struct {
+	UserID    int    `json:"uid"`
+	Purpose   string `json:"purpose"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// mfaChallengeKey diturunkan dari secret JWT tetapi berbeda dari kunci token lain
+func (u *authUsecase) mfaChallengeKey() []byte {
+	return []byte("mfa-challenge:" + u.opts.JWTSecret)
+}
+
+// mfaRequiredFor menentukan apakah kebijakan mewajibkan 2FA untuk user ini
+func (u *authUsecase) mfaRequiredFor(user *domain.User) bool {
+	for _, role := range user.Roles {
+		if role == "admin" && u.opts.MFARequiredForAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// mfaChallenge menggantikan token login dengan challenge berumur pendek
+func (u *authUsecase) mfaChallenge(user *domain.User) (*domain.LoginResponse, error) {
+	purpose := mfaPurposeVerify
+	if !user.MFAEnabled {
+		purpose = mfaPurposeEnroll
+	}
+
+	payload, err := json.Marshal(mfaChallengePayload{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(u.opts.MFAChallengeTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResponse{
+		MFARequired:           true,
+		MFAEnrollmentRequired: purpose == mfaPurposeEnroll,
+		MFAToken:              utils.SignToken(u.mfaChallengeKey(), payload),
+	}, nil
+}
+
+// resolveMFAChallenge memvalidasi mfa_token dan memuat ulang user-nya
+func (u *authUsecase) resolveMFAChallenge(ctx context.Context, token, purpose string) (*domain.User, error) {
+	raw, ok := utils.VerifySignedToken(u.mfaChallengeKey(), token)
+	if !ok {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+
+	var payload mfaChallengePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+	if payload.Purpose != purpose || time.Now().Unix() > payload.ExpiresAt {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+	if !user.IsActive || user.EmailVerifiedAt == nil {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+	return user, nil
+}
+
+func (u *authUsecase) VerifyMFA(ctx context.Context, req *domain.MFAVerifyRequest, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	user, err := u.resolveMFAChallenge(ctx, req.MFAToken, mfaPurposeVerify)
+	if err != nil {
+		return nil, err
+	}
+	// Kode 6 digit mudah ditebak jika tidak dibatasi, jadi ikut dihitung ke lockout login
+	if err := u.checkLockout(ctx, user.Email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	if req.RecoveryCode != "" {
+		var ok bool
+		ok, err = u.mfaRepo.UseRecoveryCode(ctx, user.ID, utils.HashToken(normalizeRecoveryCode(req.RecoveryCode)))
+		if err == nil && !ok {
+			err = domain.ErrInvalidMFACode
+		}
+	} else {
+		err = u.checkTOTP(ctx, user.ID, req.Code)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMFACode) {
+			u.registerLoginFailure(ctx, &user.ID, user.Email, client.IPAddress)
+		}
+		return nil, err
+	}
+	u.clearLoginFailures(ctx, user.Email)
+
+	return u.startSession(ctx, user, client)
+}
+
+func (u *authUsecase) BeginMFAEnrollment(ctx context.Context, mfaToken string) (*domain.MFASetupResponse, error) {
+	user, err := u.resolveMFAChallenge(ctx, mfaToken, mfaPurposeEnroll)
+	if err != nil {
+		return nil, err
+	}
+	return u.setupMFA(ctx, user)
+}
+
+func (u *authUsecase) ConfirmMFAEnrollment(ctx context.Context, req *domain.MFAEnrollmentConfirmRequest, client domain.ClientInfo) (*domain.MFAEnrollmentResponse, error) {
+	user, err := u.resolveMFAChallenge(ctx, req.MFAToken, mfaPurposeEnroll)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := u.enableMFA(ctx, user.ID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	login, err := u.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.MFAEnrollmentResponse{LoginResponse: *login, RecoveryCodes: codes}, nil
+}
+
+func (u *authUsecase) GetMFAStatus(ctx context.Context, userID int) (*domain.MFAStatusResponse, error) {
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.MFAStatusResponse{
+		Enabled:  user.MFAEnabled,
+		Required: u.mfaRequiredFor(user),
+	}
+	if user.MFAEnabled {
+		status.RecoveryCodesRemaining, err = u.mfaRepo.CountRecoveryCodes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (u *authUsecase) SetupMFA(ctx context.Context, userID int) (*domain.MFASetupResponse, error) {
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.setupMFA(ctx, user)
+}
+
+func (u *authUsecase) EnableMFA(ctx context.Context, userID int, code string) (*domain.MFARecoveryCodesResponse, error) {
+	codes, err := u.enableMFA(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *authUsecase) DisableMFA(ctx context.Context, userID int, code string) error {
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return domain.ErrMFANotEnabled
+	}
+	if u.mfaRequiredFor(user) {
+		return domain.ErrMFARequired
+	}
+
+	if err := u.checkTOTP(ctx, userID, code); err != nil {
+		return err
+	}
+	return u.mfaRepo.Disable(ctx, userID)
+}
+
+func (u *authUsecase) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*domain.MFARecoveryCodesResponse, error) {
+	mfa, err := u.mfaRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.EnabledAt == nil {
+		return nil, domain.ErrMFANotEnabled
+	}
+	if err := u.checkTOTP(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return &domain.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *authUsecase) ResetMFA(ctx context.Context, userID int) error {
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+	// Dipakai admin saat user kehilangan authenticator; jika role-nya wajib 2FA,
+	// user akan diminta setup ulang pada login berikutnya.
+	if err := u.mfaRepo.Disable(ctx, userID); err != nil {
+		return err
+	}
+	return u.sessionRepo.RevokeAllForUser(ctx, userID, "mfa_reset")
+}
+
+// setupMFA membuat secret baru yang belum aktif sampai dikonfirmasi dengan kode pertama
+func (u *authUsecase) setupMFA(ctx context.Context, user *domain.User) (*domain.MFASetupResponse, error) {
+	if user.MFAEnabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.mfaRepo.SavePendingSecret(ctx, user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &domain.MFASetupResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPProvisioningURI(u.opts.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// enableMFA mengonfirmasi secret yang sedang di-setup dan mengembalikan recovery code dalam bentuk asli
+func (u *authUsecase) enableMFA(ctx context.Context, userID int, code string) ([]string, error) {
+	mfa, err := u.mfaRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMFANotEnabled) {
+			return nil, domain.ErrMFASetupRequired
+		}
+		return nil, err
+	}
+	if mfa.EnabledAt != nil {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := utils.ValidateTOTP(mfa.Secret, code, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.mfaRepo.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// checkTOTP memvalidasi kode untuk 2FA yang sudah aktif dan menolak kode yang sudah pernah dipakai
+func (u *authUsecase) checkTOTP(ctx context.Context, userID int, code string) error {
+	mfa, err := u.mfaRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if mfa.EnabledAt == nil {
+		return domain.ErrMFANotEnabled
+	}
+
+	step, ok := utils.ValidateTOTP(mfa.Secret, code, time.Now())
+	if !ok {
+		return domain.ErrInvalidMFACode
+	}
+	fresh, err := u.mfaRepo.UseStep(ctx, userID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return domain.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// generateRecoveryCodes membuat recovery code berformat xxxxx-xxxxx beserta hash-nya untuk disimpan
+func generateRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(b))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = utils.HashToken(raw)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode mengabaikan huruf besar, spasi, dan tanda hubung yang diketik user
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/utils"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// totpAt menghitung kode TOTP untuk step saat ini ditambah offset, secara independen dari pkg/utils
+func totpAt(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(time.Now().Unix()/30+offset))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	pos := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[pos:pos+4])&0x7fffffff)%1000000)
+}
+
+func currentTOTP(t *testing.T, secret string) string {
+	return totpAt(t, secret, 0)
+}
+
+func TestCheckTOTP(t *testing.T) {
+	ctx := context.Background()
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabled := time.Now()
+
+	t.Run("valid code is accepted once", func(t *testing.T) {
+		mr := newFakeMFARepo()
+		mr.mfa[1] = &domain.UserMFA{UserID: 1, Secret: secret, EnabledAt: &enabled}
+		u := &authUsecase{mfaRepo: mr}
+
+		code := currentTOTP(t, secret)
+		if err := u.checkTOTP(ctx, 1, code); err != nil {
+			t.Fatalf("first use: %v", err)
+		}
+		if err := u.checkTOTP(ctx, 1, code); !errors.Is(err, domain.ErrInvalidMFACode) {
+			t.Fatalf("replay err = %v, want ErrInvalidMFACode", err)
+		}
+	})
+
+	t.Run("older step after newer one is rejected", func(t *testing.T) {
+		mr := newFakeMFARepo()
+		// Step yang lebih baru sudah dipakai, jadi kode saat ini pun dianggap replay
+		mr.mfa[1] = &domain.UserMFA{UserID: 1, Secret: secret, EnabledAt: &enabled, LastUsedStep: time.Now().Unix()/30 + 1}
+		u := &authUsecase{mfaRepo: mr}
+		if err := u.checkTOTP(ctx, 1, currentTOTP(t, secret)); !errors.Is(err, domain.ErrInvalidMFACode) {
+			t.Fatalf("err = %v, want ErrInvalidMFACode", err)
+		}
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		mr := newFakeMFARepo()
+		mr.mfa[1] = &domain.UserMFA{UserID: 1, Secret: secret, EnabledAt: &enabled}
+		u := &authUsecase{mfaRepo: mr}
+		// Pilih kode yang tidak cocok dengan step mana pun dalam toleransi skew
+		valid := map[string]bool{totpAt(t, secret, -1): true, totpAt(t, secret, 0): true, totpAt(t, secret, 1): true}
+		wrong := "000000"
+		for i := 1; valid[wrong]; i++ {
+			wrong = fmt.Sprintf("%06d", i*111111)
+		}
+		if err := u.checkTOTP(ctx, 1, wrong); !errors.Is(err, domain.ErrInvalidMFACode) {
+			t.Fatalf("err = %v, want ErrInvalidMFACode", err)
+		}
+		if mr.mfa[1].LastUsedStep != 0 {
+			t.Fatal("wrong code consumed a step")
+		}
+	})
+
+	t.Run("setup not confirmed", func(t *testing.T) {
+		mr := newFakeMFARepo()
+		mr.mfa[1] = &domain.UserMFA{UserID: 1, Secret: secret}
+		u := &authUsecase{mfaRepo: mr}
+		if err := u.checkTOTP(ctx, 1, currentTOTP(t, secret)); !errors.Is(err, domain.ErrMFANotEnabled) {
+			t.Fatalf("err = %v, want ErrMFANotEnabled", err)
+		}
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+		t.Fatalf("got %d codes and %d hashes, want %d", len(codes), len(hashes), recoveryCodeCount)
+	}
+
+	format := regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`)
+	seen := map[string]bool{}
+	for i, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q does not match xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicate code %q", code)
+		}
+		seen[code] = true
+		if utils.HashToken(normalizeRecoveryCode(code)) != hashes[i] {
+			t.Errorf("hash of %q does not match stored hash", code)
+		}
+	}
+
+	// Variasi ketikan user harus menghasilkan hash yang sama, dan setiap kode hanya bisa dipakai sekali
+	mr := newFakeMFARepo()
+	if err := mr.ReplaceRecoveryCodes(context.Background(), 1, hashes); err != nil {
+		t.Fatal(err)
+	}
+	typed := []string{codes[0], strings.ToUpper(codes[0]), strings.ReplaceAll(codes[0], "-", " ")}
+	for i, input := range typed {
+		ok, err := mr.UseRecoveryCode(context.Background(), 1, utils.HashToken(normalizeRecoveryCode(input)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != (i == 0) {
+			t.Fatalf("use %d of %q: ok = %v", i, input, ok)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"back-train/pkg/utils"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCOptions berisi pengaturan client OpenID Connect
+type OIDCOptions struct {
+	ProviderName string // disimpan di user_identities, misalnya "oidc"
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // URL /api/auth/oidc/callback milik server ini
+	Scopes       []string
+	GroupsClaim  string            // nama claim ID token yang berisi daftar grup
+	RoleMappings map[string]string // grup IdP -> nama role lokal
+	DefaultRole  string            // role untuk user baru yang grupnya tidak dipetakan
+	StateSecret  string            // dipakai untuk menandatangani cookie state
+}
+
+type oidcUsecase struct {
+	userRepo     repository.UserRepository
+	identityRepo repository.UserIdentityRepository
+	authUsecase  AuthUsecase
+	opts         OIDCOptions
+
+	// Discovery dilakukan saat login pertama agar server tetap bisa start walau IdP sedang down
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func NewOIDCUsecase(ur repository.UserRepository, uir repository.UserIdentityRepository, au AuthUsecase, opts OIDCOptions) OIDCUsecase {
+	return &oidcUsecase{
+		userRepo:     ur,
+		identityRepo: uir,
+		authUsecase:  au,
+		opts:         opts,
+	}
+}
+
+// oidcState disimpan di cookie bertanda tangan selama user berada di halaman IdP
+type oidcState struct {
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"` // PKCE code verifier
+	ExpiresAt int64  `json:"exp"`
+}
+
+// oidcClaims adalah claim ID token yang dipakai untuk provisioning
+type oidcClaims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Nonce         string `json:"nonce"`
+}
+
+func (u *oidcUsecase) stateKey() []byte {
+	return []byte("oidc-state:" + u.opts.StateSecret)
+}
+
+func (u *oidcUsecase) getProvider(ctx context.Context) (*oidc.Provider, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.provider == nil {
+		provider, err := oidc.NewProvider(ctx, u.opts.IssuerURL)
+		if err != nil {
+			return nil, fmt.Errorf("oidc discovery: %w", err)
+		}
+		u.provider = provider
+	}
+	return u.provider, nil
+}
+
+func (u *oidcUsecase) oauthConfig(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     u.opts.ClientID,
+		ClientSecret: u.opts.ClientSecret,
+		RedirectURL:  u.opts.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       u.opts.Scopes,
+	}
+}
+
+func (u *oidcUsecase) BeginLogin(ctx context.Context) (*domain.OIDCAuthRequest, error) {
+	provider, err := u.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := utils.GenerateRandomToken(24)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := utils.GenerateRandomToken(24)
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+	expiresAt := time.Now().Add(oidcStateTTL)
+
+	payload, err := json.Marshal(oidcState{State: state, Nonce: nonce, Verifier: verifier, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return nil, err
+	}
+
+	authURL := u.oauthConfig(provider).AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+	return &domain.OIDCAuthRequest{
+		AuthURL:     authURL,
+		StateCookie: utils.SignToken(u.stateKey(), payload),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+func (u *oidcUsecase) Callback(ctx context.Context, code, state, stateCookie string, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	saved, err := u.verifyState(state, stateCookie)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := u.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := u.oauthConfig(provider).Exchange(ctx, code, oauth2.VerifierOption(saved.Verifier))
+	if err != nil {
+		log.Printf("oidc code exchange failed: %v", err)
+		return nil, domain.ErrSSOLoginFailed
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, domain.ErrSSOLoginFailed
+	}
+
+	// Tanda tangan, issuer, audience dan expiry diperiksa terhadap JWKS milik provider
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: u.opts.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		log.Printf("oidc id token verification failed: %v", err)
+		return nil, domain.ErrSSOLoginFailed
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, domain.ErrSSOLoginFailed
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(saved.Nonce)) != 1 {
+		return nil, domain.ErrInvalidSSOState
+	}
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, domain.ErrSSOEmailNotVerified
+	}
+
+	var rawClaims map[string]interface{}
+	if err := idToken.Claims(&rawClaims); err != nil {
+		return nil, domain.ErrSSOLoginFailed
+	}
+
+	user, err := provisionExternalUser(ctx, u.userRepo, u.identityRepo, externalIdentity{
+		Provider: u.opts.ProviderName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+		Groups:   groupsFromClaims(rawClaims, u.opts.GroupsClaim),
+	}, roleMapping{Mappings: u.opts.RoleMappings, DefaultRole: u.opts.DefaultRole})
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, domain.ErrAccountDisabled
+	}
+	return u.authUsecase.CompleteLogin(ctx, user, client)
+}
+
+func (u *oidcUsecase) verifyState(state, stateCookie string) (*oidcState, error) {
+	raw, ok := utils.VerifySignedToken(u.stateKey(), stateCookie)
+	if !ok {
+		return nil, domain.ErrInvalidSSOState
+	}
+
+	var saved oidcState
+	if err := json.Unmarshal(raw, &saved); err != nil {
+		return nil, domain.ErrInvalidSSOState
+	}
+	if time.Now().Unix() > saved.ExpiresAt || subtle.ConstantTimeCompare([]byte(state), []byte(saved.State)) != 1 {
+		return nil, domain.ErrInvalidSSOState
+	}
+	return &saved, nil
+}
+
+// groupsFromClaims membaca claim grup yang bisa berupa array atau string dipisah spasi/koma
+func groupsFromClaims(claims map[string]interface{}, name string) []string {
+	switch value := claims[name].(type) {
+	case []interface{}:
+		groups := make([]string, 0, len(value))
+		for _, item := range value {
+			if group, ok := item.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	case string:
+		return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/pkg/mockidp"
+	"back-train/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const testRedirectURL = "http://app.test/api/auth/oidc/callback"
+
+// startMockIdP menjalankan pkg/mockidp di httptest server. Jika jwksFrom diisi, endpoint /jwks
+// dilayani IdP lain sehingga tanda tangan ID token tidak bisa diverifikasi.
+func startMockIdP(t *testing.T, jwksFrom bool) string {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(nil)
+	issuer := "http://" + srv.Listener.Addr().String()
+	cfg := mockidp.Config{Issuer: issuer, ClientID: "back-train", ClientSecret: "secret"}
+
+	idp, err := mockidp.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handler http.Handler = idp
+	if jwksFrom {
+		other, err := mockidp.New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/", idp)
+		mux.Handle("/jwks", other)
+		handler = mux
+	}
+	srv.Config.Handler = handler
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return issuer
+}
+
+func newTestOIDCUsecase(issuer string, ur *fakeUserRepo, uir *fakeIdentityRepo) *oidcUsecase {
+	auth := newTestAuthUsecase(ur, newFakeSessionRepo(), AuthOptions{})
+	return NewOIDCUsecase(ur, uir, auth, OIDCOptions{
+		ProviderName: "oidc",
+		IssuerURL:    issuer,
+		ClientID:     "back-train",
+		ClientSecret: "secret",
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email"},
+		GroupsClaim:  "groups",
+		RoleMappings: map[string]string{"staff": "admin"},
+		DefaultRole:  "user",
+		StateSecret:  "state-secret",
+	}).(*oidcUsecase)
+}
+
+// signIn mengisi form login mock IdP dan mengembalikan code serta state dari redirect ke callback
+func signIn(t *testing.T, authURL, email, groups string) (string, string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := u.Query()
+	form.Set("email", email)
+	form.Set("groups", groups)
+	form.Set("email_verified", "true")
+	u.RawQuery = ""
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.PostForm(u.String(), form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize status = %d", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(location.String(), testRedirectURL) {
+		t.Fatalf("redirected to %s, want %s", location, testRedirectURL)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+// resignState mengubah isi cookie state lalu menandatanganinya ulang dengan kunci yang sah
+func resignState(t *testing.T, u *oidcUsecase, cookie string, change func(*oidcState)) string {
+	t.Helper()
+	raw, ok := utils.VerifySignedToken(u.stateKey(), cookie)
+	if !ok {
+		t.Fatal("state cookie does not verify")
+	}
+	var s oidcState
+	if err := json.Unmarshal(raw, &s); err != nil {
+		t.Fatal(err)
+	}
+	change(&s)
+	payload, _ := json.Marshal(s)
+	return utils.SignToken(u.stateKey(), payload)
+}
+
+func TestOIDCLoginCallback(t *testing.T) {
+	ctx := context.Background()
+	issuer := startMockIdP(t, false)
+
+	t.Run("links existing user by email and maps groups", func(t *testing.T) {
+		existing := &domain.User{ID: 7, Email: "alumni@example.com", Roles: []string{"user"}, IsActive: true}
+		ur, uir := newFakeUserRepo(existing), newFakeIdentityRepo()
+		u := newTestOIDCUsecase(issuer, ur, uir)
+
+		req, err := u.BeginLogin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := mustQuery(t, req.AuthURL)
+		if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" || q.Get("nonce") == "" || q.Get("state") == "" {
+			t.Fatalf("auth url lacks PKCE, nonce or state: %s", req.AuthURL)
+		}
+
+		code, state := signIn(t, req.AuthURL, "Alumni@Example.com", "staff, other")
+		resp, err := u.Callback(ctx, code, state, req.StateCookie, domain.ClientInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Token == "" || resp.RefreshToken == "" {
+			t.Fatalf("no tokens in %+v", resp)
+		}
+
+		if len(ur.users) != 1 {
+			t.Fatalf("users = %d, want existing user reused", len(ur.users))
+		}
+		if id := uir.links["oidc|mock|Alumni@Example.com"]; id != existing.ID {
+			t.Fatalf("identity linked to %d, want %d", id, existing.ID)
+		}
+		roles := append([]string(nil), existing.Roles...)
+		sort.Strings(roles)
+		if strings.Join(roles, ",") != "admin,user" {
+			t.Fatalf("roles = %v, want admin and user", roles)
+		}
+		if existing.EmailVerifiedAt == nil {
+			t.Fatal("email not marked verified")
+		}
+	})
+
+	t.Run("provisions new user with default role", func(t *testing.T) {
+		ur, uir := newFakeUserRepo(), newFakeIdentityRepo()
+		u := newTestOIDCUsecase(issuer, ur, uir)
+		req, err := u.BeginLogin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, state := signIn(t, req.AuthURL, "new@example.com", "")
+		if _, err := u.Callback(ctx, code, state, req.StateCookie, domain.ClientInfo{}); err != nil {
+			t.Fatal(err)
+		}
+		created, err := ur.GetUserByEmail(ctx, "new@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(created.Roles) != 1 || created.Roles[0] != "user" {
+			t.Fatalf("roles = %v, want [user]", created.Roles)
+		}
+	})
+
+	t.Run("rejects tampered state and verifier", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			cookie func(u *oidcUsecase, cookie string) string
+			state  func(state string) string
+			want   error
+		}{
+			{"state mismatch", nil, func(string) string { return "other" }, domain.ErrInvalidSSOState},
+			{"unsigned cookie", func(*oidcUsecase, string) string { return "garbage" }, nil, domain.ErrInvalidSSOState},
+			{"expired cookie", func(u *oidcUsecase, c string) string {
+				return resignState(t, u, c, func(s *oidcState) { s.ExpiresAt = 1 })
+			}, nil, domain.ErrInvalidSSOState},
+			{"wrong PKCE verifier", func(u *oidcUsecase, c string) string {
+				return resignState(t, u, c, func(s *oidcState) { s.Verifier = strings.Repeat("x", 43) })
+			}, nil, domain.ErrSSOLoginFailed},
+			{"nonce mismatch", func(u *oidcUsecase, c string) string {
+				return resignState(t, u, c, func(s *oidcState) { s.Nonce = "replayed" })
+			}, nil, domain.ErrInvalidSSOState},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ur := newFakeUserRepo()
+				u := newTestOIDCUsecase(issuer, ur, newFakeIdentityRepo())
+				req, err := u.BeginLogin(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				code, state := signIn(t, req.AuthURL, "x@example.com", "")
+				cookie := req.StateCookie
+				if tt.cookie != nil {
+					cookie = tt.cookie(u, cookie)
+				}
+				if tt.state != nil {
+					state = tt.state(state)
+				}
+				if _, err := u.Callback(ctx, code, state, cookie, domain.ClientInfo{}); !errors.Is(err, tt.want) {
+					t.Fatalf("err = %v, want %v", err, tt.want)
+				}
+				if len(ur.users) != 0 {
+					t.Fatal("user provisioned despite failed callback")
+				}
+			})
+		}
+	})
+
+	t.Run("rejects id token not signed by the provider's JWKS", func(t *testing.T) {
+		ur := newFakeUserRepo()
+		u := newTestOIDCUsecase(startMockIdP(t, true), ur, newFakeIdentityRepo())
+		req, err := u.BeginLogin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, state := signIn(t, req.AuthURL, "x@example.com", "")
+		if _, err := u.Callback(ctx, code, state, req.StateCookie, domain.ClientInfo{}); !errors.Is(err, domain.ErrSSOLoginFailed) {
+			t.Fatalf("err = %v, want ErrSSOLoginFailed", err)
+		}
+	})
+
+	t.Run("disabled account", func(t *testing.T) {
+		ur := newFakeUserRepo(&domain.User{ID: 3, Email: "off@example.com", Roles: []string{"user"}})
+		u := newTestOIDCUsecase(issuer, ur, newFakeIdentityRepo())
+		req, err := u.BeginLogin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, state := signIn(t, req.AuthURL, "off@example.com", "")
+		if _, err := u.Callback(ctx, code, state, req.StateCookie, domain.ClientInfo{}); !errors.Is(err, domain.ErrAccountDisabled) {
+			t.Fatalf("err = %v, want ErrAccountDisabled", err)
+		}
+	})
+}
+
+func mustQuery(t *testing.T, raw string) url.Values {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Query()
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"net/mail"
+	"strings"
+)
+
+// checkPassword menerapkan kebijakan password pada register, reset, dan ganti password
+func (u *authUsecase) checkPassword(password, email string) error {
+	if u.opts.PasswordPolicy == nil {
+		return nil
+	}
+
+	violations, err := u.opts.PasswordPolicy.Validate(password, email)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	policyErr := &domain.PasswordPolicyError{}
+	for _, v := range violations {
+		policyErr.Violations = append(policyErr.Violations, domain.PasswordViolation{Rule: v.Rule, Message: v.Message})
+	}
+	return policyErr
+}
+
+// normalizeEmail memvalidasi alamat email dan mengembalikannya dalam huruf kecil tanpa spasi di awal/akhir,
+// sama dengan index unik lower(email) di tabel users
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	// Tolak bentuk "Nama <email>" agar yang tersimpan hanya alamatnya
+	if err != nil || addr.Address != email {
+		return "", domain.ErrInvalidEmail
+	}
+	return strings.ToLower(email), nil
+}
@@ -10,17 +10,18 @@ import (
 
 type pekerjaanUsecase struct {
 	pekerjaanRepo repository.PekerjaanRepository
+	exportOpts    ExportOptions
 }
 
-func NewPekerjaanUsecase(pr repository.PekerjaanRepository) PekerjaanUsecase {
-	return &pekerjaanUsecase{pekerjaanRepo: pr}
+func NewPekerjaanUsecase(pr repository.PekerjaanRepository, exportOpts ExportOptions) PekerjaanUsecase {
+	return &pekerjaanUsecase{pekerjaanRepo: pr, exportOpts: exportOpts}
 }
 
 func parseDate(dateStr string) (time.Time, error) {
 	return time.Parse("2006-01-02", dateStr)
 }
 
-func (u *pekerjaanUsecase) CreatePekerjaan(ctx context.Context, req *domain.CreatePekerjaanRequest) (*domain.Pekerjaan, error) {
+func (u *pekerjaanUsecase) CreatePekerjaan(ctx context.Context, req *domain.CreatePekerjaanRequest, meta domain.AuditMeta) (*domain.Pekerjaan, error) {
 	tglMulai, err := parseDate(req.TanggalMulaiKerja)
 	if err != nil {
 		return nil, errors.New("invalid format for TanggalMulaiKerja, use YYYY-MM-DD")
@@ -47,7 +48,7 @@ func (u *pekerjaanUsecase) CreatePekerjaan(ctx context.Context, req *domain.Crea
 		StatusPekerjaan:     req.StatusPekerjaan,
 		DeskripsiPekerjaan:  req.DeskripsiPekerjaan,
 	}
-	return u.pekerjaanRepo.Create(ctx, pekerjaan)
+	return u.pekerjaanRepo.Create(ctx, pekerjaan, meta)
 }
 
 func (u *pekerjaanUsecase) GetAllPekerjaan(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Pekerjaan], error) {
@@ -58,7 +59,11 @@ func (u *pekerjaanUsecase) GetPekerjaanByID(ctx context.Context, id int) (*domai
 	return u.pekerjaanRepo.FindByID(ctx, id)
 }
 
-func (u *pekerjaanUsecase) UpdatePekerjaan(ctx context.Context, id int, req *domain.UpdatePekerjaanRequest) (*domain.Pekerjaan, error) {
+func (u *pekerjaanUsecase) GetPekerjaanByAlumniID(ctx context.Context, alumniID int) ([]domain.Pekerjaan, error) {
+	return u.pekerjaanRepo.FindByAlumniID(ctx, alumniID)
+}
+
+func (u *pekerjaanUsecase) UpdatePekerjaan(ctx context.Context, id int, req *domain.UpdatePekerjaanRequest, meta domain.AuditMeta) (*domain.Pekerjaan, error) {
 	pekerjaan, err := u.pekerjaanRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -88,9 +93,9 @@ func (u *pekerjaanUsecase) UpdatePekerjaan(ctx context.Context, id int, req *dom
 	pekerjaan.StatusPekerjaan = req.StatusPekerjaan
 	pekerjaan.DeskripsiPekerjaan = req.DeskripsiPekerjaan
 
-	return u.pekerjaanRepo.Update(ctx, pekerjaan)
+	return u.pekerjaanRepo.Update(ctx, pekerjaan, meta)
 }
 
-func (u *pekerjaanUsecase) DeletePekerjaan(ctx context.Context, id int) error {
-	return u.pekerjaanRepo.Delete(ctx, id)
+func (u *pekerjaanUsecase) DeletePekerjaan(ctx context.Context, id int, meta domain.AuditMeta) error {
+	return u.pekerjaanRepo.Delete(ctx, id, meta)
 }
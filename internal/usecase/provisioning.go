@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"context"
+	"errors"
+	"log"
+)
+
+// externalIdentity adalah user yang identitasnya sudah diverifikasi sistem luar (IdP OIDC atau LDAP)
+type externalIdentity struct {
+	Provider string // disimpan di user_identities, misalnya "oidc" atau "ldap"
+	Subject  string // ID stabil dari sistem luar: claim sub atau DN
+	Email    string
+	Groups   []string
+}
+
+// roleMapping memetakan grup dari sistem luar ke role lokal
+type roleMapping struct {
+	Mappings    map[string]string // grup -> nama role lokal
+	DefaultRole string            // role untuk user baru yang grupnya tidak dipetakan
+}
+
+// provisionExternalUser mencari user lewat identitas yang sudah terhubung, lalu lewat email,
+// dan membuat user baru jika belum ada. Role hasil pemetaan grup disinkronkan setiap login.
+func provisionExternalUser(ctx context.Context, userRepo repository.UserRepository, identityRepo repository.UserIdentityRepository, identity externalIdentity, mapping roleMapping) (*domain.User, error) {
+	var user *domain.User
+	userID, err := identityRepo.FindUserID(ctx, identity.Provider, identity.Subject)
+	switch {
+	case err == nil:
+		user, err = userRepo.GetUserByID(ctx, userID)
+	case errors.Is(err, domain.ErrUserNotFound):
+		user, err = userRepo.GetUserByEmail(ctx, identity.Email)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			// Password kosong tidak pernah cocok dengan bcrypt, jadi akun ini tidak bisa login dengan password lokal
+			user, err = userRepo.CreateUser(ctx, &domain.User{Email: identity.Email}, mapping.DefaultRole)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := identityRepo.Link(ctx, user.ID, identity.Provider, identity.Subject, identity.Email); err != nil {
+		return nil, err
+	}
+	if user.EmailVerifiedAt == nil {
+		if err := userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if roles, changed := mapping.apply(user.Roles, identity.Groups); changed {
+		if err := userRepo.SetRoles(ctx, user.ID, roles); err != nil {
+			// Misalnya admin terakhir kehilangan grupnya; login tetap jalan dengan role lama
+			log.Printf("failed to sync %s roles for user %d: %v", identity.Provider, user.ID, err)
+		}
+	}
+
+	// Muat ulang agar role, permission dan status verifikasi terbaru masuk ke token
+	return userRepo.GetUserByID(ctx, user.ID)
+}
+
+// apply mengganti role yang dikelola lewat pemetaan grup dengan hasil pemetaan terbaru,
+// sementara role lain yang diberikan manual oleh admin tetap dipertahankan.
+func (m roleMapping) apply(current, groups []string) ([]string, bool) {
+	if len(m.Mappings) == 0 {
+		return current, false
+	}
+
+	managed := map[string]bool{}
+	for _, role := range m.Mappings {
+		managed[role] = true
+	}
+
+	roles := []string{}
+	for _, role := range current {
+		if !managed[role] {
+			roles = append(roles, role)
+		}
+	}
+	for _, group := range groups {
+		if role, ok := m.Mappings[group]; ok {
+			roles = append(roles, role)
+		}
+	}
+	roles = uniqueStrings(roles)
+	if len(roles) == 0 {
+		roles = []string{m.DefaultRole}
+	}
+
+	return roles, !sameStringSet(current, roles)
+}
+
+func sameStringSet(a, b []string) bool {
+	a, b = uniqueStrings(a), uniqueStrings(b)
+	if len(a) != len(b) {
+		return false
+	}
+	set := map[string]bool{}
+	for _, item := range a {
+		set[item] = true
+	}
+	for _, item := range b {
+		if !set[item] {
+			return false
+		}
+	}
+	return true
+}
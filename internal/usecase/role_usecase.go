@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"context"
+	"strings"
+)
+
+type roleUsecase struct {
+	roleRepo repository.RoleRepository
+}
+
+func NewRoleUsecase(rr repository.RoleRepository) RoleUsecase {
+	return &roleUsecase{roleRepo: rr}
+}
+
+func normalizeRoleName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || len(name) > 50 {
+		return "", domain.ErrInvalidRoleName
+	}
+	return name, nil
+}
+
+func (u *roleUsecase) GetAllRoles(ctx context.Context) ([]domain.Role, error) {
+	return u.roleRepo.FindAll(ctx)
+}
+
+func (u *roleUsecase) GetRoleByID(ctx context.Context, id int) (*domain.Role, error) {
+	return u.roleRepo.FindByID(ctx, id)
+}
+
+func (u *roleUsecase) CreateRole(ctx context.Context, req *domain.CreateRoleRequest) (*domain.Role, error) {
+	name, err := normalizeRoleName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		Name:        name,
+		Permissions: uniqueStrings(req.Permissions),
+	}
+	if _, err := u.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+	return u.roleRepo.FindByID(ctx, role.ID)
+}
+
+func (u *roleUsecase) UpdateRole(ctx context.Context, id int, req *domain.UpdateRoleRequest) (*domain.Role, error) {
+	name, err := normalizeRoleName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := u.roleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if isBuiltInRole(role.Name) {
+		return nil, domain.ErrProtectedRole
+	}
+
+	if err := u.roleRepo.Rename(ctx, id, name); err != nil {
+		return nil, err
+	}
+	return u.roleRepo.FindByID(ctx, id)
+}
+
+func (u *roleUsecase) UpdateRolePermissions(ctx context.Context, id int, req *domain.UpdateRolePermissionsRequest) (*domain.Role, error) {
+	role, err := u.roleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// admin selalu memegang semua permission agar tidak ada yang bisa mengunci dirinya sendiri
+	if role.Name == domain.RoleAdmin {
+		return nil, domain.ErrProtectedRole
+	}
+
+	if err := u.roleRepo.SetPermissions(ctx, id, uniqueStrings(req.Permissions)); err != nil {
+		return nil, err
+	}
+	return u.roleRepo.FindByID(ctx, id)
+}
+
+func (u *roleUsecase) DeleteRole(ctx context.Context, id int) error {
+	role, err := u.roleRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if isBuiltInRole(role.Name) {
+		return domain.ErrProtectedRole
+	}
+	return u.roleRepo.Delete(ctx, id)
+}
+
+func (u *roleUsecase) GetAllPermissions(ctx context.Context) ([]domain.Permission, error) {
+	return u.roleRepo.FindAllPermissions(ctx)
+}
+
+func isBuiltInRole(name string) bool {
+	return name == domain.RoleAdmin || name == domain.RoleUser
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"back-train/pkg/mailer"
+	"back-train/pkg/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// selfServiceUsecase melayani alumni yang mengelola datanya sendiri lewat /api/me.
+// Semua perubahan diteruskan ke alumniUsecase/pekerjaanUsecase setelah kepemilikan dicek.
+type selfServiceUsecase struct {
+	alumniRepo       repository.AlumniRepository
+	claimRepo        repository.AlumniClaimRepository
+	userRepo         repository.UserRepository
+	alumniUsecase    AlumniUsecase
+	pekerjaanUsecase PekerjaanUsecase
+	mailer           mailer.Mailer
+	claimTTL         time.Duration
+	appBaseURL       string
+}
+
+func NewSelfServiceUsecase(ar repository.AlumniRepository, cr repository.AlumniClaimRepository, ur repository.UserRepository, au AlumniUsecase, pu PekerjaanUsecase, m mailer.Mailer, claimTTL time.Duration, appBaseURL string) SelfServiceUsecase {
+	return &selfServiceUsecase{
+		alumniRepo:       ar,
+		claimRepo:        cr,
+		userRepo:         ur,
+		alumniUsecase:    au,
+		pekerjaanUsecase: pu,
+		mailer:           m,
+		claimTTL:         claimTTL,
+		appBaseURL:       appBaseURL,
+	}
+}
+
+func (u *selfServiceUsecase) ClaimAlumni(ctx context.Context, userID int, nim string, meta domain.AuditMeta) (*domain.Alumni, error) {
+	if _, err := u.alumniRepo.FindByUserID(ctx, userID); err == nil {
+		return nil, domain.ErrAlumniAlreadyLinked
+	}
+
+	alumni, err := u.alumniRepo.FindByNIM(ctx, strings.TrimSpace(nim))
+	if err != nil {
+		return nil, err
+	}
+	if alumni.UserID != nil {
+		return nil, domain.ErrAlumniAlreadyLinked
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Email akun sudah terverifikasi saat login, jadi jika sama dengan email alumni langsung dihubungkan
+	if strings.EqualFold(user.Email, alumni.Email) {
+		if err := u.claimRepo.LinkIfUnclaimed(ctx, userID, alumni.ID, meta); err != nil {
+			return nil, err
+		}
+		return u.alumniRepo.FindByID(ctx, alumni.ID)
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.claimRepo.Create(ctx, userID, alumni.ID, utils.HashToken(token), time.Now().Add(u.claimTTL)); err != nil {
+		return nil, err
+	}
+
+	msg := mailer.Message{
+		To:      []string{alumni.Email},
+		Subject: "Konfirmasi klaim data alumni",
+		Body: fmt.Sprintf("Akun %s meminta untuk dihubungkan dengan data alumni NIM %s.\n\n"+
+			"Jika itu Anda, buka link berikut (berlaku %d jam):\n%s/alumni/claim?token=%s\n\n"+
+			"Abaikan email ini jika Anda tidak memintanya.",
+			user.Email, alumni.NIM, int(u.claimTTL.Hours()), u.appBaseURL, token),
+	}
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	// nil berarti klaim menunggu konfirmasi lewat email
+	return nil, nil
+}
+
+func (u *selfServiceUsecase) ConfirmAlumniClaim(ctx context.Context, userID int, token string, meta domain.AuditMeta) (*domain.Alumni, error) {
+	if token == "" {
+		return nil, domain.ErrInvalidClaimToken
+	}
+
+	alumniID, err := u.claimRepo.Consume(ctx, userID, utils.HashToken(token), meta)
+	if err != nil {
+		return nil, err
+	}
+	return u.alumniRepo.FindByID(ctx, alumniID)
+}
+
+func (u *selfServiceUsecase) GetMyAlumni(ctx context.Context, userID int) (*domain.Alumni, error) {
+	return u.alumniRepo.FindByUserID(ctx, userID)
+}
+
+func (u *selfServiceUsecase) UpdateMyAlumni(ctx context.Context, userID int, req *domain.UpdateMyAlumniRequest, meta domain.AuditMeta) (*domain.Alumni, error) {
+	alumni, err := u.alumniRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Data akademik tidak boleh diubah sendiri, jadi diisi ulang dari data yang tersimpan
+	return u.alumniUsecase.UpdateAlumni(ctx, alumni.ID, &domain.UpdateAlumniRequest{
+		Nama:       alumni.Nama,
+		Jurusan:    alumni.Jurusan,
+		Angkatan:   alumni.Angkatan,
+		TahunLulus: alumni.TahunLulus,
+		Email:      req.Email,
+		NoTelepon:  req.NoTelepon,
+		Alamat:     req.Alamat,
+	}, meta)
+}
+
+func (u *selfServiceUsecase) GetMyPekerjaan(ctx context.Context, userID int) ([]domain.Pekerjaan, error) {
+	alumni, err := u.alumniRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.pekerjaanUsecase.GetPekerjaanByAlumniID(ctx, alumni.ID)
+}
+
+func (u *selfServiceUsecase) CreateMyPekerjaan(ctx context.Context, userID int, req *domain.CreatePekerjaanRequest, meta domain.AuditMeta) (*domain.Pekerjaan, error) {
+	alumni, err := u.alumniRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// alumni_id dari request diabaikan: pekerjaan selalu dicatat untuk alumni milik akun ini
+	req.AlumniID = alumni.ID
+	return u.pekerjaanUsecase.CreatePekerjaan(ctx, req, meta)
+}
+
+func (u *selfServiceUsecase) EndMyPekerjaan(ctx context.Context, userID int, pekerjaanID int, req *domain.EndPekerjaanRequest, meta domain.AuditMeta) (*domain.Pekerjaan, error) {
+	alumni, err := u.alumniRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pekerjaan, err := u.pekerjaanUsecase.GetPekerjaanByID(ctx, pekerjaanID)
+	if err != nil {
+		return nil, err
+	}
+	if pekerjaan.AlumniID != alumni.ID {
+		return nil, domain.ErrNotOwner
+	}
+
+	tglSelesai := req.TanggalSelesaiKerja
+	return u.pekerjaanUsecase.UpdatePekerjaan(ctx, pekerjaan.ID, &domain.UpdatePekerjaanRequest{
+		NamaPerusahaan:      pekerjaan.NamaPerusahaan,
+		PosisiJabatan:       pekerjaan.PosisiJabatan,
+		BidangIndustri:      pekerjaan.BidangIndustri,
+		LokasiKerja:         pekerjaan.LokasiKerja,
+		GajiRange:           pekerjaan.GajiRange,
+		TanggalMulaiKerja:   pekerjaan.TanggalMulaiKerja.Format("2006-01-02"),
+		TanggalSelesaiKerja: &tglSelesai,
+		StatusPekerjaan:     pekerjaan.StatusPekerjaan,
+		DeskripsiPekerjaan:  pekerjaan.DeskripsiPekerjaan,
+	}, meta)
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type selfServiceFixture struct {
+	u         *selfServiceUsecase
+	alumni    *fakeAlumniRepo
+	claims    *fakeAlumniClaimRepo
+	pekerjaan *fakePekerjaanRepo
+	mail      *fakeMailer
+}
+
+// newSelfServiceFixture: user 1 (email sama dengan alumni 10), user 2 (email lain), user 3 pemilik alumni 11
+func newSelfServiceFixture() *selfServiceFixture {
+	owner := 3
+	ar := newFakeAlumniRepo(
+		&domain.Alumni{ID: 10, NIM: "A10", Nama: "Budi", Jurusan: "TI", Angkatan: 2018, TahunLulus: 2022, Email: "budi@example.com"},
+		&domain.Alumni{ID: 11, NIM: "A11", Nama: "Sari", Jurusan: "SI", Angkatan: 2017, TahunLulus: 2021, Email: "sari@example.com", UserID: &owner},
+	)
+	ur := newFakeUserRepo(
+		&domain.User{ID: 1, Email: "Budi@Example.com", IsActive: true},
+		&domain.User{ID: 2, Email: "other@example.com", IsActive: true},
+		&domain.User{ID: 3, Email: "sari@example.com", IsActive: true},
+	)
+	cr := newFakeAlumniClaimRepo(ar)
+	pr := newFakePekerjaanRepo(
+		&domain.Pekerjaan{ID: 100, AlumniID: 10, NamaPerusahaan: "PT A", TanggalMulaiKerja: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), StatusPekerjaan: "aktif"},
+		&domain.Pekerjaan{ID: 101, AlumniID: 11, NamaPerusahaan: "PT B", TanggalMulaiKerja: time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), StatusPekerjaan: "aktif"},
+	)
+	mail := &fakeMailer{}
+	u := NewSelfServiceUsecase(ar, cr, ur, NewAlumniUsecase(ar, ur, AlumniImportOptions{}, ExportOptions{}),
+		NewPekerjaanUsecase(pr, ExportOptions{}), mail, time.Hour, "http://app").(*selfServiceUsecase)
+	return &selfServiceFixture{u: u, alumni: ar, claims: cr, pekerjaan: pr, mail: mail}
+}
+
+func TestClaimAlumni(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("matching email links immediately", func(t *testing.T) {
+		f := newSelfServiceFixture()
+		a, err := f.u.ClaimAlumni(ctx, 1, " A10 ", domain.AuditMeta{})
+		if err != nil {
+			t.Fatalf("ClaimAlumni: %v", err)
+		}
+		if a == nil || a.UserID == nil || *a.UserID != 1 {
+			t.Fatalf("alumni = %+v, want linked to user 1", a)
+		}
+		if len(f.mail.messages()) != 0 {
+			t.Fatal("no confirmation email expected for a matching email")
+		}
+	})
+
+	t.Run("other email needs confirmation", func(t *testing.T) {
+		f := newSelfServiceFixture()
+		a, err := f.u.ClaimAlumni(ctx, 2, "A10", domain.AuditMeta{})
+		if err != nil || a != nil {
+			t.Fatalf("ClaimAlumni = %+v, %v; want pending claim", a, err)
+		}
+		if f.alumni.alumni[10].UserID != nil {
+			t.Fatal("alumni linked without confirmation")
+		}
+		msgs := f.mail.messages()
+		if len(f.claims.claims) != 1 || len(msgs) != 1 || msgs[0].To[0] != "budi@example.com" {
+			t.Fatalf("claims = %v, mails = %v; want one claim mailed to the alumni address", f.claims.claims, msgs)
+		}
+	})
+
+	t.Run("alumni owned by another account", func(t *testing.T) {
+		f := newSelfServiceFixture()
+		if _, err := f.u.ClaimAlumni(ctx, 1, "A11", domain.AuditMeta{}); !errors.Is(err, domain.ErrAlumniAlreadyLinked) {
+			t.Fatalf("err = %v, want ErrAlumniAlreadyLinked", err)
+		}
+	})
+
+	t.Run("account already linked", func(t *testing.T) {
+		f := newSelfServiceFixture()
+		if _, err := f.u.ClaimAlumni(ctx, 3, "A10", domain.AuditMeta{}); !errors.Is(err, domain.ErrAlumniAlreadyLinked) {
+			t.Fatalf("err = %v, want ErrAlumniAlreadyLinked", err)
+		}
+	})
+
+	t.Run("concurrent claim is not overwritten", func(t *testing.T) {
+		f := newSelfServiceFixture()
+		// Klaim lain selesai setelah usecase membaca alumni tetapi sebelum menghubungkannya
+		other := 2
+		f.claims.beforeLink = func() { f.alumni.alumni[10].UserID = &other }
+		if _, err := f.u.ClaimAlumni(ctx, 1, "A10", domain.AuditMeta{}); !errors.Is(err, domain.ErrAlumniAlreadyLinked) {
+			t.Fatalf("err = %v, want ErrAlumniAlreadyLinked", err)
+		}
+		if got := *f.alumni.alumni[10].UserID; got != other {
+			t.Fatalf("alumni linked to user %d, want %d", got, other)
+		}
+	})
+
+	t.Run("unknown nim", func(t *testing.T) {
+		f := newSelfServiceFixture()
+		if _, err := f.u.ClaimAlumni(ctx, 1, "X", domain.AuditMeta{}); !errors.Is(err, domain.ErrAlumniNotFound) {
+			t.Fatalf("err = %v, want ErrAlumniNotFound", err)
+		}
+	})
+}
+
+func TestUpdateMyAlumni(t *testing.T) {
+	f := newSelfServiceFixture()
+	phone := "0812"
+	a, err := f.u.UpdateMyAlumni(context.Background(), 3, &domain.UpdateMyAlumniRequest{Email: "sari@new.example.com", NoTelepon: &phone}, domain.AuditMeta{})
+	if err != nil {
+		t.Fatalf("UpdateMyAlumni: %v", err)
+	}
+	// Hanya kontak yang berubah; data akademik tetap
+	if a.ID != 11 || a.Email != "sari@new.example.com" || a.NoTelepon == nil || *a.NoTelepon != phone {
+		t.Fatalf("alumni = %+v", a)
+	}
+	if a.Nama != "Sari" || a.NIM != "A11" || a.Angkatan != 2017 || a.TahunLulus != 2021 {
+		t.Fatalf("academic data changed: %+v", a)
+	}
+	if f.alumni.alumni[10].Email != "budi@example.com" {
+		t.Fatal("another alumni record was changed")
+	}
+
+	if _, err := f.u.UpdateMyAlumni(context.Background(), 2, &domain.UpdateMyAlumniRequest{Email: "x@example.com"}, domain.AuditMeta{}); !errors.Is(err, domain.ErrAlumniNotLinked) {
+		t.Fatalf("unlinked account: err = %v, want ErrAlumniNotLinked", err)
+	}
+}
+
+func TestEndMyPekerjaan(t *testing.T) {
+	ctx := context.Background()
+	req := &domain.EndPekerjaanRequest{TanggalSelesaiKerja: "2024-01-31"}
+
+	f := newSelfServiceFixture()
+	p, err := f.u.EndMyPekerjaan(ctx, 3, 101, req, domain.AuditMeta{})
+	if err != nil {
+		t.Fatalf("EndMyPekerjaan: %v", err)
+	}
+	if p.TanggalSelesaiKerja == nil || p.TanggalSelesaiKerja.Format("2006-01-02") != "2024-01-31" || p.NamaPerusahaan != "PT B" {
+		t.Fatalf("pekerjaan = %+v", p)
+	}
+
+	// Pekerjaan milik alumni lain
+	if _, err := f.u.EndMyPekerjaan(ctx, 3, 100, req, domain.AuditMeta{}); !errors.Is(err, domain.ErrNotOwner) {
+		t.Fatalf("err = %v, want ErrNotOwner", err)
+	}
+	if f.pekerjaan.pekerjaan[100].TanggalSelesaiKerja != nil {
+		t.Fatal("pekerjaan of another alumni was changed")
+	}
+	if _, err := f.u.EndMyPekerjaan(ctx, 2, 100, req, domain.AuditMeta{}); !errors.Is(err, domain.ErrAlumniNotLinked) {
+		t.Fatalf("unlinked account: err = %v, want ErrAlumniNotLinked", err)
+	}
+	if _, err := f.u.EndMyPekerjaan(ctx, 3, 999, req, domain.AuditMeta{}); !errors.Is(err, domain.ErrPekerjaanNotFound) {
+		t.Fatalf("missing pekerjaan: err = %v, want ErrPekerjaanNotFound", err)
+	}
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"context"
+)
+
+func (u *authUsecase) ListSessions(ctx context.Context, userID int, currentSessionID string) ([]domain.Session, error) {
+	sessions, err := u.sessionRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (u *authUsecase) RevokeSession(ctx context.Context, actorID, userID int, sessionID string) error {
+	if err := u.sessionRepo.RevokeForUser(ctx, userID, sessionID, revokeReason(actorID, userID)); err != nil {
+		return err
+	}
+
+	u.recordAuthEvent(ctx, &domain.AuthEvent{
+		EventType: "session_revoked",
+		UserID:    &userID,
+		ActorID:   &actorID,
+		Detail:    optionalString("session " + sessionID),
+	})
+	return nil
+}
+
+func (u *authUsecase) RevokeAllSessions(ctx context.Context, actorID, userID int, keepSessionID string) error {
+	if _, err := u.userRepo.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+
+	reason := revokeReason(actorID, userID)
+	var err error
+	if keepSessionID == "" {
+		err = u.sessionRepo.RevokeAllForUser(ctx, userID, reason)
+	} else {
+		err = u.sessionRepo.RevokeOthersForUser(ctx, userID, keepSessionID, reason)
+	}
+	if err != nil {
+		return err
+	}
+
+	detail := "all sessions"
+	if keepSessionID != "" {
+		detail = "all other sessions"
+	}
+	u.recordAuthEvent(ctx, &domain.AuthEvent{
+		EventType: "sessions_revoked",
+		UserID:    &userID,
+		ActorID:   &actorID,
+		Detail:    optionalString(detail),
+	})
+	return nil
+}
+
+// revokeReason membedakan sign-out oleh pemilik akun dan oleh admin di kolom revoked_reason
+func revokeReason(actorID, userID int) string {
+	if actorID == userID {
+		return "revoked_by_user"
+	}
+	return "revoked_by_admin"
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"context"
+	"fmt"
+)
+
+type statsUsecase struct {
+	statsRepo       repository.StatsRepository
+	graduationMonth int
+}
+
+func NewStatsUsecase(sr repository.StatsRepository, graduationMonth int) StatsUsecase {
+	return &statsUsecase{statsRepo: sr, graduationMonth: graduationMonth}
+}
+
+func validateStatsFilter(filter *domain.StatsFilter) error {
+	if filter.TahunLulusFrom != nil && filter.TahunLulusTo != nil && *filter.TahunLulusFrom > *filter.TahunLulusTo {
+		return fmt.Errorf("%w: tahun_lulus_from must not be after tahun_lulus_to", domain.ErrInvalidStatsFilter)
+	}
+	switch filter.Job {
+	case "":
+		filter.Job = "latest"
+	case "latest", "first":
+	default:
+		return fmt.Errorf("%w: job must be latest or first", domain.ErrInvalidStatsFilter)
+	}
+	return nil
+}
+
+func (u *statsUsecase) GetEmployment(ctx context.Context, filter domain.StatsFilter) (*domain.EmploymentSummary, error) {
+	if err := validateStatsFilter(&filter); err != nil {
+		return nil, err
+	}
+	return u.statsRepo.Employment(ctx, filter, u.graduationMonth)
+}
+
+func (u *statsUsecase) GetIndustryDistribution(ctx context.Context, filter domain.StatsFilter) (*domain.StatsDistribution, error) {
+	if err := validateStatsFilter(&filter); err != nil {
+		return nil, err
+	}
+	return u.statsRepo.Distribution(ctx, filter, "bidang_industri")
+}
+
+func (u *statsUsecase) GetSalaryDistribution(ctx context.Context, filter domain.StatsFilter) (*domain.StatsDistribution, error) {
+	if err := validateStatsFilter(&filter); err != nil {
+		return nil, err
+	}
+	return u.statsRepo.Distribution(ctx, filter, "gaji_range")
+}
@@ -0,0 +1,606 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"back-train/pkg/mailer"
+	"back-train/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tracerInviteBatch adalah jumlah undangan yang disimpan per query
+const tracerInviteBatch = 500
+
+var questionCodePattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+type tracerUsecase struct {
+	tracerRepo repository.TracerRepository
+	alumniRepo repository.AlumniRepository
+	mailer     mailer.Mailer
+	appBaseURL string
+}
+
+func NewTracerUsecase(tr repository.TracerRepository, ar repository.AlumniRepository, m mailer.Mailer, appBaseURL string) TracerUsecase {
+	return &tracerUsecase{tracerRepo: tr, alumniRepo: ar, mailer: m, appBaseURL: appBaseURL}
+}
+
+func questionnaireFromRequest(req *domain.QuestionnaireRequest) (*domain.Questionnaire, error) {
+	q := &domain.Questionnaire{
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		Period:      strings.TrimSpace(req.Period),
+		OpensAt:     req.OpensAt,
+		ClosesAt:    req.ClosesAt,
+	}
+	switch {
+	case q.Title == "" || q.Period == "":
+		return nil, fmt.Errorf("%w: title and period are required", domain.ErrInvalidQuestions)
+	case len(q.Period) > 20:
+		return nil, fmt.Errorf("%w: period must be at most 20 characters", domain.ErrInvalidQuestions)
+	case q.OpensAt != nil && q.ClosesAt != nil && !q.ClosesAt.After(*q.OpensAt):
+		return nil, fmt.Errorf("%w: closes_at must be after opens_at", domain.ErrInvalidQuestions)
+	}
+	return q, nil
+}
+
+func (u *tracerUsecase) CreateQuestionnaire(ctx context.Context, req *domain.QuestionnaireRequest) (*domain.Questionnaire, error) {
+	q, err := questionnaireFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return u.tracerRepo.CreateQuestionnaire(ctx, q)
+}
+
+// UpdateQuestionnaire menolak perubahan periode setelah ada jawaban karena jawaban disimpan dengan periode lama
+func (u *tracerUsecase) UpdateQuestionnaire(ctx context.Context, id int, req *domain.QuestionnaireRequest) (*domain.Questionnaire, error) {
+	q, err := questionnaireFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	current, err := u.tracerRepo.FindQuestionnaireByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Period != q.Period {
+		submitted, err := u.tracerRepo.HasSubmissions(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if submitted {
+			return nil, domain.ErrTracerPeriodLocked
+		}
+	}
+	q.ID = id
+	return u.tracerRepo.UpdateQuestionnaire(ctx, q)
+}
+
+func (u *tracerUsecase) GetQuestionnaires(ctx context.Context) ([]domain.Questionnaire, error) {
+	return u.tracerRepo.FindQuestionnaires(ctx)
+}
+
+func (u *tracerUsecase) GetQuestionnaire(ctx context.Context, id int) (*domain.Questionnaire, error) {
+	return u.tracerRepo.FindQuestionnaireByID(ctx, id)
+}
+
+// CreateVersion menyimpan set pertanyaan baru; pengisian yang sudah masuk tetap memakai versi lamanya
+func (u *tracerUsecase) CreateVersion(ctx context.Context, questionnaireID int, req *domain.CreateQuestionnaireVersionRequest, createdBy *int) (*domain.QuestionnaireVersion, error) {
+	if err := validateQuestions(req.Questions); err != nil {
+		return nil, err
+	}
+	return u.tracerRepo.CreateVersion(ctx, questionnaireID, req.Questions, createdBy)
+}
+
+func (u *tracerUsecase) GetVersions(ctx context.Context, questionnaireID int) ([]domain.QuestionnaireVersion, error) {
+	if _, err := u.tracerRepo.FindQuestionnaireByID(ctx, questionnaireID); err != nil {
+		return nil, err
+	}
+	return u.tracerRepo.FindVersions(ctx, questionnaireID)
+}
+
+func (u *tracerUsecase) GetVersion(ctx context.Context, questionnaireID, version int) (*domain.QuestionnaireVersion, error) {
+	if version < 1 {
+		return nil, domain.ErrVersionNotFound
+	}
+	return u.tracerRepo.FindVersion(ctx, questionnaireID, version)
+}
+
+// validateQuestions mengumpulkan semua kesalahan agar admin bisa memperbaiki sekaligus
+func validateQuestions(questions []domain.Question) error {
+	if len(questions) == 0 {
+		return fmt.Errorf("%w: at least one question is required", domain.ErrInvalidQuestions)
+	}
+
+	var problems []string
+	seen := map[string]*domain.Question{}
+	for i := range questions {
+		q := &questions[i]
+		q.Code = strings.TrimSpace(q.Code)
+		q.Prompt = strings.TrimSpace(q.Prompt)
+		label := fmt.Sprintf("question %d", i+1)
+		if q.Code != "" {
+			label = fmt.Sprintf("question %q", q.Code)
+		}
+
+		switch {
+		case !questionCodePattern.MatchString(q.Code):
+			problems = append(problems, label+": code must be 1-50 lowercase letters, digits or underscores")
+		case seen[q.Code] != nil:
+			problems = append(problems, label+": duplicate code")
+		}
+		if q.Prompt == "" {
+			problems = append(problems, label+": prompt is required")
+		}
+
+		switch q.Type {
+		case domain.QuestionSingleChoice, domain.QuestionMultipleChoice:
+			unique := map[string]bool{}
+			for _, o := range q.Options {
+				if strings.TrimSpace(o) == "" || unique[o] {
+					problems = append(problems, label+": options must be non-empty and unique")
+					break
+				}
+				unique[o] = true
+			}
+			if len(q.Options) < 2 {
+				problems = append(problems, label+": at least two options are required")
+			}
+			q.ScaleMin, q.ScaleMax = 0, 0
+		case domain.QuestionScale:
+			if q.ScaleMin >= q.ScaleMax {
+				problems = append(problems, label+": scale_min must be less than scale_max")
+			}
+			q.Options = nil
+		case domain.QuestionText:
+			q.Options = nil
+			q.ScaleMin, q.ScaleMax = 0, 0
+		default:
+			problems = append(problems, label+": type must be single_choice, multiple_choice, scale or text")
+		}
+
+		// Kondisi hanya boleh merujuk pertanyaan sebelumnya agar urutan pengisian selalu jelas
+		if c := q.ShowIf; c != nil {
+			parent := seen[c.Question]
+			switch {
+			case parent == nil:
+				problems = append(problems, label+": show_if must refer to an earlier question")
+			case parent.Type == domain.QuestionText:
+				problems = append(problems, label+": show_if cannot refer to a text question")
+			case len(c.Equals) == 0:
+				problems = append(problems, label+": show_if.equals is required")
+			default:
+				for _, v := range c.Equals {
+					if !validChoice(parent, v) {
+						problems = append(problems, fmt.Sprintf("%s: show_if value %q is not a valid answer of %q", label, v, parent.Code))
+					}
+				}
+			}
+		}
+
+		if q.Code != "" && seen[q.Code] == nil {
+			seen[q.Code] = q
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidQuestions, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validChoice mengecek nilai pilihan atau nilai skala (dalam bentuk teks) untuk pertanyaan q
+func validChoice(q *domain.Question, v string) bool {
+	if q.Type == domain.QuestionScale {
+		n, err := strconv.Atoi(v)
+		return err == nil && n >= q.ScaleMin && n <= q.ScaleMax
+	}
+	for _, o := range q.Options {
+		if o == v {
+			return true
+		}
+	}
+	return false
+}
+
+// answerValues mengubah jawaban yang sudah divalidasi menjadi teks untuk dicocokkan dengan show_if
+func answerValues(v interface{}) []string {
+	switch a := v.(type) {
+	case string:
+		return []string{a}
+	case []string:
+		return a
+	case int:
+		return []string{strconv.Itoa(a)}
+	}
+	return nil
+}
+
+// validateAnswers mengecek jawaban terhadap pertanyaan sesuai urutannya. Jawaban untuk pertanyaan
+// yang tersembunyi (show_if tidak terpenuhi) dibuang agar data mentah tetap konsisten.
+func validateAnswers(questions []domain.Question, answers map[string]json.RawMessage) (map[string]interface{}, error) {
+	known := map[string]bool{}
+	for _, q := range questions {
+		known[q.Code] = true
+	}
+
+	var problems []string
+	for code := range answers {
+		if !known[code] {
+			problems = append(problems, fmt.Sprintf("%s: unknown question", code))
+		}
+	}
+	sort.Strings(problems)
+
+	result := map[string]interface{}{}
+	for i := range questions {
+		q := &questions[i]
+		if c := q.ShowIf; c != nil {
+			visible := false
+			for _, v := range answerValues(result[c.Question]) {
+				for _, want := range c.Equals {
+					if v == want {
+						visible = true
+					}
+				}
+			}
+			if !visible {
+				continue
+			}
+		}
+
+		raw, ok := answers[q.Code]
+		if !ok || string(raw) == "null" {
+			if q.Required {
+				problems = append(problems, q.Code+": answer is required")
+			}
+			continue
+		}
+
+		value, problem := parseAnswer(q, raw)
+		switch {
+		case problem != "":
+			problems = append(problems, q.Code+": "+problem)
+		case value == nil:
+			if q.Required {
+				problems = append(problems, q.Code+": answer is required")
+			}
+		default:
+			result[q.Code] = value
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &domain.AnswerValidationError{Errors: problems}
+	}
+	return result, nil
+}
+
+// parseAnswer mengembalikan nil untuk jawaban kosong (teks kosong atau tanpa pilihan)
+func parseAnswer(q *domain.Question, raw json.RawMessage) (interface{}, string) {
+	switch q.Type {
+	case domain.QuestionSingleChoice:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, "must be a string"
+		}
+		if v == "" {
+			return nil, ""
+		}
+		if !validChoice(q, v) {
+			return nil, fmt.Sprintf("%q is not one of the options", v)
+		}
+		return v, ""
+	case domain.QuestionMultipleChoice:
+		var v []string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, "must be an array of strings"
+		}
+		if len(v) == 0 {
+			return nil, ""
+		}
+		picked := map[string]bool{}
+		for _, o := range v {
+			if !validChoice(q, o) {
+				return nil, fmt.Sprintf("%q is not one of the options", o)
+			}
+			if picked[o] {
+				return nil, fmt.Sprintf("%q is selected more than once", o)
+			}
+			picked[o] = true
+		}
+		return v, ""
+	case domain.QuestionScale:
+		var v int
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, "must be a whole number"
+		}
+		if v < q.ScaleMin || v > q.ScaleMax {
+			return nil, fmt.Sprintf("must be between %d and %d", q.ScaleMin, q.ScaleMax)
+		}
+		return v, ""
+	default:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, "must be a string"
+		}
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return nil, ""
+		}
+		if len(v) > 5000 {
+			return nil, "must be at most 5000 characters"
+		}
+		return v, ""
+	}
+}
+
+// Invite membuat undangan untuk alumni yang dipilih dan mengirim link pengisian lewat email.
+// Alumni yang sudah diundang dilewati; gunakan ResendInvitation untuk mengirim ulang.
+func (u *tracerUsecase) Invite(ctx context.Context, questionnaireID int, req *domain.InviteAlumniRequest) (*domain.InviteAlumniResult, error) {
+	q, err := u.tracerRepo.FindQuestionnaireByID(ctx, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []domain.Alumni
+	if len(req.AlumniIDs) > 0 {
+		seen := map[int]bool{}
+		for _, id := range req.AlumniIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			alumni, err := u.alumniRepo.FindByID(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("alumni %d: %w", id, err)
+			}
+			targets = append(targets, *alumni)
+		}
+	} else {
+		filters := map[string]string{}
+		if req.Jurusan != "" {
+			filters["jurusan"] = req.Jurusan
+		}
+		if req.Angkatan != 0 {
+			filters["angkatan"] = strconv.Itoa(req.Angkatan)
+		}
+		if req.TahunLulus != 0 {
+			filters["tahun_lulus"] = strconv.Itoa(req.TahunLulus)
+		}
+		err := u.alumniRepo.StreamAll(ctx, domain.PaginationParams{Sort: "nim:asc", Filters: filters}, func(a *domain.Alumni) error {
+			targets = append(targets, *a)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &domain.InviteAlumniResult{}
+	for start := 0; start < len(targets); start += tracerInviteBatch {
+		batch := targets[start:min(start+tracerInviteBatch, len(targets))]
+		tokens := make(map[int]string, len(batch))
+		invitations := make([]repository.InvitationToken, len(batch))
+		for i, a := range batch {
+			token, err := utils.GenerateRandomToken(32)
+			if err != nil {
+				return nil, err
+			}
+			tokens[a.ID] = token
+			invitations[i] = repository.InvitationToken{AlumniID: a.ID, TokenHash: utils.HashToken(token)}
+		}
+
+		created, err := u.tracerRepo.CreateInvitations(ctx, questionnaireID, invitations)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range batch {
+			invitationID, ok := created[a.ID]
+			if !ok {
+				result.AlreadyInvited++
+				continue
+			}
+			result.Invited++
+			if !u.sendInvitation(ctx, q, invitationID, a.Nama, a.Email, tokens[a.ID]) {
+				result.EmailFailed++
+			}
+		}
+	}
+	return result, nil
+}
+
+// ResendInvitation membuat token baru (token lama tidak berlaku lagi) dan mengirim ulang email undangan.
+// Undangan yang sudah diisi tidak diubah oleh RotateInvitationToken.
+func (u *tracerUsecase) ResendInvitation(ctx context.Context, questionnaireID, invitationID int) (*domain.QuestionnaireInvitation, error) {
+	q, err := u.tracerRepo.FindQuestionnaireByID(ctx, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := u.tracerRepo.RotateInvitationToken(ctx, questionnaireID, invitationID, utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if inv.SubmittedAt != nil {
+		return nil, domain.ErrAlreadySubmitted
+	}
+	if !u.sendInvitation(ctx, q, inv.ID, inv.AlumniNama, inv.Email, token) {
+		return nil, errors.New("failed to send invitation email")
+	}
+	now := time.Now()
+	inv.SentAt = &now
+	return inv, nil
+}
+
+func (u *tracerUsecase) sendInvitation(ctx context.Context, q *domain.Questionnaire, invitationID int, nama, email, token string) bool {
+	body := fmt.Sprintf("Halo %s,\n\nAnda diundang untuk mengisi kuesioner tracer study \"%s\" periode %s.\n\n"+
+		"Isi kuesioner melalui link berikut:\n%s/tracer-study?token=%s", nama, q.Title, q.Period, u.appBaseURL, token)
+	if q.ClosesAt != nil {
+		body += fmt.Sprintf("\n\nKuesioner ditutup pada %s.", q.ClosesAt.Format("02-01-2006 15:04 MST"))
+	}
+	msg := mailer.Message{
+		To:      []string{email},
+		Subject: "Undangan tracer study: " + q.Title,
+		Body:    body,
+	}
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		log.Printf("failed to send tracer study invitation %d: %v", invitationID, err)
+		return false
+	}
+	if err := u.tracerRepo.MarkInvitationSent(ctx, invitationID); err != nil {
+		log.Printf("failed to mark tracer study invitation %d as sent: %v", invitationID, err)
+	}
+	return true
+}
+
+func (u *tracerUsecase) GetInvitations(ctx context.Context, questionnaireID int, status string, page, limit int) (*domain.PaginationResult[domain.QuestionnaireInvitation], error) {
+	switch status {
+	case "", "pending", "submitted":
+	default:
+		return nil, fmt.Errorf("%w: status must be pending or submitted", domain.ErrInvalidFilter)
+	}
+	if _, err := u.tracerRepo.FindQuestionnaireByID(ctx, questionnaireID); err != nil {
+		return nil, err
+	}
+	return u.tracerRepo.FindInvitations(ctx, questionnaireID, status, page, limit)
+}
+
+// openInvitation mengembalikan undangan dan kuesionernya jika masih bisa diisi
+func (u *tracerUsecase) openInvitation(ctx context.Context, token string) (*domain.QuestionnaireInvitation, *domain.Questionnaire, error) {
+	if token == "" {
+		return nil, nil, domain.ErrInvalidInvitation
+	}
+	inv, err := u.tracerRepo.FindInvitationByToken(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvitationNotFound) {
+			return nil, nil, domain.ErrInvalidInvitation
+		}
+		return nil, nil, err
+	}
+	q, err := u.tracerRepo.FindQuestionnaireByID(ctx, inv.QuestionnaireID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if (q.OpensAt != nil && now.Before(*q.OpensAt)) || (q.ClosesAt != nil && !now.Before(*q.ClosesAt)) {
+		return nil, nil, domain.ErrTracerClosed
+	}
+	if inv.SubmittedAt != nil {
+		return nil, nil, domain.ErrAlreadySubmitted
+	}
+	return inv, q, nil
+}
+
+// findVersion dengan version 0 memakai versi terbaru
+func (u *tracerUsecase) findVersion(ctx context.Context, questionnaireID, version int) (*domain.QuestionnaireVersion, error) {
+	v, err := u.tracerRepo.FindVersion(ctx, questionnaireID, version)
+	if errors.Is(err, domain.ErrVersionNotFound) && version == 0 {
+		return nil, domain.ErrTracerNoVersion
+	}
+	return v, err
+}
+
+func (u *tracerUsecase) GetForm(ctx context.Context, token string) (*domain.TracerForm, error) {
+	inv, q, err := u.openInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	// Alumni mungkin sudah mengisi kuesioner lain pada periode yang sama
+	submitted, err := u.tracerRepo.HasSubmittedPeriod(ctx, inv.AlumniID, q.Period)
+	if err != nil {
+		return nil, err
+	}
+	if submitted {
+		return nil, domain.ErrAlreadySubmitted
+	}
+	version, err := u.findVersion(ctx, q.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TracerForm{
+		QuestionnaireID: q.ID,
+		Title:           q.Title,
+		Description:     q.Description,
+		Period:          q.Period,
+		ClosesAt:        q.ClosesAt,
+		Version:         version.Version,
+		Questions:       version.Questions,
+		AlumniNama:      inv.AlumniNama,
+	}, nil
+}
+
+// Submit memvalidasi jawaban terhadap versi yang ditampilkan ke alumni, sehingga form yang dibuka
+// sebelum versi baru dibuat tetap bisa dikirim
+func (u *tracerUsecase) Submit(ctx context.Context, req *domain.SubmitTracerRequest) (*domain.QuestionnaireSubmission, error) {
+	inv, q, err := u.openInvitation(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if req.Version < 0 {
+		return nil, domain.ErrVersionNotFound
+	}
+	version, err := u.findVersion(ctx, q.ID, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := validateAnswers(version.Questions, req.Answers)
+	if err != nil {
+		return nil, err
+	}
+	return u.tracerRepo.CreateSubmission(ctx, inv, version, q.Period, answers)
+}
+
+func (u *tracerUsecase) GetResponses(ctx context.Context, questionnaireID, page, limit int) (*domain.PaginationResult[domain.QuestionnaireSubmission], error) {
+	if _, err := u.tracerRepo.FindQuestionnaireByID(ctx, questionnaireID); err != nil {
+		return nil, err
+	}
+	return u.tracerRepo.FindSubmissions(ctx, questionnaireID, page, limit)
+}
+
+func (u *tracerUsecase) GetStats(ctx context.Context, questionnaireID int) (*domain.QuestionnaireStats, error) {
+	if _, err := u.tracerRepo.FindQuestionnaireByID(ctx, questionnaireID); err != nil {
+		return nil, err
+	}
+	byJurusan, err := u.tracerRepo.ResponseRates(ctx, questionnaireID, "jurusan")
+	if err != nil {
+		return nil, err
+	}
+	byTahunLulus, err := u.tracerRepo.ResponseRates(ctx, questionnaireID, "tahun_lulus")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.QuestionnaireStats{ByJurusan: byJurusan, ByTahunLulus: byTahunLulus}
+	for i := range byJurusan {
+		stats.Invited += byJurusan[i].Invited
+		stats.Submitted += byJurusan[i].Submitted
+	}
+	stats.Rate = responseRate(stats.Submitted, stats.Invited)
+	for _, rates := range [][]domain.ResponseRate{byJurusan, byTahunLulus} {
+		for i := range rates {
+			rates[i].Rate = responseRate(rates[i].Submitted, rates[i].Invited)
+		}
+	}
+	return stats, nil
+}
+
+func responseRate(submitted, invited int) float64 {
+	if invited == 0 {
+		return 0
+	}
+	return float64(submitted) / float64(invited)
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUpdateQuestionnairePeriodLock(t *testing.T) {
+	tests := []struct {
+		name      string
+		submitted bool
+		period    string
+		wantErr   error
+	}{
+		{"period change without submissions", false, "2027", nil},
+		{"period change with submissions", true, "2027", domain.ErrTracerPeriodLocked},
+		{"same period with submissions", true, "2026", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newFakeTracerRepo(&domain.Questionnaire{ID: 1, Title: "Tracer", Period: "2026"})
+			tr.submitted[1] = tt.submitted
+			u := NewTracerUsecase(tr, nil, nil, "")
+
+			q, err := u.UpdateQuestionnaire(context.Background(), 1, &domain.QuestionnaireRequest{Title: "Tracer Study", Period: tt.period})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if got := tr.questionnaires[1].Title; got != "Tracer" {
+					t.Fatalf("questionnaire was updated to %q despite the error", got)
+				}
+				return
+			}
+			if q.Period != tt.period || q.Title != "Tracer Study" {
+				t.Fatalf("got %+v", q)
+			}
+		})
+	}
+
+	u := NewTracerUsecase(newFakeTracerRepo(), nil, nil, "")
+	if _, err := u.UpdateQuestionnaire(context.Background(), 2, &domain.QuestionnaireRequest{Title: "x", Period: "2026"}); !errors.Is(err, domain.ErrTracerNotFound) {
+		t.Fatalf("missing questionnaire: err = %v, want ErrTracerNotFound", err)
+	}
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"back-train/internal/domain"
 	"context"
+	"io"
 )
 
 // Definisikan interface untuk setiap usecase agar dependensi bisa di-inject
@@ -10,34 +11,181 @@ import (
 
 type AuthUsecase interface {
 	Register(ctx context.Context, email, password string) (*domain.User, error)
-	Login(ctx context.Context, email, password string) (string, error)
+	Login(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResponse, error)
+	// CompleteLogin menerbitkan token (atau challenge MFA) untuk user yang identitasnya sudah
+	// diverifikasi oleh provider login lain, misalnya SSO
+	CompleteLogin(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.LoginResponse, error)
+	Refresh(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.LoginResponse, error)
+	Logout(ctx context.Context, sessionID string) error
+	ValidateSession(ctx context.Context, sessionID string) (bool, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+
+	// Langkah kedua login untuk akun dengan 2FA
+	VerifyMFA(ctx context.Context, req *domain.MFAVerifyRequest, client domain.ClientInfo) (*domain.LoginResponse, error)
+	BeginMFAEnrollment(ctx context.Context, mfaToken string) (*domain.MFASetupResponse, error)
+	ConfirmMFAEnrollment(ctx context.Context, req *domain.MFAEnrollmentConfirmRequest, client domain.ClientInfo) (*domain.MFAEnrollmentResponse, error)
+
+	// Pengelolaan 2FA milik user yang sedang login
+	GetMFAStatus(ctx context.Context, userID int) (*domain.MFAStatusResponse, error)
+	SetupMFA(ctx context.Context, userID int) (*domain.MFASetupResponse, error)
+	EnableMFA(ctx context.Context, userID int, code string) (*domain.MFARecoveryCodesResponse, error)
+	DisableMFA(ctx context.Context, userID int, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*domain.MFARecoveryCodesResponse, error)
+	ResetMFA(ctx context.Context, userID int) error
+
+	// Ganti password dan email oleh user yang sedang login; session lain dicabut setelah berhasil
+	ChangePassword(ctx context.Context, userID int, currentSessionID string, req *domain.ChangePasswordRequest, client domain.ClientInfo) error
+	// RequestEmailChange mengirim link konfirmasi ke email baru; email baru berlaku setelah ConfirmEmailChange
+	RequestEmailChange(ctx context.Context, userID int, currentSessionID string, req *domain.ChangeEmailRequest, client domain.ClientInfo) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+
+	// Daftar session aktif dan sign-out jarak jauh; actorID berbeda dari userID jika dilakukan admin
+	ListSessions(ctx context.Context, userID int, currentSessionID string) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, actorID, userID int, sessionID string) error
+	// RevokeAllSessions mencabut semua session user kecuali keepSessionID (kosong berarti semuanya)
+	RevokeAllSessions(ctx context.Context, actorID, userID int, keepSessionID string) error
+
+	// Impersonate menerbitkan access token singkat atas nama userID dengan claim act berisi admin actorID
+	Impersonate(ctx context.Context, actorID, userID int, client domain.ClientInfo) (*domain.ImpersonationResponse, error)
+	// RecordImpersonatedRequest mencatat satu request yang dilakukan admin sebagai user lain
+	RecordImpersonatedRequest(ctx context.Context, actorID, userID int, method, path string, status int, client domain.ClientInfo)
+
+	// UnlockAccount menghapus lockout akun akibat gagal login berulang (aksi admin)
+	UnlockAccount(ctx context.Context, actorID, userID int) error
 }
 
 type UsersDeleteUsecase interface {
 	DeleteUsers(ctx context.Context, id int) error
 }
 
+type UserUsecase interface {
+	UsersDeleteUsecase
+	GetAllUsers(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.User], error)
+	GetUserByID(ctx context.Context, id int) (*domain.User, error)
+	UpdateUserRoles(ctx context.Context, id int, req *domain.UpdateUserRolesRequest) (*domain.User, error)
+	UpdateUserStatus(ctx context.Context, id int, req *domain.UpdateUserStatusRequest) (*domain.User, error)
+	MarkEmailVerified(ctx context.Context, id int) (*domain.User, error)
+}
+
+type RoleUsecase interface {
+	GetAllRoles(ctx context.Context) ([]domain.Role, error)
+	GetRoleByID(ctx context.Context, id int) (*domain.Role, error)
+	CreateRole(ctx context.Context, req *domain.CreateRoleRequest) (*domain.Role, error)
+	UpdateRole(ctx context.Context, id int, req *domain.UpdateRoleRequest) (*domain.Role, error)
+	UpdateRolePermissions(ctx context.Context, id int, req *domain.UpdateRolePermissionsRequest) (*domain.Role, error)
+	DeleteRole(ctx context.Context, id int) error
+	GetAllPermissions(ctx context.Context) ([]domain.Permission, error)
+}
+
+type AuditUsecase interface {
+	GetAuditLogs(ctx context.Context, filter domain.AuditFilter) (*domain.PaginationResult[domain.AuditLog], error)
+}
+
+// HistoryUsecase menyusun versi alumni, mahasiswa dan pekerjaan dari audit log
+type HistoryUsecase interface {
+	GetHistory(ctx context.Context, entityType string, id int) ([]domain.EntityVersion, error)
+	DiffVersions(ctx context.Context, entityType string, id, fromVersion, toVersion int) (*domain.VersionDiff, error)
+	RestoreVersion(ctx context.Context, entityType string, id, version int, meta domain.AuditMeta) (interface{}, error)
+}
+
+// OIDCUsecase menangani login SSO melalui identity provider OpenID Connect
+type OIDCUsecase interface {
+	BeginLogin(ctx context.Context) (*domain.OIDCAuthRequest, error)
+	Callback(ctx context.Context, code, state, stateCookie string, client domain.ClientInfo) (*domain.LoginResponse, error)
+}
+
+// Authenticator memverifikasi email dan password untuk Login. Jika kredensial salah, kembalikan
+// domain.ErrInvalidCredentials (boleh disertai user jika akunnya dikenal, agar kegagalan tercatat).
+type Authenticator interface {
+	Name() string
+	Authenticate(ctx context.Context, email, password string) (*domain.User, error)
+}
+
+// APIKeyUsecase mengelola API key milik user yang sedang login
+type APIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, userID int, req *domain.CreateAPIKeyRequest) (*domain.CreateAPIKeyResponse, error)
+	GetMyAPIKeys(ctx context.Context, userID int) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, id int) error
+	// AuthenticateAPIKey mengembalikan user pemilik key dengan permission yang sudah dibatasi scope key
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*domain.User, *domain.APIKey, error)
+}
+
 type AlumniUsecase interface {
-	CreateAlumni(ctx context.Context, req *domain.CreateAlumniRequest) (*domain.Alumni, error)
+	CreateAlumni(ctx context.Context, req *domain.CreateAlumniRequest, meta domain.AuditMeta) (*domain.Alumni, error)
 	GetAllAlumni(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Alumni], error)
+	ExportAlumni(ctx context.Context, params domain.PaginationParams, format string, columns []string) (*ExportFile, error)
 	GetAlumniByID(ctx context.Context, id int) (*domain.Alumni, error)
-	UpdateAlumni(ctx context.Context, id int, req *domain.UpdateAlumniRequest) (*domain.Alumni, error)
-	DeleteAlumni(ctx context.Context, id int) error
+	UpdateAlumni(ctx context.Context, id int, req *domain.UpdateAlumniRequest, meta domain.AuditMeta) (*domain.Alumni, error)
+	LinkUser(ctx context.Context, id int, req *domain.LinkAlumniUserRequest, meta domain.AuditMeta) (*domain.Alumni, error)
+	DeleteAlumni(ctx context.Context, id int, meta domain.AuditMeta) error
+	ImportAlumni(ctx context.Context, filename string, r io.Reader, dryRun bool, meta domain.AuditMeta) (*domain.ImportReport, error)
+}
+
+// SelfServiceUsecase berisi aksi alumni terhadap datanya sendiri; userID selalu diambil dari token
+type SelfServiceUsecase interface {
+	ClaimAlumni(ctx context.Context, userID int, nim string, meta domain.AuditMeta) (*domain.Alumni, error)
+	ConfirmAlumniClaim(ctx context.Context, userID int, token string, meta domain.AuditMeta) (*domain.Alumni, error)
+	GetMyAlumni(ctx context.Context, userID int) (*domain.Alumni, error)
+	UpdateMyAlumni(ctx context.Context, userID int, req *domain.UpdateMyAlumniRequest, meta domain.AuditMeta) (*domain.Alumni, error)
+	GetMyPekerjaan(ctx context.Context, userID int) ([]domain.Pekerjaan, error)
+	CreateMyPekerjaan(ctx context.Context, userID int, req *domain.CreatePekerjaanRequest, meta domain.AuditMeta) (*domain.Pekerjaan, error)
+	EndMyPekerjaan(ctx context.Context, userID int, pekerjaanID int, req *domain.EndPekerjaanRequest, meta domain.AuditMeta) (*domain.Pekerjaan, error)
 }
 
 type MahasiswaUsecase interface {
-	CreateMahasiswa(ctx context.Context, req *domain.CreateMahasiswaRequest) (*domain.Mahasiswa, error)
+	CreateMahasiswa(ctx context.Context, req *domain.CreateMahasiswaRequest, meta domain.AuditMeta) (*domain.Mahasiswa, error)
 	GetAllMahasiswa(ctx context.Context) ([]domain.Mahasiswa, error)
 	GetMahasiswaByID(ctx context.Context, id int) (*domain.Mahasiswa, error)
-	UpdateMahasiswa(ctx context.Context, id int, req *domain.UpdateMahasiswaRequest) (*domain.Mahasiswa, error)
-	DeleteMahasiswa(ctx context.Context, id int) error
+	UpdateMahasiswa(ctx context.Context, id int, req *domain.UpdateMahasiswaRequest, meta domain.AuditMeta) (*domain.Mahasiswa, error)
+	DeleteMahasiswa(ctx context.Context, id int, meta domain.AuditMeta) error
 }
 
 type PekerjaanUsecase interface {
-	CreatePekerjaan(ctx context.Context, req *domain.CreatePekerjaanRequest) (*domain.Pekerjaan, error)
+	CreatePekerjaan(ctx context.Context, req *domain.CreatePekerjaanRequest, meta domain.AuditMeta) (*domain.Pekerjaan, error)
 	GetAllPekerjaan(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.Pekerjaan], error)
+	ExportPekerjaan(ctx context.Context, params domain.PaginationParams, format string, columns []string) (*ExportFile, error)
 	GetPekerjaanByID(ctx context.Context, id int) (*domain.Pekerjaan, error)
-	UpdatePekerjaan(ctx context.Context, id int, req *domain.UpdatePekerjaanRequest) (*domain.Pekerjaan, error)
-	DeletePekerjaan(ctx context.Context, id int) error
+	GetPekerjaanByAlumniID(ctx context.Context, alumniID int) ([]domain.Pekerjaan, error)
+	UpdatePekerjaan(ctx context.Context, id int, req *domain.UpdatePekerjaanRequest, meta domain.AuditMeta) (*domain.Pekerjaan, error)
+	DeletePekerjaan(ctx context.Context, id int, meta domain.AuditMeta) error
+}
+
+type JobUsecase interface {
+	EnqueueAlumniImport(ctx context.Context, filename string, r io.Reader, dryRun bool, meta domain.AuditMeta) (*domain.Job, error)
+	EnqueueAlumniExport(ctx context.Context, params domain.PaginationParams, format string, columns []string, meta domain.AuditMeta) (*domain.Job, error)
+	EnqueuePekerjaanExport(ctx context.Context, params domain.PaginationParams, format string, columns []string, meta domain.AuditMeta) (*domain.Job, error)
+	// manageAll mengizinkan akses ke job milik user lain (permission jobs:manage)
+	GetJob(ctx context.Context, id int64, userID int, manageAll bool) (*domain.Job, error)
+	GetJobs(ctx context.Context, filter domain.JobFilter) (*domain.PaginationResult[domain.Job], error)
+	CancelJob(ctx context.Context, id int64, userID int, manageAll bool) (*domain.Job, error)
+	GetJobArtifact(ctx context.Context, id int64, userID int, manageAll bool) (*domain.Job, error)
 }
 
+type TracerUsecase interface {
+	CreateQuestionnaire(ctx context.Context, req *domain.QuestionnaireRequest) (*domain.Questionnaire, error)
+	UpdateQuestionnaire(ctx context.Context, id int, req *domain.QuestionnaireRequest) (*domain.Questionnaire, error)
+	GetQuestionnaires(ctx context.Context) ([]domain.Questionnaire, error)
+	GetQuestionnaire(ctx context.Context, id int) (*domain.Questionnaire, error)
+	CreateVersion(ctx context.Context, questionnaireID int, req *domain.CreateQuestionnaireVersionRequest, createdBy *int) (*domain.QuestionnaireVersion, error)
+	GetVersions(ctx context.Context, questionnaireID int) ([]domain.QuestionnaireVersion, error)
+	GetVersion(ctx context.Context, questionnaireID, version int) (*domain.QuestionnaireVersion, error)
+	Invite(ctx context.Context, questionnaireID int, req *domain.InviteAlumniRequest) (*domain.InviteAlumniResult, error)
+	ResendInvitation(ctx context.Context, questionnaireID, invitationID int) (*domain.QuestionnaireInvitation, error)
+	GetInvitations(ctx context.Context, questionnaireID int, status string, page, limit int) (*domain.PaginationResult[domain.QuestionnaireInvitation], error)
+	GetResponses(ctx context.Context, questionnaireID, page, limit int) (*domain.PaginationResult[domain.QuestionnaireSubmission], error)
+	GetStats(ctx context.Context, questionnaireID int) (*domain.QuestionnaireStats, error)
+
+	// Dipakai alumni lewat token undangan, tanpa login
+	GetForm(ctx context.Context, token string) (*domain.TracerForm, error)
+	Submit(ctx context.Context, req *domain.SubmitTracerRequest) (*domain.QuestionnaireSubmission, error)
+}
+
+// StatsUsecase menyediakan angka agregat untuk laporan akreditasi; semua dihitung di database
+type StatsUsecase interface {
+	GetEmployment(ctx context.Context, filter domain.StatsFilter) (*domain.EmploymentSummary, error)
+	GetIndustryDistribution(ctx context.Context, filter domain.StatsFilter) (*domain.StatsDistribution, error)
+	GetSalaryDistribution(ctx context.Context, filter domain.StatsFilter) (*domain.StatsDistribution, error)
+}
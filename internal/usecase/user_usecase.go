@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"back-train/internal/repository"
+	"context"
+)
+
+type userUsecase struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+}
+
+func NewUserUsecase(ur repository.UserRepository, sr repository.SessionRepository) UserUsecase {
+	return &userUsecase{userRepo: ur, sessionRepo: sr}
+}
+
+func (u *userUsecase) GetAllUsers(ctx context.Context, params domain.PaginationParams) (*domain.PaginationResult[domain.User], error) {
+	return u.userRepo.FindAll(ctx, params)
+}
+
+func (u *userUsecase) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
+	return u.userRepo.GetUserByID(ctx, id)
+}
+
+func (u *userUsecase) UpdateUserRoles(ctx context.Context, id int, req *domain.UpdateUserRolesRequest) (*domain.User, error) {
+	if len(req.Roles) == 0 {
+		return nil, domain.ErrRolesRequired
+	}
+
+	if err := u.userRepo.SetRoles(ctx, id, uniqueStrings(req.Roles)); err != nil {
+		return nil, err
+	}
+	return u.userRepo.GetUserByID(ctx, id)
+}
+
+func (u *userUsecase) UpdateUserStatus(ctx context.Context, id int, req *domain.UpdateUserStatusRequest) (*domain.User, error) {
+	if err := u.userRepo.SetActive(ctx, id, req.IsActive); err != nil {
+		return nil, err
+	}
+
+	// Akun yang dinonaktifkan langsung kehilangan semua session-nya
+	if !req.IsActive {
+		if err := u.sessionRepo.RevokeAllForUser(ctx, id, "account_disabled"); err != nil {
+			return nil, err
+		}
+	}
+	return u.userRepo.GetUserByID(ctx, id)
+}
+
+func (u *userUsecase) MarkEmailVerified(ctx context.Context, id int) (*domain.User, error) {
+	if err := u.userRepo.MarkEmailVerified(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.userRepo.GetUserByID(ctx, id)
+}
+
+func (u *userUsecase) DeleteUsers(ctx context.Context, id int) error {
+	if err := u.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// Baris session ikut terhapus lewat cascade, tapi cache session harus tetap dibersihkan
+	// agar access token milik user yang dihapus langsung ditolak
+	return u.sessionRepo.RevokeAllForUser(ctx, id, "account_deleted")
+}
+
+// uniqueStrings membuang duplikat agar jumlah baris yang di-insert bisa dicocokkan di repository
+func uniqueStrings(list []string) []string {
+	seen := map[string]bool{}
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
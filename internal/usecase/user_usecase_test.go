@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"back-train/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeleteUsersRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	sr := newFakeSessionRepo()
+	ur := newFakeUserRepo(&domain.User{ID: 1, Email: "a@example.com", IsActive: true})
+	seedSession(t, sr, 1)
+
+	u := NewUserUsecase(ur, sr)
+	if err := u.DeleteUsers(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if s := sr.sessions["s1"]; s.RevokedAt == nil || *s.RevokedReason != "account_deleted" {
+		t.Fatalf("session not revoked: %+v", s)
+	}
+
+	// User yang tidak ada tidak boleh mencabut session siapa pun
+	other := newFakeSessionRepo()
+	other.sessions["s2"] = &domain.Session{ID: "s2", UserID: 2, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := NewUserUsecase(ur, other).DeleteUsers(ctx, 2); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("err = %v, want ErrUserNotFound", err)
+	}
+	if other.sessions["s2"].RevokedAt != nil {
+		t.Fatal("session revoked although delete failed")
+	}
+}
@@ -0,0 +1,198 @@
+// Package jwtkeys mengelola kunci untuk menandatangani dan memverifikasi access token.
+// Satu kunci aktif dipakai untuk tanda tangan, sementara beberapa kunci publik lama
+// tetap diterima saat verifikasi agar kunci bisa dirotasi tanpa memutus session.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Key adalah satu kunci beserta algoritmanya; Private nil untuk kunci yang hanya dipakai verifikasi
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private interface{}
+	Public  interface{}
+}
+
+// KeySet berisi kunci penandatangan aktif dan semua kunci yang diterima saat verifikasi
+type KeySet struct {
+	signing      *Key
+	verification map[string]*Key
+}
+
+// NewHMACKeySet membuat KeySet HS256 dari secret bersama (mode lama, tidak dipublikasikan di JWKS)
+func NewHMACKeySet(kid, secret string) *KeySet {
+	key := &Key{ID: kid, Method: jwt.SigningMethodHS256, Private: []byte(secret), Public: []byte(secret)}
+	return &KeySet{signing: key, verification: map[string]*Key{kid: key}}
+}
+
+// NewKeySet membuat KeySet asimetris dari kunci penandatangan dan kunci verifikasi tambahan
+func NewKeySet(signing *Key, verification ...*Key) (*KeySet, error) {
+	if signing == nil || signing.Private == nil {
+		return nil, errors.New("jwtkeys: signing key requires a private key")
+	}
+	ks := &KeySet{signing: signing, verification: map[string]*Key{signing.ID: signing}}
+	for _, key := range verification {
+		if _, exists := ks.verification[key.ID]; exists {
+			return nil, fmt.Errorf("jwtkeys: duplicate key id %q", key.ID)
+		}
+		ks.verification[key.ID] = key
+	}
+	return ks, nil
+}
+
+// LoadPrivateKey membaca private key PEM (PKCS#8, atau PKCS#1 untuk RSA) untuk algoritma alg
+func LoadPrivateKey(path, kid, alg string) (*Key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: parse private key %s: %w", path, err)
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("jwtkeys: unsupported private key in %s", path)
+	}
+	key := &Key{ID: kid, Private: parsed, Public: signer.Public()}
+	if err := key.setMethod(alg); err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, path)
+	}
+	return key, nil
+}
+
+// LoadPublicKey membaca public key PEM (PKIX) yang hanya dipakai untuk verifikasi.
+// Algoritma ditentukan dari tipe kunci, jadi rotasi juga bisa berpindah antara RS256 dan EdDSA.
+func LoadPublicKey(path, kid string) (*Key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed interface{}
+	if block.Type == "RSA PUBLIC KEY" {
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: parse public key %s: %w", path, err)
+	}
+
+	alg := "RS256"
+	if _, ok := parsed.(ed25519.PublicKey); ok {
+		alg = "EdDSA"
+	}
+	key := &Key{ID: kid, Public: parsed}
+	if err := key.setMethod(alg); err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, path)
+	}
+	return key, nil
+}
+
+// setMethod memastikan tipe kunci cocok dengan algoritma yang diminta
+func (k *Key) setMethod(alg string) error {
+	switch alg {
+	case "RS256":
+		if _, ok := k.Public.(*rsa.PublicKey); !ok {
+			return errors.New("jwtkeys: RS256 requires an RSA key")
+		}
+		k.Method = jwt.SigningMethodRS256
+	case "EdDSA":
+		if _, ok := k.Public.(ed25519.PublicKey); !ok {
+			return errors.New("jwtkeys: EdDSA requires an Ed25519 key")
+		}
+		k.Method = jwt.SigningMethodEdDSA
+	default:
+		return fmt.Errorf("jwtkeys: unsupported algorithm %q", alg)
+	}
+	return nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwtkeys: no PEM block found in %s", path)
+	}
+	return block, nil
+}
+
+// Sign menandatangani claims dengan kunci aktif dan menambahkan header kid
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.Private)
+}
+
+// Keyfunc memilih kunci verifikasi berdasarkan kid dan menolak token yang algoritmanya tidak cocok
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.verification[kid]
+	if !ok {
+		return nil, fmt.Errorf("jwtkeys: unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("jwtkeys: unexpected signing method %s", token.Method.Alg())
+	}
+	return key.Public, nil
+}
+
+// JWK adalah representasi public key sesuai RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS mengembalikan semua public key verifikasi; kunci HMAC tidak pernah dipublikasikan
+func (ks *KeySet) JWKS() []JWK {
+	keys := []JWK{}
+	for _, key := range ks.verification {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		keys = append(keys, jwk)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Kid < keys[j].Kid })
+	return keys
+}
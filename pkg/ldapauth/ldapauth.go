@@ -0,0 +1,212 @@
+// Package ldapauth memverifikasi kredensial ke LDAP/Active Directory dengan pola
+// search-then-bind: akun layanan mencari DN user berdasarkan email, lalu password user
+// diverifikasi dengan bind sebagai DN tersebut dan grupnya dibaca dari directory.
+package ldapauth
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ErrInvalidCredentials dikembalikan jika user tidak ditemukan atau password salah
+var ErrInvalidCredentials = errors.New("ldapauth: invalid credentials")
+
+// Config berisi pengaturan koneksi dan skema directory
+type Config struct {
+	URL                string // ldap://host:389 atau ldaps://host:636
+	StartTLS           bool
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+
+	BindDN       string // akun layanan untuk mencari user; kosong berarti anonymous search
+	BindPassword string
+
+	BaseDN         string
+	UserFilter     string // %s diganti email yang sudah di-escape, misalnya (mail=%s)
+	EmailAttribute string
+	GroupAttribute string // atribut di entry user yang berisi DN grup, misalnya memberOf
+
+	// Untuk server tanpa memberOf (mis. OpenLDAP tanpa overlay): cari grup yang memuat DN user
+	GroupBaseDN string
+	GroupFilter string // %s diganti DN user yang sudah di-escape, misalnya (member=%s)
+}
+
+// Entry adalah user directory yang berhasil diautentikasi
+type Entry struct {
+	DN     string
+	Email  string
+	Groups []string // DN lengkap grup
+}
+
+// Conn adalah bagian dari *ldap.Conn yang dipakai Client, agar bisa diganti stand-in saat pengujian
+type Conn interface {
+	Bind(username, password string) error
+	Search(req *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close() error
+}
+
+// Client mengautentikasi user ke satu server directory
+type Client struct {
+	cfg  Config
+	dial func() (Conn, error)
+}
+
+// New membuat Client yang terhubung ke cfg.URL untuk setiap login
+func New(cfg Config) *Client {
+	c := &Client{cfg: withDefaults(cfg)}
+	c.dial = c.dialURL
+	return c
+}
+
+// NewWithDialer membuat Client dengan fungsi koneksi sendiri, misalnya server LDAP in-process
+func NewWithDialer(cfg Config, dial func() (Conn, error)) *Client {
+	return &Client{cfg: withDefaults(cfg), dial: dial}
+}
+
+func withDefaults(cfg Config) Config {
+	if cfg.UserFilter == "" {
+		cfg.UserFilter = "(mail=%s)"
+	}
+	if cfg.EmailAttribute == "" {
+		cfg.EmailAttribute = "mail"
+	}
+	if cfg.GroupAttribute == "" {
+		cfg.GroupAttribute = "memberOf"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return cfg
+}
+
+func (c *Client) dialURL() (Conn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.cfg.InsecureSkipVerify}
+	conn, err := ldap.DialURL(c.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: c.cfg.Timeout}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(c.cfg.Timeout)
+
+	if c.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// Authenticate mencari user berdasarkan email lalu melakukan bind dengan password-nya
+func (c *Client) Authenticate(email, password string) (*Entry, error) {
+	// Bind dengan password kosong adalah "unauthenticated bind" yang selalu sukses di banyak server
+	if email == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return nil, fmt.Errorf("ldapauth: connect: %w", err)
+	}
+	defer conn.Close()
+
+	if c.cfg.BindDN != "" {
+		if err := conn.Bind(c.cfg.BindDN, c.cfg.BindPassword); err != nil {
+			return nil, fmt.Errorf("ldapauth: service bind: %w", err)
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf(c.cfg.UserFilter, ldap.EscapeFilter(email)),
+		[]string{c.cfg.EmailAttribute, c.cfg.GroupAttribute},
+		nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) || ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ldapauth: user search: %w", err)
+	}
+	// Email yang cocok dengan lebih dari satu entry tidak bisa dipastikan pemiliknya
+	if len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	user := result.Entries[0]
+
+	if err := conn.Bind(user.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ldapauth: user bind: %w", err)
+	}
+
+	entry := &Entry{
+		DN:     user.DN,
+		Email:  user.GetAttributeValue(c.cfg.EmailAttribute),
+		Groups: user.GetAttributeValues(c.cfg.GroupAttribute),
+	}
+	if entry.Email == "" {
+		entry.Email = email
+	}
+
+	if c.cfg.GroupFilter != "" {
+		groups, err := c.searchGroups(conn, user.DN)
+		if err != nil {
+			return nil, err
+		}
+		entry.Groups = append(entry.Groups, groups...)
+	}
+	return entry, nil
+}
+
+// searchGroups mencari grup yang anggotanya memuat DN user. Pencarian memakai hak akses user
+// itu sendiri karena koneksi sudah di-bind sebagai user pada langkah sebelumnya.
+func (c *Client) searchGroups(conn Conn, userDN string) ([]string, error) {
+	baseDN := c.cfg.GroupBaseDN
+	if baseDN == "" {
+		baseDN = c.cfg.BaseDN
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		baseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(c.cfg.GroupFilter, ldap.EscapeFilter(userDN)),
+		[]string{"cn"},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("ldapauth: group search: %w", err)
+	}
+
+	groups := make([]string, 0, len(result.Entries))
+	for _, group := range result.Entries {
+		groups = append(groups, group.DN)
+	}
+	return groups, nil
+}
+
+// GroupNames mengembalikan DN lengkap dan CN setiap grup, sehingga pemetaan role
+// bisa ditulis dengan salah satu bentuk (cn=staff,ou=groups,dc=example,dc=org atau staff)
+func GroupNames(groupDNs []string) []string {
+	names := make([]string, 0, len(groupDNs)*2)
+	for _, groupDN := range groupDNs {
+		names = append(names, groupDN)
+		parsed, err := ldap.ParseDN(groupDN)
+		if err != nil || len(parsed.RDNs) == 0 {
+			continue
+		}
+		for _, attr := range parsed.RDNs[0].Attributes {
+			if strings.EqualFold(attr.Type, "cn") {
+				names = append(names, attr.Value)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,275 @@
+package ldapauth
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// fakeConn adalah directory in-memory. Search dicocokkan persis dengan string filter,
+// sehingga test sekaligus memastikan filter dibentuk dan di-escape dengan benar.
+type fakeConn struct {
+	passwords map[string]string        // DN -> password
+	results   map[string][]*ldap.Entry // filter -> entry
+	searchErr map[string]error         // filter -> error
+	binds     []string                 // DN yang berhasil bind, berurutan
+	bases     map[string]string        // filter -> base DN yang dipakai
+	closed    bool
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{
+		passwords: map[string]string{"cn=svc,dc=example,dc=org": "svc-secret"},
+		results:   map[string][]*ldap.Entry{},
+		searchErr: map[string]error{},
+		bases:     map[string]string{},
+	}
+}
+
+func (f *fakeConn) Bind(username, password string) error {
+	if want, ok := f.passwords[username]; !ok || want != password {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	f.binds = append(f.binds, username)
+	return nil
+}
+
+func (f *fakeConn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	f.bases[req.Filter] = req.BaseDN
+	if err := f.searchErr[req.Filter]; err != nil {
+		return nil, err
+	}
+	return &ldap.SearchResult{Entries: f.results[req.Filter]}, nil
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+const aliceDN = "uid=alice,ou=people,dc=example,dc=org"
+
+func testConfig() Config {
+	return Config{
+		BindDN:       "cn=svc,dc=example,dc=org",
+		BindPassword: "svc-secret",
+		BaseDN:       "dc=example,dc=org",
+	}
+}
+
+func withAlice(f *fakeConn) *fakeConn {
+	f.passwords[aliceDN] = "alice-pass"
+	f.results["(mail=alice@example.org)"] = []*ldap.Entry{ldap.NewEntry(aliceDN, map[string][]string{
+		"mail":     {"alice@example.org"},
+		"memberOf": {"cn=staff,ou=groups,dc=example,dc=org"},
+	})}
+	return f
+}
+
+func TestAuthenticate(t *testing.T) {
+	unavailable := ldap.NewError(ldap.LDAPResultUnavailable, errors.New("server down"))
+
+	tests := []struct {
+		name     string
+		conn     func() *fakeConn
+		cfg      func(Config) Config
+		email    string
+		password string
+		want     *Entry
+		wantErr  error // nil dengan wantFail berarti error selain ErrInvalidCredentials
+		wantFail bool
+	}{
+		{
+			name:     "search then bind",
+			conn:     func() *fakeConn { return withAlice(newFakeConn()) },
+			email:    "alice@example.org",
+			password: "alice-pass",
+			want:     &Entry{DN: aliceDN, Email: "alice@example.org", Groups: []string{"cn=staff,ou=groups,dc=example,dc=org"}},
+		},
+		{
+			name:     "wrong password",
+			conn:     func() *fakeConn { return withAlice(newFakeConn()) },
+			email:    "alice@example.org",
+			password: "nope",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "empty password never binds",
+			conn:     func() *fakeConn { return withAlice(newFakeConn()) },
+			email:    "alice@example.org",
+			password: "",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "unknown email",
+			conn:     newFakeConn,
+			email:    "bob@example.org",
+			password: "x",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name: "email matches several entries",
+			conn: func() *fakeConn {
+				f := withAlice(newFakeConn())
+				f.results["(mail=alice@example.org)"] = append(f.results["(mail=alice@example.org)"],
+					ldap.NewEntry("uid=alice2,ou=people,dc=example,dc=org", nil))
+				return f
+			},
+			email:    "alice@example.org",
+			password: "alice-pass",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name: "size limit exceeded",
+			conn: func() *fakeConn {
+				f := newFakeConn()
+				f.searchErr["(mail=alice@example.org)"] = ldap.NewError(ldap.LDAPResultSizeLimitExceeded, errors.New("size limit"))
+				return f
+			},
+			email:    "alice@example.org",
+			password: "alice-pass",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "filter injection is escaped",
+			conn:     func() *fakeConn { return withAlice(newFakeConn()) },
+			email:    "*)(mail=alice@example.org",
+			password: "alice-pass",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name: "service bind fails",
+			conn: func() *fakeConn { return withAlice(newFakeConn()) },
+			cfg: func(c Config) Config {
+				c.BindPassword = "wrong"
+				return c
+			},
+			email:    "alice@example.org",
+			password: "alice-pass",
+			wantFail: true,
+		},
+		{
+			name: "directory unavailable",
+			conn: func() *fakeConn {
+				f := newFakeConn()
+				f.searchErr["(mail=alice@example.org)"] = unavailable
+				return f
+			},
+			email:    "alice@example.org",
+			password: "alice-pass",
+			wantFail: true,
+		},
+		{
+			name: "email attribute missing falls back to login email",
+			conn: func() *fakeConn {
+				f := withAlice(newFakeConn())
+				f.results["(mail=alice@example.org)"][0] = ldap.NewEntry(aliceDN, nil)
+				return f
+			},
+			email:    "alice@example.org",
+			password: "alice-pass",
+			want:     &Entry{DN: aliceDN, Email: "alice@example.org", Groups: []string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := tt.conn()
+			cfg := testConfig()
+			if tt.cfg != nil {
+				cfg = tt.cfg(cfg)
+			}
+			client := NewWithDialer(cfg, func() (Conn, error) { return conn, nil })
+
+			got, err := client.Authenticate(tt.email, tt.password)
+			switch {
+			case tt.wantFail:
+				if err == nil || errors.Is(err, ErrInvalidCredentials) {
+					t.Fatalf("err = %v, want a non-credential error", err)
+				}
+				return
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if got.DN != tt.want.DN || got.Email != tt.want.Email || len(got.Groups) != len(tt.want.Groups) {
+				t.Fatalf("entry = %+v, want %+v", got, tt.want)
+			}
+			for i := range got.Groups {
+				if got.Groups[i] != tt.want.Groups[i] {
+					t.Fatalf("groups = %v, want %v", got.Groups, tt.want.Groups)
+				}
+			}
+			// Akun layanan mencari lebih dulu, baru bind sebagai user
+			if !reflect.DeepEqual(conn.binds, []string{cfg.BindDN, aliceDN}) {
+				t.Fatalf("binds = %v, want service then user", conn.binds)
+			}
+			if !conn.closed {
+				t.Fatal("connection not closed")
+			}
+		})
+	}
+}
+
+func TestAuthenticateEmptyPasswordDoesNotDial(t *testing.T) {
+	client := NewWithDialer(testConfig(), func() (Conn, error) {
+		t.Fatal("dialed for empty password")
+		return nil, nil
+	})
+	if _, err := client.Authenticate("alice@example.org", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("err = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestAuthenticateGroupSearch(t *testing.T) {
+	conn := withAlice(newFakeConn())
+	groupFilter := "(member=" + ldap.EscapeFilter(aliceDN) + ")"
+	conn.results[groupFilter] = []*ldap.Entry{
+		ldap.NewEntry("cn=alumni,ou=groups,dc=example,dc=org", nil),
+	}
+
+	cfg := testConfig()
+	cfg.GroupFilter = "(member=%s)"
+	cfg.GroupBaseDN = "ou=groups,dc=example,dc=org"
+	client := NewWithDialer(cfg, func() (Conn, error) { return conn, nil })
+
+	entry, err := client.Authenticate("alice@example.org", "alice-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"cn=staff,ou=groups,dc=example,dc=org", "cn=alumni,ou=groups,dc=example,dc=org"}
+	if !reflect.DeepEqual(entry.Groups, want) {
+		t.Fatalf("groups = %v, want %v", entry.Groups, want)
+	}
+	if conn.bases[groupFilter] != cfg.GroupBaseDN {
+		t.Fatalf("group search base = %q, want %q", conn.bases[groupFilter], cfg.GroupBaseDN)
+	}
+}
+
+func TestGroupNames(t *testing.T) {
+	tests := []struct {
+		name string
+		dns  []string
+		want []string
+	}{
+		{"cn is added", []string{"cn=staff,ou=groups,dc=example,dc=org"}, []string{"cn=staff,ou=groups,dc=example,dc=org", "staff"}},
+		{"uppercase CN", []string{"CN=Domain Admins,CN=Users,DC=corp"}, []string{"CN=Domain Admins,CN=Users,DC=corp", "Domain Admins"}},
+		{"non-cn rdn keeps only dn", []string{"ou=staff,dc=example,dc=org"}, []string{"ou=staff,dc=example,dc=org"}},
+		{"unparseable dn kept as is", []string{"not a dn"}, []string{"not a dn"}},
+		{"empty", nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupNames(tt.dns); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("GroupNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// LogMailer tidak mengirim email sungguhan. Jika dir diisi, setiap email ditulis
+// sebagai file .eml di dir tersebut; jika kosong, email dicetak ke log.
+type LogMailer struct {
+	dir  string
+	from string
+	mu   sync.Mutex
+	seq  int
+}
+
+func NewLogMailer(dir, from string) (*LogMailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &LogMailer{dir: dir, from: from}, nil
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	content := buildMessage(m.from, msg)
+	if m.dir == "" {
+		log.Printf("[mailer] email to %v:\n%s", msg.To, content)
+		return nil
+	}
+
+	m.mu.Lock()
+	m.seq++
+	seq := m.seq
+	m.mu.Unlock()
+
+	recipient := ""
+	if len(msg.To) > 0 {
+		recipient = unsafeFileChars.ReplaceAllString(msg.To[0], "_")
+	}
+	name := fmt.Sprintf("%s-%04d-%s.eml", time.Now().Format("20060102T150405"), seq, recipient)
+	return os.WriteFile(filepath.Join(m.dir, name), content, 0o644)
+}
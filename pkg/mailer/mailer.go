@@ -0,0 +1,15 @@
+package mailer
+
+import "context"
+
+// Message adalah email teks sederhana yang dikirim oleh aplikasi
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer mengirim email; implementasinya bisa SMTP atau file/log untuk development dan testing
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,244 @@
+// Package mockidp adalah identity provider OpenID Connect sederhana untuk development dan pengujian
+// login SSO. Jangan dipakai di production: tidak ada password sama sekali, siapa pun bisa login
+// sebagai email dan grup apa pun lewat form di halaman /authorize.
+package mockidp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"back-train/pkg/jwtkeys"
+	"back-train/pkg/utils"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Config berisi pengaturan mock IdP; DefaultEmail dan DefaultGroups hanya mengisi form login
+type Config struct {
+	Issuer        string
+	ClientID      string
+	ClientSecret  string
+	DefaultEmail  string
+	DefaultGroups string
+}
+
+type authCode struct {
+	ClientID      string
+	RedirectURI   string
+	Nonce         string
+	CodeChallenge string
+	Email         string
+	Groups        []string
+	ExpiresAt     time.Time
+}
+
+// Server melayani endpoint discovery, /authorize, /token dan /jwks
+type Server struct {
+	cfg  Config
+	keys *jwtkeys.KeySet
+	mux  *http.ServeMux
+
+	mu    sync.Mutex
+	codes map[string]authCode
+}
+
+var authorizeForm = template.Must(template.New("authorize").Parse(`<!doctype html>
+<html><body>
+<h2>Mock IdP login</h2>
+<form method="post">
+  {{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{$v}}">{{end}}
+  <p><label>Email <input name="email" value="{{.Email}}"></label></p>
+  <p><label>Groups (comma separated) <input name="groups" value="{{.Groups}}"></label></p>
+  <p><label><input type="checkbox" name="email_verified" value="true" checked> email verified</label></p>
+  <button type="submit">Sign in</button>
+</form>
+</body></html>`))
+
+// New membuat server dengan kunci RSA baru; ID token yang diterbitkan hanya bisa diverifikasi lewat JWKS server ini
+func New(cfg Config) (*Server, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := jwtkeys.NewKeySet(&jwtkeys.Key{
+		ID:      "mockidp",
+		Method:  jwt.SigningMethodRS256,
+		Private: privateKey,
+		Public:  &privateKey.PublicKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Issuer = strings.TrimRight(cfg.Issuer, "/")
+	s := &Server{cfg: cfg, keys: keys, mux: http.NewServeMux(), codes: map[string]authCode{}}
+	s.mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	s.mux.HandleFunc("/authorize", s.authorize)
+	s.mux.HandleFunc("/token", s.token)
+	s.mux.HandleFunc("/jwks", s.jwks)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	issuer := s.cfg.Issuer
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	params := map[string]string{}
+	for _, name := range []string{"client_id", "redirect_uri", "state", "nonce", "code_challenge", "code_challenge_method", "response_type", "scope"} {
+		params[name] = r.Form.Get(name)
+	}
+	if params["client_id"] != s.cfg.ClientID || params["response_type"] != "code" {
+		http.Error(w, "unknown client or unsupported response_type", http.StatusBadRequest)
+		return
+	}
+	if params["code_challenge_method"] != "S256" || params["code_challenge"] == "" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		authorizeForm.Execute(w, map[string]interface{}{
+			"Params": params,
+			"Email":  s.cfg.DefaultEmail,
+			"Groups": s.cfg.DefaultGroups,
+		})
+		return
+	}
+
+	code, err := utils.GenerateRandomToken(24)
+	if err != nil {
+		http.Error(w, "could not create code", http.StatusInternalServerError)
+		return
+	}
+	var groups []string
+	for _, g := range strings.Split(r.Form.Get("groups"), ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+
+	email := r.Form.Get("email")
+	if r.Form.Get("email_verified") != "true" {
+		// Ditandai dengan prefix agar bisa diuji penolakan email yang belum terverifikasi
+		email = "unverified:" + email
+	}
+
+	s.mu.Lock()
+	s.codes[code] = authCode{
+		ClientID:      params["client_id"],
+		RedirectURI:   params["redirect_uri"],
+		Nonce:         params["nonce"],
+		CodeChallenge: params["code_challenge"],
+		Email:         email,
+		Groups:        groups,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	s.mu.Unlock()
+
+	redirect, err := url.Parse(params["redirect_uri"])
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	q := redirect.Query()
+	q.Set("code", code)
+	q.Set("state", params["state"])
+	redirect.RawQuery = q.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.Form.Get("client_id"), r.Form.Get("client_secret")
+	}
+	if clientID != s.cfg.ClientID || clientSecret != s.cfg.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	code, found := s.codes[r.Form.Get("code")]
+	delete(s.codes, r.Form.Get("code"))
+	s.mu.Unlock()
+
+	if !found || time.Now().After(code.ExpiresAt) || code.RedirectURI != r.Form.Get("redirect_uri") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != code.CodeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	email := strings.TrimPrefix(code.Email, "unverified:")
+	now := time.Now()
+	idToken, err := s.keys.Sign(jwt.MapClaims{
+		"iss":            s.cfg.Issuer,
+		"sub":            "mock|" + email,
+		"aud":            code.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          code.Nonce,
+		"email":          email,
+		"email_verified": email == code.Email,
+		"groups":         code.Groups,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	accessToken, _ := utils.GenerateRandomToken(24)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"keys": s.keys.JWKS()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,179 @@
+package passwordhash
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Parameter murah agar test cepat; validasi New tetap terpenuhi
+var testArgon2id = Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 16}
+
+func mustHasher(t *testing.T, cfg Config) *Hasher {
+	t.Helper()
+	h, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func argonConfig(p Argon2idParams) Config {
+	return Config{Algorithm: AlgorithmArgon2id, Argon2id: p}
+}
+
+func bcryptConfig(cost int) Config {
+	return Config{Algorithm: AlgorithmBcrypt, BcryptCost: cost}
+}
+
+func TestDecodeArgon2id(t *testing.T) {
+	// salt "saltsaltsaltsalt" dan key 4 byte, ditulis base64 tanpa padding
+	tests := []struct {
+		name    string
+		encoded string
+		want    Argon2idParams
+		wantErr bool
+	}{
+		{"valid", "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$AQIDBA",
+			Argon2idParams{Memory: 65536, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 4}, false},
+		{"wrong version", "$argon2id$v=16$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$AQIDBA", Argon2idParams{}, true},
+		{"missing version", "$argon2id$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$AQIDBA", Argon2idParams{}, true},
+		{"bad params", "$argon2id$v=19$m=x,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$AQIDBA", Argon2idParams{}, true},
+		{"padded salt", "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA==$AQIDBA", Argon2idParams{}, true},
+		{"bad key", "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$!!", Argon2idParams{}, true},
+		{"too few parts", "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA", Argon2idParams{}, true},
+		{"too many parts", "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$AQIDBA$extra", Argon2idParams{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, salt, key, err := decodeArgon2id(tt.encoded)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if params != tt.want || string(salt) != "saltsaltsaltsalt" || len(key) != 4 {
+				t.Fatalf("decode = %+v salt %q key %v, want %+v", params, salt, key, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashVerifyRoundTrip(t *testing.T) {
+	for _, cfg := range []Config{argonConfig(testArgon2id), bcryptConfig(bcrypt.MinCost)} {
+		t.Run(cfg.Algorithm, func(t *testing.T) {
+			h := mustHasher(t, cfg)
+			encoded, err := h.Hash("correct horse")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, err := h.Verify("correct horse", encoded); err != nil || !ok {
+				t.Fatalf("Verify(correct) = %v, %v", ok, err)
+			}
+			if ok, err := h.Verify("wrong horse", encoded); err != nil || ok {
+				t.Fatalf("Verify(wrong) = %v, %v", ok, err)
+			}
+			if h.NeedsRehash(encoded) {
+				t.Fatal("fresh hash needs rehash")
+			}
+
+			// Salt acak: dua hash password yang sama harus berbeda
+			again, _ := h.Hash("correct horse")
+			if again == encoded {
+				t.Fatal("hash is not salted")
+			}
+		})
+	}
+
+	h := mustHasher(t, argonConfig(testArgon2id))
+	encoded, _ := h.Hash("pw")
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("unexpected PHC string %s", encoded)
+	}
+}
+
+func TestVerifyAcrossConfigs(t *testing.T) {
+	// Hash lama dengan parameter atau algoritma lain tetap bisa diverifikasi oleh konfigurasi baru
+	oldArgon, _ := mustHasher(t, argonConfig(testArgon2id)).Hash("pw")
+	oldBcrypt, _ := mustHasher(t, bcryptConfig(bcrypt.MinCost)).Hash("pw")
+
+	stronger := testArgon2id
+	stronger.Iterations = 2
+	current := mustHasher(t, argonConfig(stronger))
+
+	for _, encoded := range []string{oldArgon, oldBcrypt} {
+		if ok, err := current.Verify("pw", encoded); err != nil || !ok {
+			t.Fatalf("Verify(%s) = %v, %v", encoded[:10], ok, err)
+		}
+	}
+
+	for _, encoded := range []string{"", "plain-text", "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$AQID"} {
+		if ok, err := current.Verify("pw", encoded); ok || !errors.Is(err, ErrUnknownFormat) {
+			t.Fatalf("Verify(%q) = %v, %v; want ErrUnknownFormat", encoded, ok, err)
+		}
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	argonHash, _ := mustHasher(t, argonConfig(testArgon2id)).Hash("pw")
+	bcryptHash, _ := mustHasher(t, bcryptConfig(bcrypt.MinCost)).Hash("pw")
+
+	with := func(change func(*Argon2idParams)) Config {
+		p := testArgon2id
+		change(&p)
+		return argonConfig(p)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		encoded string
+		want    bool
+	}{
+		{"same argon2id params", argonConfig(testArgon2id), argonHash, false},
+		{"memory changed", with(func(p *Argon2idParams) { p.Memory = 128 }), argonHash, true},
+		{"iterations changed", with(func(p *Argon2idParams) { p.Iterations = 2 }), argonHash, true},
+		{"parallelism changed", with(func(p *Argon2idParams) { p.Parallelism = 2 }), argonHash, true},
+		{"salt length changed", with(func(p *Argon2idParams) { p.SaltLength = 32 }), argonHash, true},
+		{"key length changed", with(func(p *Argon2idParams) { p.KeyLength = 32 }), argonHash, true},
+		{"bcrypt to argon2id", argonConfig(testArgon2id), bcryptHash, true},
+		{"argon2id to bcrypt", bcryptConfig(bcrypt.MinCost), argonHash, true},
+		{"same bcrypt cost", bcryptConfig(bcrypt.MinCost), bcryptHash, false},
+		{"bcrypt cost changed", bcryptConfig(bcrypt.MinCost + 1), bcryptHash, true},
+		{"corrupt argon2id", argonConfig(testArgon2id), "$argon2id$v=19$m=x$salt$key", true},
+		{"unknown format", argonConfig(testArgon2id), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustHasher(t, tt.cfg).NeedsRehash(tt.encoded); got != tt.want {
+				t.Fatalf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	bad := []Config{
+		{Algorithm: "md5"},
+		bcryptConfig(bcrypt.MinCost - 1),
+		bcryptConfig(bcrypt.MaxCost + 1),
+		argonConfig(Argon2idParams{Memory: 8, Iterations: 1, Parallelism: 2, SaltLength: 16, KeyLength: 16}),
+		argonConfig(Argon2idParams{Memory: 64, Iterations: 0, Parallelism: 1, SaltLength: 16, KeyLength: 16}),
+		argonConfig(Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 4, KeyLength: 16}),
+		argonConfig(Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 8}),
+	}
+	for _, cfg := range bad {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("New(%+v) accepted invalid config", cfg)
+		}
+	}
+	if _, err := New(DefaultConfig()); err != nil {
+		t.Fatalf("default config rejected: %v", err)
+	}
+}
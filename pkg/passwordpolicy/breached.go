@@ -0,0 +1,131 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Breached mencocokkan password dengan daftar hash SHA-1 password bocor format Pwned Passwords.
+// Password tidak pernah disimpan atau dikirim ke mana pun; yang dicari hanya hash-nya, dan untuk
+// direktori range hanya satu file kecil dengan 5 karakter awal hash yang sama yang dibuka (k-anonymity).
+//
+// Path bisa berupa:
+//   - direktori berisi file range bernama 5 karakter awal hash (misalnya 21BD1 atau 21BD1.txt)
+//     dengan baris "SISA_HASH:JUMLAH", seperti respons API range Pwned Passwords; atau
+//   - satu file berisi baris "HASH:JUMLAH" yang terurut berdasarkan hash, dicari dengan binary search.
+type Breached struct {
+	path  string
+	isDir bool
+}
+
+// OpenBreached memastikan path ada dan menentukan formatnya
+func OpenBreached(path string) (*Breached, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("passwordpolicy: breached list: %w", err)
+	}
+	return &Breached{path: path, isDir: info.IsDir()}, nil
+}
+
+// Contains melaporkan apakah password ada di daftar
+func (b *Breached) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	if b.isDir {
+		return b.searchRange(hash[:5], hash[5:])
+	}
+	return b.searchSorted(hash)
+}
+
+func (b *Breached) searchRange(prefix, suffix string) (bool, error) {
+	f, err := os.Open(filepath.Join(b.path, prefix))
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.Open(filepath.Join(b.path, prefix+".txt"))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		// Tidak ada file untuk prefix ini berarti tidak ada hash bocor dengan awalan tersebut
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if hashOf(scanner.Text()) == suffix {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// searchSorted melakukan binary search berdasarkan offset byte. lo selalu berada di awal baris,
+// dan baris yang dicari (jika ada) dimulai di rentang [lo, hi).
+func (b *Breached) searchSorted(hash string) (bool, error) {
+	f, err := os.Open(b.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	lo, hi := int64(0), info.Size()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start := lo
+		if mid > lo {
+			// Lompat ke awal baris pertama setelah mid
+			skipped, err := readLine(f, mid-1, info.Size())
+			if err != nil {
+				return false, err
+			}
+			start = mid - 1 + int64(len(skipped))
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		line, err := readLine(f, start, info.Size())
+		if err != nil {
+			return false, err
+		}
+		switch candidate := hashOf(line); {
+		case candidate == hash:
+			return true, nil
+		case candidate < hash:
+			lo = start + int64(len(line))
+		default:
+			hi = start
+		}
+	}
+	return false, nil
+}
+
+// readLine membaca satu baris mulai dari offset, termasuk karakter newline-nya
+func readLine(f *os.File, offset, size int64) (string, error) {
+	reader := bufio.NewReader(io.NewSectionReader(f, offset, size-offset))
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return line, nil
+}
+
+// hashOf mengambil bagian hash dari baris "HASH:JUMLAH"
+func hashOf(line string) string {
+	hash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+	return strings.ToUpper(hash)
+}
@@ -0,0 +1,118 @@
+package passwordpolicy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// writeSorted menulis daftar "HASH:JUMLAH" terurut dengan pemisah baris newline
+func writeSorted(t *testing.T, passwords []string, newline string, trailing bool) string {
+	t.Helper()
+	lines := make([]string, len(passwords))
+	for i, p := range passwords {
+		lines[i] = fmt.Sprintf("%s:%d", sha1Hex(p), i+1)
+	}
+	sort.Strings(lines)
+	content := strings.Join(lines, newline)
+	if trailing && len(lines) > 0 {
+		content += newline
+	}
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBreachedSortedFile(t *testing.T) {
+	var leaked []string
+	for i := 0; i < 500; i++ {
+		leaked = append(leaked, fmt.Sprintf("leaked-%d", i))
+	}
+	notLeaked := []string{"", "leaked-500", "correct horse battery staple", "Leaked-1"}
+
+	tests := []struct {
+		name      string
+		passwords []string
+		newline   string
+		trailing  bool
+	}{
+		{"many lines", leaked, "\n", true},
+		{"crlf line endings", leaked, "\r\n", true},
+		{"no trailing newline", leaked, "\n", false},
+		{"single line", leaked[:1], "\n", false},
+		{"two lines", leaked[:2], "\n", true},
+		{"empty file", nil, "\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := OpenBreached(writeSorted(t, tt.passwords, tt.newline, tt.trailing))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Setiap baris harus bisa ditemukan, termasuk baris pertama dan terakhir
+			for _, p := range tt.passwords {
+				if found, err := b.Contains(p); err != nil || !found {
+					t.Fatalf("Contains(%q) = %v, %v; want true", p, found, err)
+				}
+			}
+			for _, p := range notLeaked {
+				if found, err := b.Contains(p); err != nil || found {
+					t.Fatalf("Contains(%q) = %v, %v; want false", p, found, err)
+				}
+			}
+		})
+	}
+}
+
+func TestBreachedRangeDirectory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, passwords ...string) {
+		var b strings.Builder
+		for _, p := range passwords {
+			// Format respons API range: sisa hash huruf besar, lalu jumlah
+			fmt.Fprintf(&b, "%s:%d\r\n", sha1Hex(p)[5:], 3)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(sha1Hex("password")[:5], "password")
+	write(sha1Hex("123456")[:5]+".txt", "123456")
+
+	b, err := OpenBreached(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"password", true},
+		{"123456", true},                 // file dengan ekstensi .txt
+		{"Password", false},              // prefix tanpa file
+		{"not-in-any-range-file", false}, // prefix tanpa file
+	}
+	for _, tt := range tests {
+		if got, err := b.Contains(tt.password); err != nil || got != tt.want {
+			t.Errorf("Contains(%q) = %v, %v; want %v", tt.password, got, err, tt.want)
+		}
+	}
+}
+
+func TestOpenBreachedMissing(t *testing.T) {
+	if _, err := OpenBreached(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("expected error for missing path")
+	}
+}
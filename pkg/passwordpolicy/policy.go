@@ -0,0 +1,113 @@
+// Package passwordpolicy memeriksa password baru terhadap aturan panjang, jenis karakter,
+// kemiripan dengan email, dan daftar password yang pernah bocor.
+package passwordpolicy
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kode aturan yang dikembalikan di Violation.Rule, stabil untuk dipakai frontend
+const (
+	RuleMinLength     = "min_length"
+	RuleMaxLength     = "max_length"
+	RuleUppercase     = "uppercase"
+	RuleLowercase     = "lowercase"
+	RuleDigit         = "digit"
+	RuleSymbol        = "symbol"
+	RuleContainsEmail = "contains_email"
+	RuleBreached      = "breached"
+)
+
+// Violation adalah satu aturan yang tidak dipenuhi
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Policy berisi aturan password; nilai nol berarti aturan tersebut tidak diperiksa
+type Policy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	RejectEmail   bool      // tolak password yang memuat email atau bagian sebelum @
+	BreachedList  *Breached // nil jika pengecekan password bocor dinonaktifkan
+}
+
+// Validate mengembalikan semua aturan yang dilanggar, bukan hanya yang pertama,
+// agar user bisa memperbaiki password dalam satu kali percobaan
+func (p *Policy) Validate(password, email string) ([]Violation, error) {
+	violations := []Violation{}
+	add := func(rule, format string, args ...interface{}) {
+		violations = append(violations, Violation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		add(RuleMinLength, "must be at least %d characters long", p.MinLength)
+	}
+	// Batas dalam byte karena bcrypt hanya memakai 72 byte pertama
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		add(RuleMaxLength, "must be at most %d bytes long", p.MaxLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		add(RuleUppercase, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		add(RuleLowercase, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		add(RuleDigit, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add(RuleSymbol, "must contain a symbol")
+	}
+
+	if p.RejectEmail && containsEmail(password, email) {
+		add(RuleContainsEmail, "must not contain your email address")
+	}
+
+	if p.BreachedList != nil && password != "" {
+		breached, err := p.BreachedList.Contains(password)
+		if err != nil {
+			return nil, err
+		}
+		if breached {
+			add(RuleBreached, "appears in a list of leaked passwords, choose a different one")
+		}
+	}
+	return violations, nil
+}
+
+// containsEmail memeriksa email lengkap dan bagian sebelum @ (minimal 3 karakter) tanpa membedakan huruf besar
+func containsEmail(password, email string) bool {
+	password = strings.ToLower(password)
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+	if strings.Contains(password, email) {
+		return true
+	}
+	local, _, _ := strings.Cut(email, "@")
+	return utf8.RuneCountInString(local) >= 3 && strings.Contains(password, local)
+}
@@ -0,0 +1,84 @@
+package passwordpolicy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func rules(violations []Violation) []string {
+	out := []string{}
+	for _, v := range violations {
+		out = append(out, v.Rule)
+	}
+	return out
+}
+
+func TestValidate(t *testing.T) {
+	strict := &Policy{
+		MinLength:     10,
+		MaxLength:     72,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		RejectEmail:   true,
+	}
+
+	tests := []struct {
+		name     string
+		policy   *Policy
+		password string
+		email    string
+		want     []string
+	}{
+		{"strong password", strict, "Tr0ub4dor&Horse", "alice@example.com", []string{}},
+		{"every rule reported", strict, "", "alice@example.com", []string{RuleMinLength, RuleUppercase, RuleLowercase, RuleDigit, RuleSymbol}},
+		{"length counts runes", &Policy{MinLength: 4}, "ééé", "", []string{RuleMinLength}},
+		{"multibyte meets min length", &Policy{MinLength: 3}, "ééé", "", []string{}},
+		{"max length in bytes", &Policy{MaxLength: 5}, "ééé", "", []string{RuleMaxLength}},
+		{"space counts as symbol", &Policy{RequireSymbol: true}, "two words", "", []string{}},
+		{"contains full email", strict, "Xalice@example.com1", "Alice@Example.com", []string{RuleContainsEmail}},
+		{"contains local part", strict, "My-ALICE-pass1", "alice@example.com", []string{RuleContainsEmail}},
+		{"short local part ignored", strict, "Bob-is-great1!", "bo@example.com", []string{}},
+		{"email check disabled", &Policy{}, "alice@example.com", "alice@example.com", []string{}},
+		{"empty email", strict, "Tr0ub4dor&Horse", "", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := tt.policy.Validate(tt.password, tt.email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rules(violations); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBreached(t *testing.T) {
+	list, err := OpenBreached(writeSorted(t, []string{"Summer2024!", "P@ssw0rd123"}, "\n", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Policy{MinLength: 8, BreachedList: list}
+
+	tests := []struct {
+		password string
+		want     []string
+	}{
+		{"Summer2024!", []string{RuleBreached}},
+		{"P@ssw0rd123", []string{RuleBreached}},
+		{"unique-enough-passphrase", []string{}},
+		{"", []string{RuleMinLength}}, // password kosong tidak dicari di daftar
+	}
+	for _, tt := range tests {
+		violations, err := p.Validate(tt.password, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rules(violations); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Validate(%q) rules = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
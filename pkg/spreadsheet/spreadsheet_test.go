@@ -0,0 +1,206 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatFromFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+		wantErr  bool
+	}{
+		{"alumni.csv", FormatCSV, false},
+		{"ALUMNI.CSV", FormatCSV, false},
+		{"data/alumni.xlsx", FormatXLSX, false},
+		{"alumni.Xlsx", FormatXLSX, false},
+		{"alumni.xls", "", true},
+		{"alumni.pdf", "", true}, // pdf hanya untuk export
+		{"alumni", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, err := FormatFromFilename(tt.filename)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedFormat) {
+					t.Fatalf("err = %v, want ErrUnsupportedFormat", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("format = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadAllCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  [][]string
+	}{
+		{
+			name:  "comma separated",
+			input: "nim,nama\n1001,Budi\n",
+			want:  [][]string{{"nim", "nama"}, {"1001", "Budi"}},
+		},
+		{
+			name:  "semicolon separated",
+			input: "nim;nama;alamat\n1001;Budi;Jl. Mawar, Bandung\n",
+			want:  [][]string{{"nim", "nama", "alamat"}, {"1001", "Budi", "Jl. Mawar, Bandung"}},
+		},
+		{
+			name:  "comma wins when it is more common in the header",
+			input: "nim,nama,catatan\n1001,Budi,a;b\n",
+			want:  [][]string{{"nim", "nama", "catatan"}, {"1001", "Budi", "a;b"}},
+		},
+		{
+			name:  "utf-8 BOM stripped",
+			input: "\ufeffnim,nama\n1001,Budi\n",
+			want:  [][]string{{"nim", "nama"}, {"1001", "Budi"}},
+		},
+		{
+			name:  "ragged rows and leading spaces",
+			input: "nim, nama\n1001\n",
+			want:  [][]string{{"nim", "nama"}, {"1001"}},
+		},
+		{
+			name:  "no trailing newline",
+			input: "nim,nama",
+			want:  [][]string{{"nim", "nama"}},
+		},
+		{
+			name:  "empty file",
+			input: "",
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadAll(FormatCSV, strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadAllErrors(t *testing.T) {
+	if _, err := ReadAll(FormatPDF, strings.NewReader("")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("pdf: err = %v, want ErrUnsupportedFormat", err)
+	}
+	if _, err := ReadAll(FormatCSV, strings.NewReader("nim\n\"1001\n")); err == nil {
+		t.Error("csv with unterminated quote: expected error")
+	}
+	if _, err := ReadAll(FormatXLSX, strings.NewReader("not a zip file")); err == nil {
+		t.Error("invalid xlsx: expected error")
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"nim", "nama", "alamat"},
+		{"123456789012345678", "Budi", "Jl. Mawar, Bandung"},
+		{"1002", "Ani \"A\"", ""},
+	}
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(format, &buf, "Data Alumni")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range rows {
+				if err := w.Write(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadAll(format, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// XLSX tidak menyimpan sel kosong di akhir baris
+			want := rows
+			if format == FormatXLSX {
+				want = [][]string{rows[0], rows[1], {"1002", "Ani \"A\""}}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("rows = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPDFWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]string
+	}{
+		{"empty", nil},
+		{"header only", [][]string{{"nim", "nama"}}},
+		{"many pages and long text", append([][]string{{"nim", "nama"}}, repeatRows([]string{"1001", strings.Repeat("panjang ", 50)}, 200)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(FormatPDF, &buf, "Data Alumni")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range tt.rows {
+				if err := w.Write(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+				t.Fatalf("output does not start with a PDF header: %q", buf.Bytes()[:min(buf.Len(), 16)])
+			}
+		})
+	}
+}
+
+func repeatRows(row []string, n int) [][]string {
+	rows := make([][]string, n)
+	for i := range rows {
+		rows[i] = row
+	}
+	return rows
+}
+
+func TestNewWriterUnsupported(t *testing.T) {
+	if _, err := NewWriter("ods", &bytes.Buffer{}, ""); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("err = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestContentType(t *testing.T) {
+	tests := map[string]string{
+		FormatCSV:  "text/csv; charset=utf-8",
+		FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		FormatPDF:  "application/pdf",
+		"ods":      "application/octet-stream",
+	}
+	for format, want := range tests {
+		if got := ContentType(format); got != want {
+			t.Errorf("ContentType(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
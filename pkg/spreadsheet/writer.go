@@ -0,0 +1,191 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer menulis tabel baris demi baris. Baris pertama yang ditulis adalah header.
+// Close wajib dipanggil untuk menyelesaikan file; untuk CSV data sudah dikirim ke w selama Write.
+type Writer interface {
+	Write(row []string) error
+	Close() error
+}
+
+// NewWriter membuat writer untuk format csv, xlsx atau pdf; title dipakai sebagai nama sheet/judul PDF
+func NewWriter(format string, w io.Writer, title string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, title)
+	case FormatPDF:
+		return newPDFWriter(w, title), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType mengembalikan MIME type untuk header response
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// csvFlushRows adalah jumlah baris yang ditahan sebelum dikirim ke client
+const csvFlushRows = 500
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func (c *csvWriter) Write(row []string) error {
+	if err := c.w.Write(row); err != nil {
+		return err
+	}
+	c.rows++
+	if c.rows%csvFlushRows == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxWriter memakai stream writer excelize yang menyimpan baris ke file sementara, bukan ke memori
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	rows   int
+}
+
+func newXLSXWriter(w io.Writer, title string) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	sheet := "Sheet1"
+	if title != "" {
+		if len(title) > 31 { // batas panjang nama sheet Excel
+			title = title[:31]
+		}
+		if err := f.SetSheetName(sheet, title); err != nil {
+			return nil, err
+		}
+		sheet = title
+	}
+	stream, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: f, stream: stream}, nil
+}
+
+func (x *xlsxWriter) Write(row []string) error {
+	x.rows++
+	cell, err := excelize.CoordinatesToCellName(1, x.rows)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(row))
+	for i, v := range row {
+		values[i] = v
+	}
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
+
+// pdfWriter membuat tabel sederhana di halaman A4 landscape; header kolom diulang di setiap halaman.
+// Lebar kolom dibagi rata dan teks yang terlalu panjang dipotong.
+type pdfWriter struct {
+	out       io.Writer
+	pdf       *gofpdf.Fpdf
+	translate func(string) string
+	header    []string
+	colWidth  float64
+}
+
+const pdfRowHeight = 6
+
+func newPDFWriter(w io.Writer, title string) *pdfWriter {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 10)
+	p := &pdfWriter{out: w, pdf: pdf, translate: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetHeaderFuncMode(func() {
+		if pdf.PageNo() == 1 && title != "" {
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.CellFormat(0, 8, p.translate(title), "", 1, "L", false, 0, "")
+		}
+		p.writeRow(p.header, true)
+	}, true)
+	return p
+}
+
+func (p *pdfWriter) Write(row []string) error {
+	if p.header == nil {
+		p.header = row
+		pageWidth, _ := p.pdf.GetPageSize()
+		left, _, right, _ := p.pdf.GetMargins()
+		p.colWidth = (pageWidth - left - right) / float64(max(len(row), 1))
+		p.pdf.AddPage()
+		return p.pdf.Error()
+	}
+	p.writeRow(row, false)
+	return p.pdf.Error()
+}
+
+func (p *pdfWriter) writeRow(row []string, bold bool) {
+	if bold {
+		p.pdf.SetFont("Helvetica", "B", 8)
+		p.pdf.SetFillColor(230, 230, 230)
+	} else {
+		p.pdf.SetFont("Helvetica", "", 8)
+	}
+	for _, v := range row {
+		p.pdf.CellFormat(p.colWidth, pdfRowHeight, p.fit(v), "1", 0, "L", bold, 0, "")
+	}
+	p.pdf.Ln(pdfRowHeight)
+}
+
+// fit memotong teks agar muat di satu sel
+func (p *pdfWriter) fit(text string) string {
+	text = p.translate(text)
+	maxWidth := p.colWidth - 2
+	if p.pdf.GetStringWidth(text) <= maxWidth {
+		return text
+	}
+	for len(text) > 0 && p.pdf.GetStringWidth(text+"...") > maxWidth {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
+
+func (p *pdfWriter) Close() error {
+	if p.header == nil {
+		p.pdf.AddPage()
+	}
+	return p.pdf.Output(p.out)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// SignToken membuat token "payload.signature" (base64url) yang ditandatangani HMAC-SHA256
+func SignToken(key []byte, payload []byte) string {
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(key, encoded)
+}
+
+// VerifySignedToken memeriksa tanda tangan token dari SignToken dan mengembalikan payload aslinya
+func VerifySignedToken(key []byte, token string) ([]byte, bool) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, false
+	}
+	if !hmac.Equal([]byte(signature), []byte(sign(key, encoded))) {
+		return nil, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false
+	}
+	return payload, true
+}
+
+func sign(key []byte, data string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}